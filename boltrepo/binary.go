@@ -2,6 +2,7 @@ package boltrepo
 
 import (
 	"encoding/binary"
+	"time"
 
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/commit"
@@ -51,6 +52,31 @@ func makeVersionKey(version resource.Version) (key [8]byte) {
 	return
 }
 
+// makeTimeKey returns an 8 byte big-endian binary representation of a
+// point in time, measured in nanoseconds since the unix epoch. Times
+// before the epoch are clamped to it so that they sort before every
+// later time instead of wrapping around to the end.
+func makeTimeKey(t time.Time) (key [8]byte) {
+	var n uint64
+	if t.After(time.Unix(0, 0)) {
+		n = uint64(t.UnixNano())
+	}
+	binary.BigEndian.PutUint64(key[:], n)
+	return
+}
+
+// makeActorKey returns a binary representation of an actor entry that
+// sorts chronologically. It is composed of an 8 byte time key, followed by
+// an 8 byte commit key, followed by the file ID.
+func makeActorKey(t time.Time, seqNum commit.SeqNum, file resource.ID) []byte {
+	timeKey := makeTimeKey(t)
+	commitKey := makeCommitKey(seqNum)
+	key := make([]byte, 0, len(timeKey)+len(commitKey)+len(file))
+	key = append(key, timeKey[:]...)
+	key = append(key, commitKey[:]...)
+	return append(key, file...)
+}
+
 // makeBool returns a single byte binary representation of a boolean.
 func makeBool(value bool) [1]byte {
 	if value {
@@ -58,3 +84,10 @@ func makeBool(value bool) [1]byte {
 	}
 	return [1]byte{0}
 }
+
+// makeSchemaValue returns an 8 byte big-endian binary representation of
+// a schema version.
+func makeSchemaValue(version SchemaVersion) (value [8]byte) {
+	binary.BigEndian.PutUint64(value[:], uint64(version))
+	return
+}
@@ -125,6 +125,42 @@ func createDriveViewBucket(tx *bolt.Tx, driveID resource.ID) (*bolt.Bucket, erro
 	return drv.CreateBucketIfNotExists([]byte(ViewBucket))
 }
 
+// driveActorsBucket returns the actors bucket of the drive.
+func driveActorsBucket(tx *bolt.Tx, driveID resource.ID) *bolt.Bucket {
+	drv := driveBucket(tx, driveID)
+	if drv == nil {
+		return nil
+	}
+	return drv.Bucket([]byte(ActorBucket))
+}
+
+// createDriveActorsBucket creates the actors bucket for the drive.
+func createDriveActorsBucket(tx *bolt.Tx, driveID resource.ID) (*bolt.Bucket, error) {
+	drv, err := createDriveBucket(tx, driveID)
+	if err != nil {
+		return nil, err
+	}
+	return drv.CreateBucketIfNotExists([]byte(ActorBucket))
+}
+
+// driveConsumersBucket returns the consumers bucket of the drive.
+func driveConsumersBucket(tx *bolt.Tx, driveID resource.ID) *bolt.Bucket {
+	drv := driveBucket(tx, driveID)
+	if drv == nil {
+		return nil
+	}
+	return drv.Bucket([]byte(ConsumerBucket))
+}
+
+// createDriveConsumersBucket creates the consumers bucket for the drive.
+func createDriveConsumersBucket(tx *bolt.Tx, driveID resource.ID) (*bolt.Bucket, error) {
+	drv, err := createDriveBucket(tx, driveID)
+	if err != nil {
+		return nil, err
+	}
+	return drv.CreateBucketIfNotExists([]byte(ConsumerBucket))
+}
+
 // filesBucket returns the files bucket.
 func filesBucket(tx *bolt.Tx) *bolt.Bucket {
 	root := tx.Bucket([]byte(RootBucket))
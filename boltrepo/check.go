@@ -0,0 +1,527 @@
+package boltrepo
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Problem describes an inconsistency found within a bolt repository.
+type Problem struct {
+	// Err is a typed error describing the problem.
+	Err error
+
+	// Repairable is true if the problem can be fixed by Repair without
+	// losing any data that is reachable through the repository.
+	Repairable bool
+
+	bucket [][]byte // Path of the bucket holding the offending key
+	key    []byte   // Offending key, which Repair removes
+}
+
+// Error returns a string representation of the problem.
+func (p Problem) Error() string {
+	return p.Err.Error()
+}
+
+// Check walks the repository looking for corruption and returns the
+// problems it finds. The walk is performed within a single read-only
+// transaction, so it sees a consistent view of the database.
+//
+// Check reports malformed keys, gaps in collection, commit, state and page
+// sequences, page token chains that are broken, commits with a source that
+// does not exist, actor entries that can't be decoded, file views that
+// refer to missing file versions, and file versions that were collected
+// with data that conflicts with their stored copy.
+func (repo Repository) Check() (problems []Problem, err error) {
+	err = repo.db.View(func(tx *bolt.Tx) error {
+		c := checker{tx: tx}
+		c.check()
+		problems = c.problems
+		return nil
+	})
+	return problems, err
+}
+
+// Repair fixes the repairable problems in the given list, which should
+// have been returned by a recent call to Check. It returns the problems
+// that were repaired.
+//
+// Repair only removes malformed keys. Such keys cannot be reached through
+// the repository, but they prevent the sequences that contain them from
+// growing. All other problems are left in place for an operator to
+// examine.
+func (repo Repository) Repair(problems []Problem) (repaired []Problem, err error) {
+	err = repo.db.Update(func(tx *bolt.Tx) error {
+		for _, p := range problems {
+			if !p.Repairable {
+				continue
+			}
+			bucket := tx.Bucket(p.bucket[0])
+			for i := 1; bucket != nil && i < len(p.bucket); i++ {
+				bucket = bucket.Bucket(p.bucket[i])
+			}
+			if bucket == nil {
+				continue // Already removed along with its parent
+			}
+			if bucket.Bucket(p.key) != nil {
+				err = bucket.DeleteBucket(p.key)
+			} else if bucket.Get(p.key) != nil {
+				err = bucket.Delete(p.key)
+			} else {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			repaired = append(repaired, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repaired, nil
+}
+
+// checker accumulates problems found while walking a bolt transaction.
+type checker struct {
+	tx        *bolt.Tx
+	problems  []Problem
+	conflicts map[fileversion.Conflict]bool
+}
+
+// report records a problem that cannot be repaired.
+func (c *checker) report(err error) {
+	c.problems = append(c.problems, Problem{Err: err})
+}
+
+// reportKey records a malformed key that can be removed by Repair.
+func (c *checker) reportKey(err error, path [][]byte, key []byte) {
+	c.problems = append(c.problems, Problem{
+		Err:        err,
+		Repairable: true,
+		bucket:     copyPath(path),
+		key:        append(key[:0:0], key...),
+	})
+}
+
+// copyPath returns a deep copy of path. Keys provided by bolt are only
+// valid for the life of the transaction.
+func copyPath(path [][]byte) [][]byte {
+	result := make([][]byte, len(path))
+	for i := range path {
+		result[i] = append(path[i][:0:0], path[i]...)
+	}
+	return result
+}
+
+func (c *checker) check() {
+	root := c.tx.Bucket([]byte(RootBucket))
+	if root == nil {
+		return
+	}
+	if _, err := schemaVersion(c.tx); err != nil {
+		c.report(err)
+	}
+
+	versions := make(map[resource.ID]map[resource.Version]bool)
+
+	if files := root.Bucket([]byte(FileBucket)); files != nil {
+		files.ForEach(func(k, v []byte) error {
+			if v == nil {
+				versions[resource.ID(k)] = c.fileVersions(resource.ID(k), files.Bucket(k))
+			}
+			return nil
+		})
+	}
+
+	if drives := root.Bucket([]byte(DriveBucket)); drives != nil {
+		drives.ForEach(func(k, v []byte) error {
+			if v == nil {
+				c.drive(resource.ID(k), drives.Bucket(k))
+			}
+			return nil
+		})
+	}
+
+	if files := root.Bucket([]byte(FileBucket)); files != nil {
+		files.ForEach(func(k, v []byte) error {
+			if v == nil {
+				c.fileViews(resource.ID(k), files.Bucket(k), versions[resource.ID(k)])
+			}
+			return nil
+		})
+	}
+}
+
+func (c *checker) drive(driveID resource.ID, drv *bolt.Bucket) {
+	pages := c.collections(driveID, drv.Bucket([]byte(CollectionBucket)))
+	c.commits(driveID, drv.Bucket([]byte(CommitBucket)), pages)
+
+	if versions := drv.Bucket([]byte(VersionBucket)); versions != nil {
+		path := [][]byte{[]byte(RootBucket), []byte(DriveBucket), []byte(driveID), []byte(VersionBucket)}
+		versions.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				c.reportKey(BadDriveVersionKey{Drive: driveID, BadKey: append(k[:0:0], k...)}, path, k)
+			}
+			return nil
+		})
+	}
+
+	if view := drv.Bucket([]byte(ViewBucket)); view != nil {
+		path := [][]byte{[]byte(RootBucket), []byte(DriveBucket), []byte(driveID), []byte(ViewBucket)}
+		view.ForEach(func(k, v []byte) error {
+			switch {
+			case len(k) != 8:
+				c.reportKey(BadDriveViewKey{Drive: driveID, BadKey: append(k[:0:0], k...)}, path, k)
+			case len(v) != 8:
+				c.report(BadDriveViewValue{Drive: driveID, Commit: commit.SeqNum(binary.BigEndian.Uint64(k)), BadValue: append(v[:0:0], v...)})
+			}
+			return nil
+		})
+	}
+
+	if actors := drv.Bucket([]byte(ActorBucket)); actors != nil {
+		c.actors(driveID, actors)
+	}
+
+	if consumers := drv.Bucket([]byte(ConsumerBucket)); consumers != nil {
+		consumers.ForEach(func(k, v []byte) error {
+			var data consumer.Data
+			if v == nil || decodeValue(v, &data) != nil {
+				c.report(consumer.InvalidData{Drive: driveID, Name: string(k)})
+			}
+			return nil
+		})
+	}
+}
+
+// actors checks the actor buckets of a drive. Each actor bucket is named
+// by a hash of the actor's email address.
+func (c *checker) actors(driveID resource.ID, actors *bolt.Bucket) {
+	path := [][]byte{[]byte(RootBucket), []byte(DriveBucket), []byte(driveID), []byte(ActorBucket)}
+	actors.ForEach(func(k, v []byte) error {
+		if len(k) != codec.HashSize || v != nil {
+			c.reportKey(BadDriveActorBucket{Drive: driveID, BadKey: append(k[:0:0], k...)}, path, k)
+			return nil
+		}
+		name := append(k[:0:0], k...)
+		actorPath := append(path[:len(path):len(path)], k)
+		var email string
+		actors.Bucket(k).ForEach(func(k, v []byte) error {
+			if len(k) < 16 || v == nil {
+				c.reportKey(BadDriveActorKey{Drive: driveID, Actor: name, BadKey: append(k[:0:0], k...)}, actorPath, k)
+				return nil
+			}
+			var actor resource.UserData
+			if err := decodeValue(v, &actor); err != nil {
+				c.report(BadDriveActorValue{Drive: driveID, Actor: name, Err: err})
+				return nil
+			}
+			if email == "" {
+				email = actor.EmailAddress
+			} else if actor.EmailAddress != email {
+				c.report(BadDriveActorValue{Drive: driveID, Actor: name, Err: fmt.Errorf("the bucket holds entries for both %s and %s", email, actor.EmailAddress)})
+			}
+			return nil
+		})
+		return nil
+	})
+}
+
+// collectionPages holds the number of changes in each page of a
+// collection, for use when checking commit sources.
+type collectionPages struct {
+	Type    collection.Type
+	Pruned  bool
+	Changes map[page.SeqNum]int
+}
+
+func (c *checker) collections(driveID resource.ID, collections *bolt.Bucket) map[collection.SeqNum]collectionPages {
+	found := make(map[collection.SeqNum]collectionPages)
+	if collections == nil {
+		return found
+	}
+
+	path := [][]byte{[]byte(RootBucket), []byte(DriveBucket), []byte(driveID), []byte(CollectionBucket)}
+	var expected collection.SeqNum
+	collections.ForEach(func(k, v []byte) error {
+		if len(k) != 8 || v != nil {
+			c.reportKey(BadCollectionKey{Drive: driveID, BadKey: append(k[:0:0], k...)}, path, k)
+			return nil
+		}
+		seqNum := collection.SeqNum(binary.BigEndian.Uint64(k))
+		if seqNum != expected {
+			c.report(CollectionGap{Drive: driveID, Collection: seqNum, Expected: expected})
+		}
+		expected = seqNum + 1
+		found[seqNum] = c.collection(driveID, seqNum, collections.Bucket(k), append(path[:len(path):len(path)], k))
+		return nil
+	})
+	return found
+}
+
+func (c *checker) collection(driveID resource.ID, seqNum collection.SeqNum, col *bolt.Bucket, path [][]byte) collectionPages {
+	var data collection.Data
+	if value := col.Get([]byte(DataKey)); value == nil || decodeValue(value, &data) != nil {
+		c.report(collection.DataInvalid{Drive: driveID, Collection: seqNum})
+	}
+	result := collectionPages{
+		Type:    data.Type,
+		Pruned:  col.Get([]byte(PrunedKey)) != nil,
+		Changes: make(map[page.SeqNum]int),
+	}
+
+	if states := col.Bucket([]byte(StateBucket)); states != nil {
+		statesPath := append(path[:len(path):len(path)], []byte(StateBucket))
+		var expected collection.StateNum
+		states.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				c.reportKey(BadCollectionStateKey{Drive: driveID, Collection: seqNum, BadKey: append(k[:0:0], k...)}, statesPath, k)
+				return nil
+			}
+			state := collection.StateNum(binary.BigEndian.Uint64(k))
+			if state != expected {
+				c.report(CollectionStateGap{Drive: driveID, Collection: seqNum, State: state, Expected: expected})
+			}
+			expected = state + 1
+			var s collection.State
+			if v == nil || decodeValue(v, &s) != nil {
+				c.report(collection.StateInvalid{Drive: driveID, Collection: seqNum, State: state})
+			}
+			return nil
+		})
+	}
+
+	pages := col.Bucket([]byte(PageBucket))
+	if pages == nil {
+		return result
+	}
+	pagesPath := append(path[:len(path):len(path)], []byte(PageBucket))
+	var (
+		expected page.SeqNum
+		previous *page.Data
+	)
+	pages.ForEach(func(k, v []byte) error {
+		if len(k) != 8 {
+			c.reportKey(BadPageKey{Drive: driveID, Collection: seqNum, BadKey: append(k[:0:0], k...)}, pagesPath, k)
+			return nil
+		}
+		pageNum := page.SeqNum(binary.BigEndian.Uint64(k))
+		if pageNum != expected {
+			c.report(PageGap{Drive: driveID, Collection: seqNum, Page: pageNum, Expected: expected})
+			previous = nil // The chain can't be followed across the gap
+		}
+		expected = pageNum + 1
+
+		var pg page.Data
+		if v == nil || decodeValue(v, &pg) != nil {
+			c.report(collection.PageDataInvalid{Drive: driveID, Collection: seqNum, Page: pageNum})
+			previous = nil
+			return nil
+		}
+		result.Changes[pageNum] = len(pg.Changes)
+		c.pageVersions(driveID, &pg)
+
+		if token, ok := expectedPageToken(data, previous, &pg); !ok {
+			c.report(BrokenPageChain{Drive: driveID, Collection: seqNum, Page: pageNum, Expected: token, Token: pg.PageToken})
+		}
+		previous = &pg
+		return nil
+	})
+
+	return result
+}
+
+// pageVersions checks the file versions collected within a page against
+// their stored copies. Each version is stored once, with the data it was
+// first collected with, so a page holds a different copy when the same
+// version was collected through more than one drive. Copies are only
+// reported when they differ in properties of the version itself.
+func (c *checker) pageVersions(driveID resource.ID, pg *page.Data) {
+	files := c.tx.Bucket([]byte(RootBucket)).Bucket([]byte(FileBucket))
+	if files == nil {
+		return
+	}
+	for _, change := range pg.Changes {
+		if change.Type != resource.TypeFile || change.Removed {
+			continue
+		}
+		file := files.Bucket([]byte(change.File.ID))
+		if file == nil {
+			continue
+		}
+		versions := file.Bucket([]byte(VersionBucket))
+		if versions == nil {
+			continue
+		}
+		key := makeVersionKey(change.File.Version)
+		value := versions.Get(key[:])
+		if value == nil {
+			continue // The page hasn't been committed yet
+		}
+		var stored resource.FileData
+		if decodeValue(value, &stored) != nil || fileversion.SameVersion(stored, change.File.FileData) {
+			continue
+		}
+		conflict := fileversion.Conflict{File: change.File.ID, Version: change.File.Version, Drive: driveID}
+		if c.conflicts[conflict] {
+			continue
+		}
+		if c.conflicts == nil {
+			c.conflicts = make(map[fileversion.Conflict]bool)
+		}
+		c.conflicts[conflict] = true
+		c.report(conflict)
+	}
+}
+
+// expectedPageToken returns the page token that pg should have, given the
+// page that precedes it within a collection. It returns false if the page
+// token of pg does not match. If previous is nil and pg is not the first
+// page of its type the chain can't be verified and true is returned.
+func expectedPageToken(data collection.Data, previous, pg *page.Data) (token string, ok bool) {
+	if previous == nil {
+		return pg.PageToken, true
+	}
+	if previous.Type == pg.Type {
+		return previous.NextPageToken, previous.NextPageToken == pg.PageToken
+	}
+	// Drive and file lists must be complete before the next page type begins.
+	if previous.Type > pg.Type || (previous.Type != page.ChangeList && !previous.Last()) {
+		return previous.NextPageToken, false
+	}
+	switch pg.Type {
+	case page.ChangeList:
+		return data.StartToken, pg.PageToken == data.StartToken
+	default:
+		return "", pg.PageToken == ""
+	}
+}
+
+func (c *checker) commits(driveID resource.ID, commits *bolt.Bucket, collections map[collection.SeqNum]collectionPages) {
+	if commits == nil {
+		return
+	}
+
+	path := [][]byte{[]byte(RootBucket), []byte(DriveBucket), []byte(driveID), []byte(CommitBucket)}
+	var expected commit.SeqNum
+	commits.ForEach(func(k, v []byte) error {
+		if len(k) != 8 || v != nil {
+			c.reportKey(BadCommitKey{Drive: driveID, BadKey: append(k[:0:0], k...)}, path, k)
+			return nil
+		}
+		seqNum := commit.SeqNum(binary.BigEndian.Uint64(k))
+		if seqNum != expected {
+			c.report(CommitGap{Drive: driveID, Commit: seqNum, Expected: expected})
+		}
+		expected = seqNum + 1
+
+		com := commits.Bucket(k)
+		var data commit.Data
+		if value := com.Get([]byte(DataKey)); value == nil || decodeValue(value, &data) != nil {
+			c.report(commit.DataInvalid{Drive: driveID, Commit: seqNum})
+		} else if !sourceExists(data.Source, collections) {
+			c.report(CommitSourceMissing{Drive: driveID, Commit: seqNum, Source: data.Source})
+		}
+
+		if states := com.Bucket([]byte(StateBucket)); states != nil {
+			statesPath := append(path[:len(path):len(path)], k, []byte(StateBucket))
+			var expected commit.StateNum
+			states.ForEach(func(k, v []byte) error {
+				if len(k) != 8 {
+					c.reportKey(BadCommitStateKey{Drive: driveID, Commit: seqNum, BadKey: append(k[:0:0], k...)}, statesPath, k)
+					return nil
+				}
+				state := commit.StateNum(binary.BigEndian.Uint64(k))
+				if state != expected {
+					c.report(CommitStateGap{Drive: driveID, Commit: seqNum, State: state, Expected: expected})
+				}
+				expected = state + 1
+				var s commit.State
+				if v == nil || decodeValue(v, &s) != nil {
+					c.report(commit.StateInvalid{Drive: driveID, Commit: seqNum, State: state})
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// sourceExists returns true if the collection page identified by source
+// exists. For incremental collections that haven't been pruned the change
+// identified by the source index must also exist.
+func sourceExists(source commit.Source, collections map[collection.SeqNum]collectionPages) bool {
+	col, ok := collections[source.Collection]
+	if !ok {
+		return false
+	}
+	changes, ok := col.Changes[source.Page]
+	if !ok {
+		return false
+	}
+	if col.Type == collection.Incremental && !col.Pruned && source.Index >= changes {
+		return false
+	}
+	return true
+}
+
+func (c *checker) fileVersions(fileID resource.ID, file *bolt.Bucket) map[resource.Version]bool {
+	found := make(map[resource.Version]bool)
+	versions := file.Bucket([]byte(VersionBucket))
+	if versions == nil {
+		return found
+	}
+	path := [][]byte{[]byte(RootBucket), []byte(FileBucket), []byte(fileID), []byte(VersionBucket)}
+	versions.ForEach(func(k, v []byte) error {
+		if len(k) != 8 {
+			c.reportKey(BadFileVersionKey{File: fileID, BadKey: append(k[:0:0], k...)}, path, k)
+			return nil
+		}
+		found[resource.Version(binary.BigEndian.Uint64(k))] = true
+		return nil
+	})
+	return found
+}
+
+func (c *checker) fileViews(fileID resource.ID, file *bolt.Bucket, versions map[resource.Version]bool) {
+	views := file.Bucket([]byte(ViewBucket))
+	if views == nil {
+		return
+	}
+	views.ForEach(func(driveID, v []byte) error {
+		view := views.Bucket(driveID)
+		if view == nil {
+			return nil
+		}
+		path := [][]byte{[]byte(RootBucket), []byte(FileBucket), []byte(fileID), []byte(ViewBucket), driveID}
+		drive := resource.ID(driveID)
+		view.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				c.reportKey(BadFileViewKey{File: fileID, Drive: drive, BadKey: append(k[:0:0], k...)}, path, k)
+				return nil
+			}
+			seqNum := commit.SeqNum(binary.BigEndian.Uint64(k))
+			if len(v) != 8 {
+				c.report(BadFileViewValue{File: fileID, Drive: drive, Commit: seqNum, BadValue: append(v[:0:0], v...)})
+				return nil
+			}
+			version := resource.Version(binary.BigEndian.Uint64(v))
+			if version >= 0 && !versions[version] { // Removals are recorded as negative versions
+				c.report(FileViewVersionMissing{File: fileID, Drive: drive, Commit: seqNum, Version: version})
+			}
+			return nil
+		})
+		return nil
+	})
+}
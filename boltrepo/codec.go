@@ -0,0 +1,67 @@
+package boltrepo
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/codec"
+)
+
+// Codec returns the codec used to encode new values written to the
+// repository.
+func (repo Repository) Codec() (c codec.Codec, err error) {
+	return dbCodec(repo.db)
+}
+
+// SetCodec changes the codec used to encode new values written to the
+// repository. Existing values are left as they are. Every value records
+// the codec that encoded it, so values written with any registered codec
+// remain readable.
+func (repo Repository) SetCodec(c codec.Codec) error {
+	return repo.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(RootBucket))
+		if err != nil {
+			return err
+		}
+		if c.ID() == codec.JSONID {
+			return root.Delete([]byte(CodecKey))
+		}
+		return root.Put([]byte(CodecKey), []byte(c.Name()))
+	})
+}
+
+// dbCodec returns the codec used to encode new values written to db.
+func dbCodec(db store) (c codec.Codec, err error) {
+	err = db.View(func(tx *bolt.Tx) error {
+		c, err = txCodec(tx)
+		return err
+	})
+	return c, err
+}
+
+// txCodec returns the codec used to encode new values written within tx.
+// Databases that don't record a codec use JSON.
+func txCodec(tx *bolt.Tx) (codec.Codec, error) {
+	root := tx.Bucket([]byte(RootBucket))
+	if root == nil {
+		return codec.JSON, nil
+	}
+	name := root.Get([]byte(CodecKey))
+	if name == nil {
+		return codec.JSON, nil
+	}
+	return codec.Lookup(string(name))
+}
+
+// encodeValue encodes v with the codec used for new values written to db.
+func encodeValue(db store, v interface{}) ([]byte, error) {
+	c, err := dbCodec(db)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(c, v)
+}
+
+// decodeValue decodes value into v, using whichever codec value was
+// encoded with.
+func decodeValue(value []byte, v interface{}) error {
+	return codec.Decode(value, v)
+}
@@ -2,10 +2,11 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/codec"
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/page"
 	"github.com/scjalliance/drivestream/resource"
@@ -15,7 +16,7 @@ var _ collection.Reference = (*Collection)(nil)
 
 // Collection is a drivestream collection reference for a bolt repository.
 type Collection struct {
-	db         *bolt.DB
+	db         store
 	drive      resource.ID
 	collection collection.SeqNum
 }
@@ -50,7 +51,7 @@ func (ref Collection) Exists() (exists bool, err error) {
 // If a collection already exists with the sequence number an error will be
 // returned.
 func (ref Collection) Create(data collection.Data) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -99,7 +100,7 @@ func (ref Collection) Data() (data collection.Data, err error) {
 		if value == nil {
 			return collection.DataInvalid{Drive: ref.drive, Collection: ref.collection}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
@@ -135,3 +136,92 @@ func (ref Collection) Pages() page.Sequence {
 func (ref Collection) Page(pageNum page.SeqNum) page.Reference {
 	return ref.Pages().Ref(pageNum)
 }
+
+// Prune discards the changes held by the pages of the collection and
+// records a summary of what was discarded. The page tokens of each page
+// are retained. If the collection has already been pruned its existing
+// summary is returned.
+//
+// The reclaimed bytes are returned to bolt's free list for reuse. The
+// database file itself does not shrink.
+func (ref Collection) Prune() (data collection.PruneData, err error) {
+	err = ref.db.Update(func(tx *bolt.Tx) error {
+		col := collectionBucket(tx, ref.drive, ref.collection)
+		if col == nil {
+			return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+		}
+		if value := col.Get([]byte(PrunedKey)); value != nil {
+			return decodeValue(value, &data)
+		}
+
+		c, err := txCodec(tx)
+		if err != nil {
+			return err
+		}
+
+		data.Time = time.Now().UTC()
+		if pages := col.Bucket([]byte(PageBucket)); pages != nil {
+			// Collect the pruned pages before writing them, because bolt
+			// cursors can be invalidated by modifications.
+			type update struct {
+				key   []byte
+				value []byte
+			}
+			var updates []update
+			cursor := pages.Cursor()
+			for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+				if len(k) != 8 {
+					key := append(k[:0:0], k...) // Copy key bytes
+					return BadPageKey{Drive: ref.drive, Collection: ref.collection, BadKey: key}
+				}
+				var pg page.Data
+				if err := decodeValue(v, &pg); err != nil {
+					return collection.PageDataInvalid{Drive: ref.drive, Collection: ref.collection, Page: page.SeqNum(binary.BigEndian.Uint64(k))}
+				}
+				if len(pg.Changes) == 0 {
+					continue
+				}
+				changes := len(pg.Changes)
+				pg.Changes = nil
+				value, err := codec.Encode(c, pg)
+				if err != nil {
+					return err
+				}
+				data.Pages++
+				data.Changes += int64(changes)
+				data.Bytes += int64(len(v) - len(value))
+				updates = append(updates, update{key: append(k[:0:0], k...), value: value})
+			}
+			for _, u := range updates {
+				if err := pages.Put(u.key, u.value); err != nil {
+					return err
+				}
+			}
+		}
+
+		value, err := codec.Encode(c, data)
+		if err != nil {
+			return err
+		}
+		return col.Put([]byte(PrunedKey), value)
+	})
+	return data, err
+}
+
+// Pruned returns a summary of the changes that were discarded when the
+// collection was pruned. If the collection has not been pruned a zero
+// value is returned.
+func (ref Collection) Pruned() (data collection.PruneData, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		col := collectionBucket(tx, ref.drive, ref.collection)
+		if col == nil {
+			return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+		}
+		value := col.Get([]byte(PrunedKey))
+		if value == nil {
+			return nil
+		}
+		return decodeValue(value, &data)
+	})
+	return data, err
+}
@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ collection.Sequence = (*Collections)(nil)
 
 // Collections accesses a sequence of collections in a bolt repository.
 type Collections struct {
-	db    *bolt.DB
+	db    store
 	drive resource.ID
 }
 
@@ -71,7 +70,7 @@ func (ref Collections) Read(start collection.SeqNum, p []collection.Data) (n int
 			if value == nil {
 				return collection.DataInvalid{Drive: ref.drive, Collection: pos}
 			}
-			if err := json.Unmarshal(value, &p[n]); err != nil {
+			if err := decodeValue(value, &p[n]); err != nil {
 				// TODO: Wrap the error in DataInvalid?
 				return err
 			}
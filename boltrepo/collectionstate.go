@@ -2,7 +2,6 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ collection.StateReference = (*CollectionState)(nil)
 // CollectionState is a drivestream collection state accessor for a
 // bolt repository.
 type CollectionState struct {
-	db         *bolt.DB
+	db         store
 	drive      resource.ID
 	collection collection.SeqNum
 	state      collection.StateNum
@@ -35,7 +34,7 @@ func (ref CollectionState) StateNum() collection.StateNum {
 // If a state already exists with the state's sequence number an error
 // will be returned.
 func (ref CollectionState) Create(data collection.State) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -89,7 +88,7 @@ func (ref CollectionState) Data() (data collection.State, err error) {
 		if value == nil {
 			return collection.StateNotFound{Drive: ref.drive, Collection: ref.collection, State: ref.state}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
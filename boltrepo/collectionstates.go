@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -16,7 +15,7 @@ var _ collection.StateSequence = (*CollectionStates)(nil)
 // CollectionStates accesses a sequence of collection states in a
 // bolt repository.
 type CollectionStates struct {
-	db         *bolt.DB
+	db         store
 	drive      resource.ID
 	collection collection.SeqNum
 }
@@ -76,7 +75,7 @@ func (ref CollectionStates) Read(start collection.StateNum, p []collection.State
 			if v == nil {
 				return collection.StateInvalid{Drive: ref.drive, Collection: ref.collection, State: pos} // All states must be non-nil
 			}
-			if err := json.Unmarshal(v, &p[n]); err != nil {
+			if err := decodeValue(v, &p[n]); err != nil {
 				// TODO: Wrap the error in an InvalidState?
 				return err
 			}
@@ -2,7 +2,6 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -14,7 +13,7 @@ var _ commit.Reference = (*Commit)(nil)
 
 // Commit is a drivestream commit reference for a bolt repository.
 type Commit struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 }
@@ -49,7 +48,7 @@ func (ref Commit) Exists() (exists bool, err error) {
 // If a commit already exists with the sequence number an error will be
 // returned.
 func (ref Commit) Create(data commit.Data) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -98,7 +97,7 @@ func (ref Commit) Data() (data commit.Data, err error) {
 		if value == nil {
 			return commit.DataInvalid{Drive: ref.drive, Commit: ref.commit}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
@@ -138,3 +137,37 @@ func (ref Commit) Tree() commit.TreeMap {
 		commit: ref.commit,
 	}
 }
+
+// SetDrift records the drift that was corrected by the commit when it
+// reconciled a full collection.
+func (ref Commit) SetDrift(data commit.DriftData) error {
+	value, err := encodeValue(ref.db, data)
+	if err != nil {
+		return err
+	}
+
+	return ref.db.Update(func(tx *bolt.Tx) error {
+		com := commitBucket(tx, ref.drive, ref.commit)
+		if com == nil {
+			return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+		}
+		return com.Put([]byte(DriftKey), value)
+	})
+}
+
+// Drift returns the drift that was corrected by the commit. If the commit
+// did not reconcile a full collection the zero value is returned.
+func (ref Commit) Drift() (data commit.DriftData, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		com := commitBucket(tx, ref.drive, ref.commit)
+		if com == nil {
+			return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+		}
+		value := com.Get([]byte(DriftKey))
+		if value == nil {
+			return nil
+		}
+		return decodeValue(value, &data)
+	})
+	return data, err
+}
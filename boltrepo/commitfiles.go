@@ -12,7 +12,7 @@ var _ commit.FileMap = (*CommitFiles)(nil)
 
 // CommitFiles is a reference to a commit file map.
 type CommitFiles struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 }
@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ commit.Sequence = (*Commits)(nil)
 
 // Commits accesses a sequence of commits in a bolt repository.
 type Commits struct {
-	db    *bolt.DB
+	db    store
 	drive resource.ID
 }
 
@@ -71,7 +70,7 @@ func (ref Commits) Read(start commit.SeqNum, p []commit.Data) (n int, err error)
 			if value == nil {
 				return commit.DataInvalid{Drive: ref.drive, Commit: pos}
 			}
-			if err := json.Unmarshal(value, &p[n]); err != nil {
+			if err := decodeValue(value, &p[n]); err != nil {
 				// TODO: Wrap the error in DataInvalid?
 				return err
 			}
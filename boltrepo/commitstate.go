@@ -2,7 +2,6 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -14,7 +13,7 @@ var _ commit.StateReference = (*CommitState)(nil)
 
 // CommitState is a reference to a commit state.
 type CommitState struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 	state  commit.StateNum
@@ -33,7 +32,7 @@ func (ref CommitState) StateNum() commit.StateNum {
 // Create creates the commit state with the given data. If a state already
 // exists with the state number an error will be returned.
 func (ref CommitState) Create(data commit.State) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -87,7 +86,7 @@ func (ref CommitState) Data() (data commit.State, err error) {
 		if value == nil {
 			return commit.StateNotFound{Drive: ref.drive, Commit: ref.commit, State: ref.state}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -16,7 +15,7 @@ var _ commit.StateSequence = (*CommitStates)(nil)
 // CommitStates accesses a sequence of commit states in a
 // bolt repository.
 type CommitStates struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 }
@@ -76,7 +75,7 @@ func (ref CommitStates) Read(start commit.StateNum, p []commit.State) (n int, er
 			if v == nil {
 				return commit.StateInvalid{Drive: ref.drive, Commit: ref.commit, State: pos} // All states must be non-nil
 			}
-			if err := json.Unmarshal(v, &p[n]); err != nil {
+			if err := decodeValue(v, &p[n]); err != nil {
 				// TODO: Wrap the error in an InvalidState?
 				return err
 			}
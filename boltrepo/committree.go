@@ -11,7 +11,7 @@ var _ commit.TreeMap = (*CommitTree)(nil)
 
 // CommitTree is a reference to a commit file map.
 type CommitTree struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 }
@@ -11,7 +11,7 @@ var _ commit.TreeGroup = (*CommitTreeGroup)(nil)
 // CommitTreeGroup is an unordered group of tree changes sharing a common
 // parent.
 type CommitTreeGroup struct {
-	db     *bolt.DB
+	db     store
 	drive  resource.ID
 	commit commit.SeqNum
 	parent resource.ID
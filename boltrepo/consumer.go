@@ -0,0 +1,98 @@
+package boltrepo
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ consumer.Reference = (*Consumer)(nil)
+
+// Consumer is a drivestream consumer reference for a bolt repository.
+type Consumer struct {
+	db    store
+	drive resource.ID
+	name  string
+}
+
+// Path returns the path of the consumer.
+func (ref Consumer) Path() binpath.Text {
+	return binpath.Text{RootBucket, DriveBucket, ref.drive.String(), ConsumerBucket, ref.name}
+}
+
+// Drive returns the ID of the drive.
+func (ref Consumer) Drive() resource.ID {
+	return ref.drive
+}
+
+// Name returns the name of the consumer.
+func (ref Consumer) Name() string {
+	return ref.name
+}
+
+// Exists returns true if the consumer has acknowledged a commit.
+func (ref Consumer) Exists() (exists bool, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		consumers := driveConsumersBucket(tx, ref.drive)
+		if consumers == nil {
+			return nil
+		}
+		exists = consumers.Get([]byte(ref.name)) != nil
+		return nil
+	})
+	return exists, err
+}
+
+// Data returns the progress of the consumer. If the consumer hasn't
+// acknowledged a commit a NotFound error is returned.
+func (ref Consumer) Data() (data consumer.Data, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		consumers := driveConsumersBucket(tx, ref.drive)
+		if consumers == nil {
+			return consumer.NotFound{Drive: ref.drive, Name: ref.name}
+		}
+		value := consumers.Get([]byte(ref.name))
+		if value == nil {
+			return consumer.NotFound{Drive: ref.drive, Name: ref.name}
+		}
+		if err := decodeValue(value, &data); err != nil {
+			return consumer.InvalidData{Drive: ref.drive, Name: ref.name}
+		}
+		return nil
+	})
+	return data, err
+}
+
+// Set records the progress of the consumer, replacing any progress
+// recorded before.
+func (ref Consumer) Set(data consumer.Data) error {
+	if ref.name == "" {
+		return consumer.InvalidName{Drive: ref.drive}
+	}
+
+	value, err := encodeValue(ref.db, data)
+	if err != nil {
+		return err
+	}
+
+	return ref.db.Update(func(tx *bolt.Tx) error {
+		consumers, err := createDriveConsumersBucket(tx, ref.drive)
+		if err != nil {
+			return err
+		}
+		return consumers.Put([]byte(ref.name), value)
+	})
+}
+
+// Delete removes the consumer, so that it starts again with the first
+// commit.
+func (ref Consumer) Delete() error {
+	return ref.db.Update(func(tx *bolt.Tx) error {
+		consumers := driveConsumersBucket(tx, ref.drive)
+		if consumers == nil || ref.name == "" {
+			return nil
+		}
+		return consumers.Delete([]byte(ref.name))
+	})
+}
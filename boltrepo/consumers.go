@@ -0,0 +1,46 @@
+package boltrepo
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ consumer.Map = (*Consumers)(nil)
+
+// Consumers accesses a map of drive consumers in a bolt repository.
+type Consumers struct {
+	db    store
+	drive resource.ID
+}
+
+// Path returns the path of the drive consumers.
+func (ref Consumers) Path() binpath.Text {
+	return binpath.Text{RootBucket, DriveBucket, ref.drive.String(), ConsumerBucket}
+}
+
+// List returns the names of all consumers within the map.
+func (ref Consumers) List() (names []string, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		bucket := driveConsumersBucket(tx, ref.drive)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			names = append(names, string(k))
+		}
+		return nil
+	})
+	return names, err
+}
+
+// Ref returns a reference to the named consumer.
+func (ref Consumers) Ref(name string) consumer.Reference {
+	return Consumer{
+		db:    ref.db,
+		drive: ref.drive,
+		name:  name,
+	}
+}
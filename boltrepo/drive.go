@@ -6,6 +6,8 @@ import (
 	"github.com/scjalliance/drivestream/binpath"
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
 	"github.com/scjalliance/drivestream/driveversion"
 	"github.com/scjalliance/drivestream/driveview"
 	"github.com/scjalliance/drivestream/resource"
@@ -15,7 +17,7 @@ var _ drivestream.DriveReference = (*Drive)(nil)
 
 // Drive is a drivestream drive reference for a bolt repository.
 type Drive struct {
-	db    *bolt.DB
+	db    store
 	drive resource.ID
 }
 
@@ -104,6 +106,24 @@ func (ref Drive) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
 	return ref.View().At(seqNum)
 }
 
+// Actors returns the map of actors that have made changes within the
+// drive.
+func (ref Drive) Actors() driveactor.Map {
+	return DriveActors{
+		db:    ref.db,
+		drive: ref.drive,
+	}
+}
+
+// Consumers returns the map of named consumers that process the commits
+// of the drive.
+func (ref Drive) Consumers() consumer.Map {
+	return Consumers{
+		db:    ref.db,
+		drive: ref.drive,
+	}
+}
+
 // Stats returns statistics about the drive.
 func (ref Drive) Stats() (stats drivestream.DriveStats, err error) {
 	err = ref.db.View(func(tx *bolt.Tx) error {
@@ -119,6 +139,16 @@ func (ref Drive) Stats() (stats drivestream.DriveStats, err error) {
 				stats.Collections++
 				stats.CollectionBytes += int64(len(k)) + int64(len(v))
 				stats.CollectionBytes += countBytes(collections.Bucket(k))
+				if col := collections.Bucket(k); col != nil {
+					if value := col.Get([]byte(PrunedKey)); value != nil {
+						var pruned collection.PruneData
+						if err := decodeValue(value, &pruned); err != nil {
+							return err
+						}
+						stats.PrunedPages += pruned.Pages
+						stats.ReclaimedBytes += pruned.Bytes
+					}
+				}
 			}
 		}
 		if commits := commitsBucket(tx, ref.drive); commits != nil {
@@ -127,6 +157,18 @@ func (ref Drive) Stats() (stats drivestream.DriveStats, err error) {
 				stats.Commits++
 				stats.CommitBytes += int64(len(k)) + int64(len(v))
 				stats.CommitBytes += countBytes(commits.Bucket(k))
+				if com := commits.Bucket(k); com != nil {
+					if value := com.Get([]byte(DriftKey)); value != nil {
+						var drift commit.DriftData
+						if err := decodeValue(value, &drift); err != nil {
+							return err
+						}
+						stats.Reconciliations++
+						stats.DriftAdded += drift.Added
+						stats.DriftChanged += drift.Changed
+						stats.DriftRemoved += drift.Removed
+					}
+				}
 			}
 		}
 		if versions := driveVersionsBucket(tx, ref.drive); versions != nil {
@@ -0,0 +1,135 @@
+package boltrepo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveactor.Reference = (*DriveActor)(nil)
+
+// DriveActor is a drivestream drive actor reference for a bolt repository.
+type DriveActor struct {
+	db    store
+	drive resource.ID
+	email string
+}
+
+// Path returns the path of the bucket that holds the drive actor's
+// entries when they are written with the repository's current codec. The
+// bucket is named by a hash of the actor's email address.
+func (ref DriveActor) Path() binpath.Text {
+	c, err := dbCodec(ref.db)
+	if err != nil {
+		c = codec.JSON
+	}
+	name := codec.Hash(c, []byte(ref.email))
+	return binpath.Text{RootBucket, DriveBucket, ref.drive.String(), ActorBucket, hex.EncodeToString(name)}
+}
+
+// Drive returns the ID of the drive.
+func (ref DriveActor) Drive() resource.ID {
+	return ref.drive
+}
+
+// Email returns the email address of the actor.
+func (ref DriveActor) Email() string {
+	return ref.email
+}
+
+// Read returns the entries for changes made by the actor at or after
+// since and before until, in chronological order. A zero value for
+// either time leaves that end of the range unbounded.
+//
+// Entries are read from every bucket that the actor's email address may
+// hash to with the registered codecs, so entries written before the
+// repository's codec or key changed are included.
+func (ref DriveActor) Read(since, until time.Time) (entries []driveactor.Entry, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		actors := driveActorsBucket(tx, ref.drive)
+		if actors == nil {
+			return nil
+		}
+
+		var found []actorEntry
+		for _, name := range codec.Hashes([]byte(ref.email)) {
+			actor := actors.Bucket(name)
+			if actor == nil {
+				continue
+			}
+			more, err := ref.readBucket(name, actor, since, until)
+			if err != nil {
+				return err
+			}
+			found = append(found, more...)
+		}
+
+		// Merge entries from more than one bucket, dropping entries that
+		// were written to both.
+		sort.SliceStable(found, func(i, j int) bool {
+			return bytes.Compare(found[i].key, found[j].key) < 0
+		})
+		for i := range found {
+			if i > 0 && bytes.Equal(found[i].key, found[i-1].key) {
+				continue
+			}
+			entries = append(entries, found[i].entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// actorEntry is an actor entry along with its key.
+type actorEntry struct {
+	key   []byte
+	entry driveactor.Entry
+}
+
+// readBucket returns the entries within the actor bucket with the given
+// name at or after since and before until.
+func (ref DriveActor) readBucket(name []byte, actor *bolt.Bucket, since, until time.Time) (entries []actorEntry, err error) {
+	var end []byte
+	if !until.IsZero() {
+		key := makeTimeKey(until)
+		end = key[:]
+	}
+
+	cursor := actor.Cursor()
+	var k, v []byte
+	if since.IsZero() {
+		k, v = cursor.First()
+	} else {
+		key := makeTimeKey(since)
+		k, v = cursor.Seek(key[:])
+	}
+
+	for ; k != nil; k, v = cursor.Next() {
+		if len(k) < 16 {
+			key := append(k[:0:0], k...) // Copy key bytes
+			return nil, BadDriveActorKey{Drive: ref.drive, Actor: append(name[:0:0], name...), BadKey: key}
+		}
+		if end != nil && bytes.Compare(k[:8], end) >= 0 {
+			break
+		}
+		entry := driveactor.Entry{
+			Time:   time.Unix(0, int64(binary.BigEndian.Uint64(k[0:8]))).UTC(),
+			Commit: commit.SeqNum(binary.BigEndian.Uint64(k[8:16])),
+			File:   resource.ID(k[16:]),
+		}
+		if err := decodeValue(v, &entry.Actor); err != nil {
+			return nil, driveactor.InvalidData{Drive: ref.drive, Email: ref.email}
+		}
+		entries = append(entries, actorEntry{key: append(k[:0:0], k...), entry: entry})
+	}
+	return entries, nil
+}
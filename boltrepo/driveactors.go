@@ -0,0 +1,110 @@
+package boltrepo
+
+import (
+	"sort"
+
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveactor.Map = (*DriveActors)(nil)
+
+// DriveActors accesses a map of drive actors in a bolt repository.
+type DriveActors struct {
+	db    store
+	drive resource.ID
+}
+
+// Path returns the path of the drive actors.
+func (ref DriveActors) Path() binpath.Text {
+	return binpath.Text{RootBucket, DriveBucket, ref.drive.String(), ActorBucket}
+}
+
+// List returns the email addresses of all actors within the map.
+//
+// Actor buckets are named by a hash of each actor's email address, so the
+// addresses are decoded from the first entry of each bucket.
+func (ref DriveActors) List() (actors []string, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		bucket := driveActorsBucket(tx, ref.drive)
+		if bucket == nil {
+			return nil
+		}
+		seen := make(map[string]bool)
+		return bucket.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			_, value := bucket.Bucket(k).Cursor().First()
+			if value == nil {
+				return nil
+			}
+			var actor resource.UserData
+			if err := decodeValue(value, &actor); err != nil {
+				return BadDriveActorValue{Drive: ref.drive, Actor: append(k[:0:0], k...), Err: err}
+			}
+			if !seen[actor.EmailAddress] {
+				seen[actor.EmailAddress] = true
+				actors = append(actors, actor.EmailAddress)
+			}
+			return nil
+		})
+	})
+	sort.Strings(actors)
+	return actors, err
+}
+
+// Ref returns a reference to the changes made by an actor.
+func (ref DriveActors) Ref(email string) driveactor.Reference {
+	return DriveActor{
+		db:    ref.db,
+		drive: ref.drive,
+		email: email,
+	}
+}
+
+// Add adds the given entries to the map in bulk, indexed by the email
+// address of each entry's actor.
+//
+// Each actor's entries are stored in a bucket named by a hash of the
+// actor's email address, so that the address itself is only stored
+// within values. When the repository's codec is a codec.Hasher the hash
+// is keyed, which keeps the addresses private in encrypted repositories.
+func (ref DriveActors) Add(entries ...driveactor.Entry) error {
+	// Perform the encoding outside the transaction to minimize
+	// time spent within it.
+	c, err := dbCodec(ref.db)
+	if err != nil {
+		return err
+	}
+	payloads := make([][]byte, 0, len(entries))
+	names := make([][]byte, 0, len(entries))
+	for i := range entries {
+		payload, err := codec.Encode(c, entries[i].Actor)
+		if err != nil {
+			return err
+		}
+		payloads = append(payloads, payload)
+		names = append(names, codec.Hash(c, []byte(entries[i].Actor.EmailAddress)))
+	}
+	return ref.db.Update(func(tx *bolt.Tx) error {
+		actors, err := createDriveActorsBucket(tx, ref.drive)
+		if err != nil {
+			return err
+		}
+		for i, entry := range entries {
+			actor, err := actors.CreateBucketIfNotExists(names[i])
+			if err != nil {
+				return err
+			}
+			key := makeActorKey(entry.Time, entry.Commit, entry.File)
+			if err := actor.Put(key, payloads[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -11,7 +11,7 @@ var _ drivestream.DriveMap = (*Drives)(nil)
 
 // Drives accesses a map of drives in a bolt repository.
 type Drives struct {
-	db *bolt.DB
+	db store
 }
 
 // Path returns the path of the drives.
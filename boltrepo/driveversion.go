@@ -2,7 +2,6 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ driveversion.Reference = (*DriveVersion)(nil)
 // DriveVersion is a drivestream drive version reference for a bolt
 // repository.
 type DriveVersion struct {
-	db      *bolt.DB
+	db      store
 	drive   resource.ID
 	version resource.Version
 }
@@ -39,7 +38,7 @@ func (ref DriveVersion) Version() resource.Version {
 // and data. If a version already exists with the version number an
 // error will be returned.
 func (ref DriveVersion) Create(data resource.DriveData) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -85,7 +84,7 @@ func (ref DriveVersion) Data() (data resource.DriveData, err error) {
 		if value == nil {
 			return driveversion.NotFound{Drive: ref.drive, Version: ref.version}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
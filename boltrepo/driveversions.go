@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ driveversion.Sequence = (*DriveVersions)(nil)
 
 // DriveVersions accesses a sequence of drive versions in a bolt repository.
 type DriveVersions struct {
-	db    *bolt.DB
+	db    store
 	drive resource.ID
 }
 
@@ -66,7 +65,7 @@ func (ref DriveVersions) Read(start resource.Version, p []resource.DriveData) (n
 			if v == nil {
 				return driveversion.InvalidData{Drive: ref.drive, Version: pos} // All versions must be non-nil
 			}
-			if err := json.Unmarshal(v, &p[n]); err != nil {
+			if err := decodeValue(v, &p[n]); err != nil {
 				// TODO: Wrap the error in InvalidData?
 				return err
 			}
@@ -17,7 +17,7 @@ var _ driveview.Reference = (*DriveView)(nil)
 // DriveView is a drivestream drive version reference for a bolt
 // repository.
 type DriveView struct {
-	db    *bolt.DB
+	db    store
 	drive resource.ID
 }
 
@@ -91,3 +91,31 @@ func (ref DriveView) Add(seqNum commit.SeqNum, version resource.Version) error {
 		return view.Put(key[:], value[:])
 	})
 }
+
+// Entries returns every view of the drive in commit order.
+func (ref DriveView) Entries() (entries []driveview.Entry, err error) {
+	err = ref.db.View(func(tx *bolt.Tx) error {
+		view := driveViewBucket(tx, ref.drive)
+		if view == nil {
+			return nil
+		}
+		cursor := view.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			if len(k) != 8 {
+				key := append(k[:0:0], k...) // Copy key bytes
+				return BadDriveViewKey{Drive: ref.drive, BadKey: key}
+			}
+			seqNum := commit.SeqNum(binary.BigEndian.Uint64(k))
+			if len(v) != 8 {
+				value := append(v[:0:0], v...) // Copy value bytes
+				return BadDriveViewValue{Drive: ref.drive, Commit: seqNum, BadValue: value}
+			}
+			entries = append(entries, driveview.Entry{
+				Commit:  seqNum,
+				Version: resource.Version(binary.BigEndian.Uint64(v)),
+			})
+		}
+		return nil
+	})
+	return entries, err
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/page"
 	"github.com/scjalliance/drivestream/resource"
 )
 
@@ -120,6 +121,46 @@ func (e BadDriveViewValue) Error() string {
 	return fmt.Sprintf("drivestream: drive %s: the database contains an invalid drive view value for commit %d: %v", e.Drive, e.Commit, e.BadValue)
 }
 
+// BadDriveActorBucket reports that the repository contains an actor bucket
+// whose name is not a hash of an email address.
+type BadDriveActorBucket struct {
+	Drive  resource.ID
+	BadKey []byte
+}
+
+// Error returns a string representation of the error.
+func (e BadDriveActorBucket) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: the database contains an invalid actor bucket: %v", e.Drive, e.BadKey)
+}
+
+// BadDriveActorKey reports that the repository contains invalid key
+// data within its drive actor table. Actor is the name of the actor's
+// bucket, which is a hash of the actor's email address.
+type BadDriveActorKey struct {
+	Drive  resource.ID
+	Actor  []byte
+	BadKey []byte
+}
+
+// Error returns a string representation of the error.
+func (e BadDriveActorKey) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: the database contains an invalid actor key for actor %x: %v", e.Drive, e.Actor, e.BadKey)
+}
+
+// BadDriveActorValue reports that the repository contains an actor value
+// that could not be decoded. Actor is the name of the actor's bucket,
+// which is a hash of the actor's email address.
+type BadDriveActorValue struct {
+	Drive resource.ID
+	Actor []byte
+	Err   error
+}
+
+// Error returns a string representation of the error.
+func (e BadDriveActorValue) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: the database contains an actor value for actor %x that can't be decoded: %v", e.Drive, e.Actor, e.Err)
+}
+
 // BadFileVersionKey reports that the repository contains invalid key
 // data within its file version table.
 type BadFileVersionKey struct {
@@ -156,5 +197,161 @@ type BadFileViewValue struct {
 
 // Error returns a string representation of the error.
 func (e BadFileViewValue) Error() string {
-	return fmt.Sprintf("drivestream: drive %s: the database contains an invalid file view value for drive %s commit %d: %v", e.Drive, e.Drive, e.Commit, e.BadValue)
+	return fmt.Sprintf("drivestream: file %s: the database contains an invalid file view value for drive %s commit %d: %v", e.File, e.Drive, e.Commit, e.BadValue)
+}
+
+// BadSchemaVersion reports that the repository contains an invalid schema
+// version.
+type BadSchemaVersion struct {
+	BadValue []byte
+}
+
+// Error returns a string representation of the error.
+func (e BadSchemaVersion) Error() string {
+	return fmt.Sprintf("drivestream: the database contains an invalid schema version: %v", e.BadValue)
+}
+
+// SchemaTooNew reports that the repository was written with a newer schema
+// than this version of the package supports.
+type SchemaTooNew struct {
+	Version   SchemaVersion
+	Supported SchemaVersion
+}
+
+// Error returns a string representation of the error.
+func (e SchemaTooNew) Error() string {
+	return fmt.Sprintf("drivestream: the database uses schema version %d, which is newer than the supported version %d", e.Version, e.Supported)
+}
+
+// SchemaOutdated reports that the repository uses an older schema that
+// must be migrated before it can be used, but the database is read-only.
+type SchemaOutdated struct {
+	Version SchemaVersion
+	Current SchemaVersion
+}
+
+// Error returns a string representation of the error.
+func (e SchemaOutdated) Error() string {
+	return fmt.Sprintf("drivestream: the database uses schema version %d and must be migrated to version %d, but it is read-only", e.Version, e.Current)
+}
+
+// MigrationFailed reports that a schema migration could not be completed.
+type MigrationFailed struct {
+	Version SchemaVersion
+	Err     error
+}
+
+// Error returns a string representation of the error.
+func (e MigrationFailed) Error() string {
+	return fmt.Sprintf("drivestream: migration to schema version %d failed: %v", e.Version, e.Err)
+}
+
+// CollectionGap reports that a drive is missing one or more collections
+// in its sequence.
+type CollectionGap struct {
+	Drive      resource.ID
+	Collection collection.SeqNum
+	Expected   collection.SeqNum
+}
+
+// Error returns a string representation of the error.
+func (e CollectionGap) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: collection %d follows a gap in the collection sequence (expected %d)", e.Drive, e.Collection, e.Expected)
+}
+
+// CollectionStateGap reports that a collection is missing one or more
+// states in its sequence.
+type CollectionStateGap struct {
+	Drive      resource.ID
+	Collection collection.SeqNum
+	State      collection.StateNum
+	Expected   collection.StateNum
+}
+
+// Error returns a string representation of the error.
+func (e CollectionStateGap) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: collection %d: state %d follows a gap in the state sequence (expected %d)", e.Drive, e.Collection, e.State, e.Expected)
+}
+
+// PageGap reports that a collection is missing one or more pages in its
+// sequence.
+type PageGap struct {
+	Drive      resource.ID
+	Collection collection.SeqNum
+	Page       page.SeqNum
+	Expected   page.SeqNum
+}
+
+// Error returns a string representation of the error.
+func (e PageGap) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: collection %d: page %d follows a gap in the page sequence (expected %d)", e.Drive, e.Collection, e.Page, e.Expected)
+}
+
+// BrokenPageChain reports that the page token of a page does not follow
+// from the page that precedes it.
+type BrokenPageChain struct {
+	Drive      resource.ID
+	Collection collection.SeqNum
+	Page       page.SeqNum
+	Expected   string
+	Token      string
+}
+
+// Error returns a string representation of the error.
+func (e BrokenPageChain) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: collection %d: page %d has page token \"%s\" but the previous page expects \"%s\"", e.Drive, e.Collection, e.Page, e.Token, e.Expected)
+}
+
+// CommitGap reports that a drive is missing one or more commits in its
+// sequence.
+type CommitGap struct {
+	Drive    resource.ID
+	Commit   commit.SeqNum
+	Expected commit.SeqNum
+}
+
+// Error returns a string representation of the error.
+func (e CommitGap) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: commit %d follows a gap in the commit sequence (expected %d)", e.Drive, e.Commit, e.Expected)
+}
+
+// CommitStateGap reports that a commit is missing one or more states in
+// its sequence.
+type CommitStateGap struct {
+	Drive    resource.ID
+	Commit   commit.SeqNum
+	State    commit.StateNum
+	Expected commit.StateNum
+}
+
+// Error returns a string representation of the error.
+func (e CommitStateGap) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: commit %d: state %d follows a gap in the state sequence (expected %d)", e.Drive, e.Commit, e.State, e.Expected)
+}
+
+// CommitSourceMissing reports that the source of a commit refers to a
+// collection, page or change that does not exist.
+type CommitSourceMissing struct {
+	Drive  resource.ID
+	Commit commit.SeqNum
+	Source commit.Source
+}
+
+// Error returns a string representation of the error.
+func (e CommitSourceMissing) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: commit %d: the source collection %d page %d index %d does not exist", e.Drive, e.Commit, e.Source.Collection, e.Source.Page, e.Source.Index)
+}
+
+// FileViewVersionMissing reports that a file view refers to a file
+// version that does not exist.
+type FileViewVersionMissing struct {
+	File    resource.ID
+	Drive   resource.ID
+	Commit  commit.SeqNum
+	Version resource.Version
+}
+
+// Error returns a string representation of the error.
+func (e FileViewVersionMissing) Error() string {
+	return fmt.Sprintf("drivestream: file %s: the view for drive %s commit %d refers to version %d, which does not exist", e.File, e.Drive, e.Commit, e.Version)
 }
@@ -13,7 +13,7 @@ var _ drivestream.FileReference = (*File)(nil)
 
 // File is a drivestream file reference for a bolt repository.
 type File struct {
-	db   *bolt.DB
+	db   store
 	file resource.ID
 }
 
@@ -1,11 +1,10 @@
 package boltrepo
 
 import (
-	"encoding/json"
-
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream"
 	"github.com/scjalliance/drivestream/binpath"
+	"github.com/scjalliance/drivestream/codec"
 	"github.com/scjalliance/drivestream/fileview"
 	"github.com/scjalliance/drivestream/resource"
 )
@@ -14,7 +13,7 @@ var _ drivestream.FileMap = (*Files)(nil)
 
 // Files accesses a map of files in a bolt repository.
 type Files struct {
-	db *bolt.DB
+	db store
 }
 
 // Path returns the path of the files.
@@ -53,12 +52,21 @@ func (ref Files) Ref(id resource.ID) drivestream.FileReference {
 }
 
 // AddVersions adds file versions to the file map in bulk.
+//
+// Versions that already exist are skipped, and keep the data they were
+// first stored with. The same version can be collected with different
+// data through different drives. Check reports copies that conflict in
+// properties of the version itself.
 func (ref Files) AddVersions(fileVersions ...resource.File) error {
-	// Perform the JSON encoding outside the transaction to minimize
+	// Perform the encoding outside the transaction to minimize
 	// time spent within it.
-	payloads := make([][]byte, len(fileVersions))
+	c, err := dbCodec(ref.db)
+	if err != nil {
+		return err
+	}
+	payloads := make([][]byte, 0, len(fileVersions))
 	for i := range fileVersions {
-		payload, err := json.Marshal(fileVersions[i].FileData)
+		payload, err := codec.Encode(c, fileVersions[i].FileData)
 		if err != nil {
 			return err
 		}
@@ -83,6 +91,9 @@ func (ref Files) AddVersions(fileVersions ...resource.File) error {
 				return err
 			}
 			key := makeVersionKey(fileVersions[i].Version)
+			if versions.Get(key[:]) != nil {
+				continue
+			}
 			if err := versions.Put(key[:], payloads[i]); err != nil {
 				return err
 			}
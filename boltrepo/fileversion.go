@@ -1,8 +1,6 @@
 package boltrepo
 
 import (
-	"encoding/json"
-
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
 	"github.com/scjalliance/drivestream/fileversion"
@@ -14,7 +12,7 @@ var _ fileversion.Reference = (*FileVersion)(nil)
 // FileVersion is a drivestream file version reference for a bolt
 // repository.
 type FileVersion struct {
-	db      *bolt.DB
+	db      store
 	file    resource.ID
 	version resource.Version
 }
@@ -38,7 +36,7 @@ func (ref FileVersion) Version() resource.Version {
 // and data. If a version already exists with the version number an
 // error will be returned.
 func (ref FileVersion) Create(data resource.FileData) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -66,7 +64,7 @@ func (ref FileVersion) Data() (data resource.FileData, err error) {
 		if value == nil {
 			return fileversion.NotFound{File: ref.file, Version: ref.version}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
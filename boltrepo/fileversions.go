@@ -13,7 +13,7 @@ var _ fileversion.Map = (*FileVersions)(nil)
 
 // FileVersions accesses a map of file versions in a bolt repository.
 type FileVersions struct {
-	db   *bolt.DB
+	db   store
 	file resource.ID
 }
 
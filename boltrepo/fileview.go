@@ -17,7 +17,7 @@ var _ fileview.Reference = (*FileView)(nil)
 // FileView is a drivestream file version reference for a bolt
 // repository.
 type FileView struct {
-	db    *bolt.DB
+	db    store
 	file  resource.ID
 	drive resource.ID
 }
@@ -11,7 +11,7 @@ var _ fileview.Map = (*FileViews)(nil)
 
 // FileViews accesses a map of file views in a bolt repository.
 type FileViews struct {
-	db   *bolt.DB
+	db   store
 	file resource.ID
 }
 
@@ -0,0 +1,200 @@
+package boltrepo
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream/codec"
+)
+
+// A Migration upgrades a bolt repository from the previous schema version
+// to Version.
+//
+// Migrations are performed as a series of steps, each within its own
+// transaction, so that large databases can be upgraded without holding a
+// single enormous transaction open. Each step is given a state bucket that
+// is reserved for the migration. A step should record its progress within
+// the state bucket so that an interrupted migration resumes where it left
+// off the next time the database is opened. The state bucket is removed
+// and the schema version is updated within the same transaction as the
+// final step.
+type Migration struct {
+	Version     SchemaVersion
+	Description string
+	Step        func(tx *bolt.Tx, state *bolt.Bucket) (done bool, err error)
+}
+
+// migrations is the list of registered migrations, in schema version
+// order.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Record the schema version of databases created before schema versions were introduced",
+		Step: func(tx *bolt.Tx, state *bolt.Bucket) (done bool, err error) {
+			return true, nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "Allow values to be encoded with codecs other than JSON, which earlier versions cannot read",
+		Step: func(tx *bolt.Tx, state *bolt.Bucket) (done bool, err error) {
+			return true, nil
+		},
+	},
+	{
+		Version:     3,
+		Description: "Name actor buckets by a hash of each actor's email address, which is keyed in encrypted repositories",
+		Step:        hashActorBuckets,
+	},
+}
+
+// hashActorBuckets renames the actor buckets of one drive per step from
+// the actor's email address to its hash. The ID of the last drive that was
+// migrated is recorded in state.
+func hashActorBuckets(tx *bolt.Tx, state *bolt.Bucket) (done bool, err error) {
+	drives := tx.Bucket([]byte(RootBucket)).Bucket([]byte(DriveBucket))
+	if drives == nil {
+		return true, nil
+	}
+
+	cursor := drives.Cursor()
+	var k, v []byte
+	if last := state.Get([]byte(DriveBucket)); last == nil {
+		k, v = cursor.First()
+	} else {
+		k, v = cursor.Seek(last)
+		if k != nil && bytes.Equal(k, last) {
+			k, v = cursor.Next()
+		}
+	}
+	for k != nil && v != nil { // Skip values that aren't drive buckets
+		k, v = cursor.Next()
+	}
+	if k == nil {
+		return true, nil
+	}
+	driveID := append(k[:0:0], k...)
+
+	c, err := txCodec(tx)
+	if err != nil {
+		return false, err
+	}
+
+	if actors := drives.Bucket(driveID).Bucket([]byte(ActorBucket)); actors != nil {
+		var emails [][]byte
+		actors.ForEach(func(k, v []byte) error {
+			if v == nil {
+				emails = append(emails, append(k[:0:0], k...))
+			}
+			return nil
+		})
+		for _, email := range emails {
+			old := actors.Bucket(email)
+			actor, err := actors.CreateBucketIfNotExists(codec.Hash(c, email))
+			if err != nil {
+				return false, err
+			}
+			err = old.ForEach(func(k, v []byte) error {
+				return actor.Put(append(k[:0:0], k...), append(v[:0:0], v...))
+			})
+			if err != nil {
+				return false, err
+			}
+			if err := actors.DeleteBucket(email); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return false, state.Put([]byte(DriveBucket), driveID)
+}
+
+// upgrade checks the schema version of db and runs any migrations needed
+// to bring it up to date. New databases are stamped with the current
+// schema version.
+func upgrade(db *bolt.DB) error {
+	var (
+		version SchemaVersion
+		fresh   bool
+	)
+	err := db.View(func(tx *bolt.Tx) (err error) {
+		fresh = tx.Bucket([]byte(RootBucket)) == nil
+		version, err = schemaVersion(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version > CurrentSchemaVersion:
+		return SchemaTooNew{Version: version, Supported: CurrentSchemaVersion}
+	case fresh:
+		if db.IsReadOnly() {
+			return nil
+		}
+		return db.Update(func(tx *bolt.Tx) error {
+			return writeSchemaVersion(tx, CurrentSchemaVersion)
+		})
+	case version == CurrentSchemaVersion:
+		return nil
+	case db.IsReadOnly():
+		return SchemaOutdated{Version: version, Current: CurrentSchemaVersion}
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if m.Version != version+1 {
+			return MigrationFailed{Version: version + 1, Err: fmt.Errorf("no migration is registered for schema version %d", version+1)}
+		}
+		if err := runMigration(db, m); err != nil {
+			return MigrationFailed{Version: m.Version, Err: err}
+		}
+		version = m.Version
+	}
+
+	if version != CurrentSchemaVersion {
+		return MigrationFailed{Version: CurrentSchemaVersion, Err: fmt.Errorf("no migration is registered for schema version %d", version+1)}
+	}
+
+	return nil
+}
+
+// runMigration runs the steps of m until it is complete.
+func runMigration(db *bolt.DB, m Migration) error {
+	key := makeSchemaValue(m.Version)
+	for {
+		var done bool
+		err := db.Update(func(tx *bolt.Tx) (err error) {
+			root, err := tx.CreateBucketIfNotExists([]byte(RootBucket))
+			if err != nil {
+				return err
+			}
+			states, err := root.CreateBucketIfNotExists([]byte(MigrationBucket))
+			if err != nil {
+				return err
+			}
+			state, err := states.CreateBucketIfNotExists(key[:])
+			if err != nil {
+				return err
+			}
+			done, err = m.Step(tx, state)
+			if err != nil || !done {
+				return err
+			}
+			if err := states.DeleteBucket(key[:]); err != nil {
+				return err
+			}
+			return writeSchemaVersion(tx, m.Version)
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
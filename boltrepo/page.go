@@ -2,7 +2,6 @@ package boltrepo
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -15,7 +14,7 @@ var _ page.Reference = (*Page)(nil)
 
 // Page is a drivestream page reference for a bolt repository.
 type Page struct {
-	db         *bolt.DB
+	db         store
 	drive      resource.ID
 	collection collection.SeqNum
 	page       page.SeqNum
@@ -33,7 +32,7 @@ func (ref Page) SeqNum() page.SeqNum {
 
 // Create creates the page with the given data.
 func (ref Page) Create(data page.Data) error {
-	value, err := json.Marshal(data)
+	value, err := encodeValue(ref.db, data)
 	if err != nil {
 		return err
 	}
@@ -87,7 +86,7 @@ func (ref Page) Data() (data page.Data, err error) {
 		if value == nil {
 			return collection.PageNotFound{Drive: ref.drive, Collection: ref.collection, Page: ref.page}
 		}
-		if err := json.Unmarshal(value, &data); err != nil {
+		if err := decodeValue(value, &data); err != nil {
 			// TODO: Wrap the error in DataInvalid?
 			return err
 		}
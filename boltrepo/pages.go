@@ -3,7 +3,6 @@ package boltrepo
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream/binpath"
@@ -16,7 +15,7 @@ var _ page.Sequence = (*Pages)(nil)
 
 // Pages accesses a sequence of pages in a bolt repository.
 type Pages struct {
-	db         *bolt.DB
+	db         store
 	drive      resource.ID
 	collection collection.SeqNum
 }
@@ -75,7 +74,7 @@ func (ref Pages) Read(start page.SeqNum, p []page.Data) (n int, err error) {
 			if v == nil {
 				return collection.PageDataInvalid{Drive: ref.drive, Collection: ref.collection, Page: pos} // All pages must be non-nil
 			}
-			if err := json.Unmarshal(v, &p[n]); err != nil {
+			if err := decodeValue(v, &p[n]); err != nil {
 				// TODO: Wrap the error in PageDataInvalid?
 				return err
 			}
@@ -15,12 +15,26 @@ type Repository struct {
 }
 
 // New returns a new drivestream bolt database for the team drive.
+//
+// New does not examine the schema version of the database. Use Open to
+// verify the schema version and upgrade older databases.
 func New(db *bolt.DB) Repository {
 	return Repository{
 		db: db,
 	}
 }
 
+// Open returns a drivestream bolt database for db after verifying its
+// schema version. Databases with an older schema are migrated to the
+// current schema. An error of type SchemaTooNew is returned if the
+// database was written by a newer version of this package.
+func Open(db *bolt.DB) (Repository, error) {
+	if err := upgrade(db); err != nil {
+		return Repository{}, err
+	}
+	return New(db), nil
+}
+
 // Type returns a string describing the type of the repository.
 func (repo Repository) Type() string {
 	return "bolt"
@@ -4,11 +4,14 @@ package boltrepo
 const (
 	RootBucket       = "drivestream"
 	SchemaKey        = "schema"
+	CodecKey         = "codec"
 	DriveBucket      = "drive"
 	FileBucket       = "file"
 	TreeBucket       = "tree"
 	CollectionBucket = "collection"
 	DataKey          = "data"
+	PrunedKey        = "pruned"
+	DriftKey         = "drift"
 	StateBucket      = "state"
 	PageBucket       = "page"
 	CommitBucket     = "commit"
@@ -16,4 +19,7 @@ const (
 	VersionBucket    = "version"
 	ViewBucket       = "view"
 	HashBucket       = "hash"
+	ActorBucket      = "actor"
+	ConsumerBucket   = "consumer"
+	MigrationBucket  = "migration"
 )
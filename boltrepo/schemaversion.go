@@ -0,0 +1,50 @@
+package boltrepo
+
+import (
+	"encoding/binary"
+
+	"github.com/boltdb/bolt"
+)
+
+// SchemaVersion is the version of the layout of a bolt repository.
+type SchemaVersion uint64
+
+// CurrentSchemaVersion is the schema version written by this package.
+const CurrentSchemaVersion SchemaVersion = 3
+
+// ReadSchemaVersion returns the schema version of db. Databases that were
+// created before schema versions were recorded are reported as version 0.
+// An empty database is reported as the current version.
+func ReadSchemaVersion(db *bolt.DB) (version SchemaVersion, err error) {
+	err = db.View(func(tx *bolt.Tx) error {
+		version, err = schemaVersion(tx)
+		return err
+	})
+	return version, err
+}
+
+// schemaVersion returns the schema version recorded within tx.
+func schemaVersion(tx *bolt.Tx) (SchemaVersion, error) {
+	root := tx.Bucket([]byte(RootBucket))
+	if root == nil {
+		return CurrentSchemaVersion, nil
+	}
+	value := root.Get([]byte(SchemaKey))
+	if value == nil {
+		return 0, nil
+	}
+	if len(value) != 8 {
+		return 0, BadSchemaVersion{BadValue: append(value[:0:0], value...)}
+	}
+	return SchemaVersion(binary.BigEndian.Uint64(value)), nil
+}
+
+// writeSchemaVersion records the schema version within tx.
+func writeSchemaVersion(tx *bolt.Tx, version SchemaVersion) error {
+	root, err := tx.CreateBucketIfNotExists([]byte(RootBucket))
+	if err != nil {
+		return err
+	}
+	value := makeSchemaValue(version)
+	return root.Put([]byte(SchemaKey), value[:])
+}
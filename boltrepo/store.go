@@ -0,0 +1,29 @@
+package boltrepo
+
+import "github.com/boltdb/bolt"
+
+// store runs read and write transactions against a bolt database. It is
+// implemented by *bolt.DB, which runs each transaction on its own, and by
+// txStore, which runs every transaction within a single batch.
+type store interface {
+	View(fn func(*bolt.Tx) error) error
+	Update(fn func(*bolt.Tx) error) error
+}
+
+// txStore is a store that runs every transaction within tx.
+type txStore struct {
+	tx *bolt.Tx
+}
+
+// View calls fn with the batch transaction.
+func (s txStore) View(fn func(*bolt.Tx) error) error {
+	return fn(s.tx)
+}
+
+// Update calls fn with the batch transaction.
+func (s txStore) Update(fn func(*bolt.Tx) error) error {
+	if !s.tx.Writable() {
+		return bolt.ErrTxNotWritable
+	}
+	return fn(s.tx)
+}
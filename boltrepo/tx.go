@@ -0,0 +1,50 @@
+package boltrepo
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.RepositoryTx = (*Tx)(nil)
+
+// Tx provides access to a bolt repository within a batch transaction.
+type Tx struct {
+	db txStore
+}
+
+// Batch calls fn with a transaction that reads from and writes to the
+// repository within a single bolt transaction. The transaction is
+// committed, and synced to disk once, when fn returns nil. It is rolled
+// back when fn returns an error.
+func (repo Repository) Batch(fn func(tx drivestream.RepositoryTx) error) error {
+	return repo.db.Update(func(tx *bolt.Tx) error {
+		return fn(Tx{db: txStore{tx: tx}})
+	})
+}
+
+// Drives returns a drive map.
+func (tx Tx) Drives() drivestream.DriveMap {
+	return Drives{db: tx.db}
+}
+
+// Drive returns a drive reference.
+func (tx Tx) Drive(driveID resource.ID) drivestream.DriveReference {
+	return Drive{
+		db:    tx.db,
+		drive: driveID,
+	}
+}
+
+// Files returns a file map.
+func (tx Tx) Files() drivestream.FileMap {
+	return Files{db: tx.db}
+}
+
+// File returns a file reference.
+func (tx Tx) File(fileID resource.ID) drivestream.FileReference {
+	return File{
+		db:   tx.db,
+		file: fileID,
+	}
+}
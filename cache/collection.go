@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/page"
+)
+
+var (
+	_ collection.Sequence  = (*Collections)(nil)
+	_ collection.Reference = (*Collection)(nil)
+	_ page.Sequence        = (*Pages)(nil)
+	_ page.Reference       = (*Page)(nil)
+)
+
+// Collections is a collection sequence within a caching repository.
+type Collections struct {
+	collection.Sequence
+	repo *Repository
+}
+
+// Ref returns a collection reference for the sequence number.
+func (ref Collections) Ref(seqNum collection.SeqNum) collection.Reference {
+	return Collection{Reference: ref.Sequence.Ref(seqNum), repo: ref.repo}
+}
+
+// Collection is a collection reference within a caching repository.
+type Collection struct {
+	collection.Reference
+	repo *Repository
+}
+
+// Pages returns the page sequence for the collection.
+func (ref Collection) Pages() page.Sequence {
+	return Pages{Sequence: ref.Reference.Pages(), col: ref.Reference, repo: ref.repo}
+}
+
+// Page returns a page reference.
+func (ref Collection) Page(seqNum page.SeqNum) page.Reference {
+	return Page{Reference: ref.Reference.Page(seqNum), col: ref.Reference, repo: ref.repo}
+}
+
+// Prune discards the changes held by the pages of the collection, along
+// with any cached copies of its pages.
+func (ref Collection) Prune() (collection.PruneData, error) {
+	ref.repo.pruning.Lock()
+	defer ref.repo.pruning.Unlock()
+
+	data, err := ref.Reference.Prune()
+	ref.repo.forgetPages(ref.Reference)
+	return data, err
+}
+
+// Pages is a page sequence within a caching repository.
+type Pages struct {
+	page.Sequence
+	col  collection.Reference
+	repo *Repository
+}
+
+// Ref returns a page reference for the sequence number.
+func (ref Pages) Ref(seqNum page.SeqNum) page.Reference {
+	return Page{Reference: ref.Sequence.Ref(seqNum), col: ref.col, repo: ref.repo}
+}
+
+// Clear removes all pages from the sequence, along with any cached copies
+// of them.
+func (ref Pages) Clear() error {
+	err := ref.Sequence.Clear()
+	ref.repo.forgetPages(ref.col)
+	return err
+}
+
+// Page is a page reference within a caching repository. The data of pages
+// within finalized collections is cached.
+type Page struct {
+	page.Reference
+	col  collection.Reference
+	repo *Repository
+}
+
+// Data returns the page data.
+func (ref Page) Data() (page.Data, error) {
+	key := pageKey{
+		collectionKey: collectionKey{drive: ref.col.Drive(), collection: ref.col.SeqNum()},
+		page:          ref.SeqNum(),
+	}
+	if value, ok := ref.repo.pages.Get(key); ok {
+		return value.(page.Data), nil
+	}
+
+	ref.repo.pruning.RLock()
+	defer ref.repo.pruning.RUnlock()
+
+	data, err := ref.Reference.Data()
+	if err != nil {
+		return data, err
+	}
+	finalized, err := ref.repo.isFinalized(ref.col)
+	if err != nil {
+		return page.Data{}, err
+	}
+	if finalized {
+		ref.repo.pages.Add(key, data)
+	}
+	return data, nil
+}
+
+// forgetPages removes the cached pages of col.
+func (repo *Repository) forgetPages(col collection.Reference) {
+	target := collectionKey{drive: col.Drive(), collection: col.SeqNum()}
+	repo.pages.RemoveIf(func(key interface{}) bool {
+		return key.(pageKey).collectionKey == target
+	})
+}
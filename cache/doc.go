@@ -0,0 +1,11 @@
+// Package cache provides a drivestream repository decorator that caches
+// immutable data in memory.
+//
+// Reading a page, file version or drive version from a repository
+// typically involves a database transaction and the decoding of the
+// value. The cache keeps the most recently used pages of finalized
+// collections, file versions and drive versions in bounded LRU caches, so
+// that repeated reads of the same data are served from memory.
+//
+// Cached values are shared between callers and must not be modified.
+package cache
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ drivestream.DriveMap       = (*Drives)(nil)
+	_ drivestream.DriveReference = (*Drive)(nil)
+)
+
+// Drives is a map of drives within a caching repository.
+type Drives struct {
+	drivestream.DriveMap
+	repo *Repository
+}
+
+// Ref returns a drive reference.
+func (ref Drives) Ref(driveID resource.ID) drivestream.DriveReference {
+	return Drive{DriveReference: ref.DriveMap.Ref(driveID), repo: ref.repo}
+}
+
+// Drive is a drive reference within a caching repository.
+type Drive struct {
+	drivestream.DriveReference
+	repo *Repository
+}
+
+// Collections returns the collection sequence for the drive.
+func (ref Drive) Collections() collection.Sequence {
+	return Collections{Sequence: ref.DriveReference.Collections(), repo: ref.repo}
+}
+
+// Collection returns a collection reference.
+func (ref Drive) Collection(seqNum collection.SeqNum) collection.Reference {
+	return Collection{Reference: ref.DriveReference.Collection(seqNum), repo: ref.repo}
+}
+
+// Versions returns the version sequence for the drive.
+func (ref Drive) Versions() driveversion.Sequence {
+	return DriveVersions{Sequence: ref.DriveReference.Versions(), repo: ref.repo}
+}
+
+// Version returns a drive version reference.
+func (ref Drive) Version(v resource.Version) driveversion.Reference {
+	return DriveVersion{Reference: ref.DriveReference.Version(v), repo: ref.repo}
+}
+
+// View returns a view of the drive.
+func (ref Drive) View() driveview.Reference {
+	return DriveView{Reference: ref.DriveReference.View(), repo: ref.repo}
+}
+
+// At returns a version reference of the drive at a particular commit.
+func (ref Drive) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	return ref.View().At(seqNum)
+}
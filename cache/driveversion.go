@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ driveversion.Sequence  = (*DriveVersions)(nil)
+	_ driveversion.Reference = (*DriveVersion)(nil)
+	_ driveview.Reference    = (*DriveView)(nil)
+)
+
+// DriveVersions is a drive version sequence within a caching repository.
+type DriveVersions struct {
+	driveversion.Sequence
+	repo *Repository
+}
+
+// Ref returns a drive version reference for the version number.
+func (ref DriveVersions) Ref(v resource.Version) driveversion.Reference {
+	return DriveVersion{Reference: ref.Sequence.Ref(v), repo: ref.repo}
+}
+
+// DriveVersion is a drive version reference within a caching repository.
+// The data of drive versions is cached.
+type DriveVersion struct {
+	driveversion.Reference
+	repo *Repository
+}
+
+// Data returns the data of the drive version.
+func (ref DriveVersion) Data() (resource.DriveData, error) {
+	key := driveVersionKey{drive: ref.Drive(), version: ref.Version()}
+	if value, ok := ref.repo.driveVersions.Get(key); ok {
+		return value.(resource.DriveData), nil
+	}
+	data, err := ref.Reference.Data()
+	if err != nil {
+		return data, err
+	}
+	ref.repo.driveVersions.Add(key, data)
+	return data, nil
+}
+
+// DriveView is a drive view reference within a caching repository.
+type DriveView struct {
+	driveview.Reference
+	repo *Repository
+}
+
+// At returns the version reference of the drive at a particular commit.
+func (ref DriveView) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	r, err := ref.Reference.At(seqNum)
+	if err != nil {
+		return nil, err
+	}
+	return DriveVersion{Reference: r, repo: ref.repo}, nil
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ drivestream.FileMap       = (*Files)(nil)
+	_ drivestream.FileReference = (*File)(nil)
+	_ fileversion.Map           = (*FileVersions)(nil)
+	_ fileversion.Reference     = (*FileVersion)(nil)
+	_ fileview.Map              = (*FileViews)(nil)
+	_ fileview.Reference        = (*FileView)(nil)
+)
+
+// Files is a map of files within a caching repository.
+type Files struct {
+	drivestream.FileMap
+	repo *Repository
+}
+
+// Ref returns a file reference.
+func (ref Files) Ref(fileID resource.ID) drivestream.FileReference {
+	return File{FileReference: ref.FileMap.Ref(fileID), repo: ref.repo}
+}
+
+// File is a file reference within a caching repository.
+type File struct {
+	drivestream.FileReference
+	repo *Repository
+}
+
+// Versions returns the version map for the file.
+func (ref File) Versions() fileversion.Map {
+	return FileVersions{Map: ref.FileReference.Versions(), repo: ref.repo}
+}
+
+// Version returns a file version reference.
+func (ref File) Version(v resource.Version) fileversion.Reference {
+	return FileVersion{Reference: ref.FileReference.Version(v), repo: ref.repo}
+}
+
+// Views returns the view map for the file.
+func (ref File) Views() fileview.Map {
+	return FileViews{Map: ref.FileReference.Views(), repo: ref.repo}
+}
+
+// View returns a view of the file for a particular drive.
+func (ref File) View(driveID resource.ID) fileview.Reference {
+	return FileView{Reference: ref.FileReference.View(driveID), repo: ref.repo}
+}
+
+// FileVersions is a map of file versions within a caching repository.
+type FileVersions struct {
+	fileversion.Map
+	repo *Repository
+}
+
+// Ref returns a file version reference for the version number.
+func (ref FileVersions) Ref(v resource.Version) fileversion.Reference {
+	return FileVersion{Reference: ref.Map.Ref(v), repo: ref.repo}
+}
+
+// FileVersion is a file version reference within a caching repository.
+// The data of file versions is cached.
+type FileVersion struct {
+	fileversion.Reference
+	repo *Repository
+}
+
+// Data returns the data of the file version.
+func (ref FileVersion) Data() (resource.FileData, error) {
+	key := fileVersionKey{file: ref.File(), version: ref.Version()}
+	if value, ok := ref.repo.fileVersions.Get(key); ok {
+		return value.(resource.FileData), nil
+	}
+	data, err := ref.Reference.Data()
+	if err != nil {
+		return data, err
+	}
+	ref.repo.fileVersions.Add(key, data)
+	return data, nil
+}
+
+// FileViews is a map of file views within a caching repository.
+type FileViews struct {
+	fileview.Map
+	repo *Repository
+}
+
+// Ref returns a view of the file for a particular drive.
+func (ref FileViews) Ref(driveID resource.ID) fileview.Reference {
+	return FileView{Reference: ref.Map.Ref(driveID), repo: ref.repo}
+}
+
+// FileView is a file view reference within a caching repository.
+type FileView struct {
+	fileview.Reference
+	repo *Repository
+}
+
+// At returns the version reference of the file at a particular commit.
+func (ref FileView) At(seqNum commit.SeqNum) (fileversion.Reference, error) {
+	r, err := ref.Reference.At(seqNum)
+	if err != nil {
+		return nil, err
+	}
+	return FileVersion{Reference: r, repo: ref.repo}, nil
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+type collectionKey struct {
+	drive      resource.ID
+	collection collection.SeqNum
+}
+
+type pageKey struct {
+	collectionKey
+	page page.SeqNum
+}
+
+type fileVersionKey struct {
+	file    resource.ID
+	version resource.Version
+}
+
+type driveVersionKey struct {
+	drive   resource.ID
+	version resource.Version
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a least recently used cache that is safe for concurrent use.
+// A limit of zero or less disables the cache.
+type lru struct {
+	mu        sync.Mutex
+	limit     int
+	ll        *list.List
+	items     map[interface{}]*list.Element
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func newLRU(limit int) *lru {
+	return &lru{
+		limit: limit,
+		ll:    list.New(),
+		items: make(map[interface{}]*list.Element),
+	}
+}
+
+// Get returns the value cached for key, and records a hit or a miss.
+func (c *lru) Get(key interface{}) (value interface{}, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Add caches value for key, evicting the least recently used entry if the
+// cache is full.
+func (c *lru) Add(key, value interface{}) {
+	if c.limit <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value})
+	for c.ll.Len() > c.limit {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+		c.evictions++
+	}
+}
+
+// RemoveIf removes every entry with a key for which match returns true.
+func (c *lru) RemoveIf(match func(key interface{}) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if match(key) {
+			c.ll.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Stats returns statistics about the cache.
+func (c *lru) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Entries:   c.ll.Len(),
+		Limit:     c.limit,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
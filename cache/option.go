@@ -0,0 +1,35 @@
+package cache
+
+// Default cache limits.
+const (
+	DefaultPageLimit         = 256
+	DefaultFileVersionLimit  = 65536
+	DefaultDriveVersionLimit = 1024
+)
+
+// Option is a configuration option for a caching repository.
+type Option func(*Repository)
+
+// WithPageLimit sets the maximum number of pages held by the cache. A limit
+// of zero disables the caching of pages.
+func WithPageLimit(n int) Option {
+	return func(repo *Repository) {
+		repo.pages = newLRU(n)
+	}
+}
+
+// WithFileVersionLimit sets the maximum number of file versions held by the
+// cache. A limit of zero disables the caching of file versions.
+func WithFileVersionLimit(n int) Option {
+	return func(repo *Repository) {
+		repo.fileVersions = newLRU(n)
+	}
+}
+
+// WithDriveVersionLimit sets the maximum number of drive versions held by
+// the cache. A limit of zero disables the caching of drive versions.
+func WithDriveVersionLimit(n int) Option {
+	return func(repo *Repository) {
+		repo.driveVersions = newLRU(n)
+	}
+}
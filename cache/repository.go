@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.Repository = (*Repository)(nil)
+
+// Repository is a drivestream repository that caches immutable data read
+// from the repository it wraps. It is safe for concurrent use if the
+// wrapped repository is. It should be created by calling New.
+type Repository struct {
+	repo          drivestream.Repository
+	pages         *lru
+	fileVersions  *lru
+	driveVersions *lru
+
+	// finalized records collections that are known to be finalized. Once
+	// finalized, a collection's pages can only be changed by pruning.
+	finalized sync.Map
+
+	// pruning is held exclusively while a collection is pruned, so that
+	// pages being read at the same time can't be cached after their
+	// collection's cached pages have been discarded.
+	pruning sync.RWMutex
+}
+
+// New returns a caching repository that wraps repo.
+func New(repo drivestream.Repository, options ...Option) *Repository {
+	c := &Repository{
+		repo:          repo,
+		pages:         newLRU(DefaultPageLimit),
+		fileVersions:  newLRU(DefaultFileVersionLimit),
+		driveVersions: newLRU(DefaultDriveVersionLimit),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Type returns a string describing the type of the repository.
+func (repo *Repository) Type() string {
+	return repo.repo.Type()
+}
+
+// Drives returns a drive map.
+func (repo *Repository) Drives() drivestream.DriveMap {
+	return Drives{DriveMap: repo.repo.Drives(), repo: repo}
+}
+
+// Drive returns a drive reference.
+func (repo *Repository) Drive(driveID resource.ID) drivestream.DriveReference {
+	return Drive{DriveReference: repo.repo.Drive(driveID), repo: repo}
+}
+
+// Files returns a file map.
+func (repo *Repository) Files() drivestream.FileMap {
+	return Files{FileMap: repo.repo.Files(), repo: repo}
+}
+
+// File returns a file reference.
+func (repo *Repository) File(fileID resource.ID) drivestream.FileReference {
+	return File{FileReference: repo.repo.File(fileID), repo: repo}
+}
+
+// Batch calls fn with a transaction of the wrapped repository. Reads and
+// writes within the transaction bypass the caches, because the data they
+// see isn't committed until fn returns. Pages of collections pruned
+// within a batch aren't discarded from the cache, so collections should be
+// pruned through the caching repository instead.
+func (repo *Repository) Batch(fn func(tx drivestream.RepositoryTx) error) error {
+	return repo.repo.Batch(fn)
+}
+
+// Stats returns statistics about the repository's caches.
+func (repo *Repository) Stats() RepositoryStats {
+	return RepositoryStats{
+		Pages:         repo.pages.Stats(),
+		FileVersions:  repo.fileVersions.Stats(),
+		DriveVersions: repo.driveVersions.Stats(),
+	}
+}
+
+// isFinalized returns true if col is in a finalized state.
+func (repo *Repository) isFinalized(col collection.Reference) (bool, error) {
+	key := collectionKey{drive: col.Drive(), collection: col.SeqNum()}
+	if _, ok := repo.finalized.Load(key); ok {
+		return true, nil
+	}
+	next, err := col.States().Next()
+	if err != nil || next == 0 {
+		return false, err
+	}
+	state, err := col.State(next - 1).Data()
+	if err != nil {
+		return false, err
+	}
+	if state.Phase != collection.PhaseFinalized {
+		return false, nil
+	}
+	repo.finalized.Store(key, true)
+	return true, nil
+}
@@ -0,0 +1,43 @@
+package cache
+
+import "fmt"
+
+// Stats hold statistics about a cache.
+type Stats struct {
+	Entries   int
+	Limit     int
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// HitRate returns the fraction of lookups that were served from the
+// cache.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// RepositoryStats hold statistics about each of the caches of a
+// repository.
+type RepositoryStats struct {
+	Pages         Stats
+	FileVersions  Stats
+	DriveVersions Stats
+}
+
+// Summary returns a slice of strings summarizing the statistics.
+func (rs RepositoryStats) Summary() []string {
+	line := func(name string, s Stats) string {
+		return fmt.Sprintf("  %s: %d/%d entries, %d hits, %d misses (%.1f%%), %d evictions", name, s.Entries, s.Limit, s.Hits, s.Misses, s.HitRate()*100, s.Evictions)
+	}
+	return []string{
+		"Cache:",
+		line("Pages", rs.Pages),
+		line("File Versions", rs.FileVersions),
+		line("Drive Versions", rs.DriveVersions),
+	}
+}
@@ -0,0 +1,43 @@
+package changewatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Channel describes a notification channel for a drive.
+type Channel struct {
+	// ID is the unique identifier of the channel, chosen by the manager.
+	ID string
+
+	// ResourceID identifies the watched resource. It is assigned when the
+	// channel is registered and is needed to stop the channel.
+	ResourceID string
+
+	// Drive is the drive being watched.
+	Drive resource.ID
+
+	// Address is the URL that notifications are sent to.
+	Address string
+
+	// Token is a secret that accompanies each notification sent to the
+	// channel.
+	Token string
+
+	// Expiration is the time at which the channel expires. When a channel
+	// is registered it holds the requested expiration, or zero to accept
+	// the registrar's default.
+	Expiration time.Time
+}
+
+// Registrar registers and stops notification channels.
+type Registrar interface {
+	// Watch registers ch for changes within its drive and returns the
+	// channel as registered.
+	Watch(ctx context.Context, ch Channel) (Channel, error)
+
+	// Stop stops notifications for ch.
+	Stop(ctx context.Context, ch Channel) error
+}
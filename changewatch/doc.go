@@ -0,0 +1,14 @@
+// Package changewatch receives push notifications for changes within
+// drives.
+//
+// A Manager registers a notification channel for each drive it watches
+// through a Registrar, such as the Drive API's changes.watch method, and
+// renews each channel before it expires. The manager is also an
+// http.Handler that receives the notifications sent to its channels and
+// reports each affected drive through Notified and Pending.
+//
+// Notifications only report that something changed. Callers are expected
+// to respond by updating the affected drive, and to keep polling every
+// drive at a relaxed interval in case notifications are lost or a channel
+// can't be registered.
+package changewatch
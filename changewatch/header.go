@@ -0,0 +1,20 @@
+package changewatch
+
+// Notification header names.
+const (
+	ChannelIDHeader     = "X-Goog-Channel-ID"
+	ChannelTokenHeader  = "X-Goog-Channel-Token"
+	ResourceIDHeader    = "X-Goog-Resource-ID"
+	ResourceStateHeader = "X-Goog-Resource-State"
+	MessageNumberHeader = "X-Goog-Message-Number"
+)
+
+// Resource states.
+const (
+	// StateSync is sent once when a channel is registered. It doesn't
+	// indicate a change.
+	StateSync = "sync"
+
+	// StateChange is sent when changes are made within the watched drive.
+	StateChange = "change"
+)
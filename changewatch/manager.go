@@ -0,0 +1,377 @@
+package changewatch
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Default timing of a manager.
+const (
+	DefaultRenewBefore = 5 * time.Minute
+	DefaultRetryDelay  = time.Minute
+)
+
+// stopTimeout limits the time spent stopping channels when a manager shuts
+// down.
+const stopTimeout = 10 * time.Second
+
+// Manager maintains notification channels for a set of drives and receives
+// the notifications sent to them. It should be created by calling
+// NewManager.
+type Manager struct {
+	registrar   Registrar
+	address     string
+	lifetime    time.Duration
+	renewBefore time.Duration
+	retryDelay  time.Duration
+	stdout      io.Writer
+
+	mutex    sync.Mutex
+	channels map[string]Channel     // Channels that can receive notifications, by ID
+	drives   map[resource.ID]string // The current channel of each drive
+	retry    map[resource.ID]time.Time
+	pending  []resource.ID
+	queued   map[resource.ID]bool
+	changed  chan struct{}
+	notified chan struct{}
+}
+
+// NewManager returns a manager that registers channels with registrar.
+// Notifications for the channels are sent to address, which must be a
+// publicly reachable HTTPS URL that is served by the manager.
+func NewManager(registrar Registrar, address string, options ...Option) *Manager {
+	m := &Manager{
+		registrar:   registrar,
+		address:     address,
+		renewBefore: DefaultRenewBefore,
+		retryDelay:  DefaultRetryDelay,
+		channels:    make(map[string]Channel),
+		drives:      make(map[resource.ID]string),
+		retry:       make(map[resource.ID]time.Time),
+		queued:      make(map[resource.ID]bool),
+		changed:     make(chan struct{}, 1),
+		notified:    make(chan struct{}, 1),
+	}
+	for _, opt := range options {
+		opt(m)
+	}
+	return m
+}
+
+// Watch registers a channel for driveID if the manager doesn't already
+// have one.
+func (m *Manager) Watch(ctx context.Context, driveID resource.ID) error {
+	m.mutex.Lock()
+	_, ok := m.drives[driveID]
+	m.mutex.Unlock()
+	if ok {
+		return nil
+	}
+
+	ch, err := m.register(ctx, driveID)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	m.channels[ch.ID] = ch
+	m.drives[driveID] = ch.ID
+	m.mutex.Unlock()
+
+	m.log("DRIVE %s: WATCH: Registered channel %s (expires %s)\n", driveID, ch.ID, expiration(ch))
+	m.signal(m.changed)
+	return nil
+}
+
+// Unwatch stops the channel for driveID.
+func (m *Manager) Unwatch(ctx context.Context, driveID resource.ID) error {
+	m.mutex.Lock()
+	id, ok := m.drives[driveID]
+	ch := m.channels[id]
+	delete(m.drives, driveID)
+	delete(m.channels, id)
+	delete(m.retry, driveID)
+	m.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+	m.signal(m.changed)
+	return m.registrar.Stop(ctx, ch)
+}
+
+// Watching returns true if the manager has a channel for driveID.
+func (m *Manager) Watching(driveID resource.ID) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.drives[driveID]
+	return ok
+}
+
+// Notified returns a channel that receives a value when drives have
+// pending notifications. The drives can be retrieved by calling Pending.
+func (m *Manager) Notified() <-chan struct{} {
+	return m.notified
+}
+
+// Pending returns the drives that have received notifications since the
+// last call to Pending, in the order they were first notified.
+func (m *Manager) Pending() []resource.ID {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	pending := m.pending
+	m.pending = nil
+	m.queued = make(map[resource.ID]bool)
+	return pending
+}
+
+// Run renews channels before they expire until ctx is cancelled, and then
+// stops every channel.
+//
+// When a channel can't be renewed the manager tries again after its retry
+// delay. A channel that expires before it could be renewed is dropped, and
+// notifications for its drive cease until Watch is called again.
+func (m *Manager) Run(ctx context.Context) {
+	defer m.stopAll()
+
+	for {
+		wait := m.renewDue(ctx)
+
+		var (
+			t     *time.Timer
+			timer <-chan time.Time
+		)
+		if wait >= 0 {
+			t = time.NewTimer(wait)
+			timer = t.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-m.changed:
+		case <-timer:
+		}
+		if t != nil {
+			t.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// ServeHTTP receives a notification.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	m.mutex.Lock()
+	ch, ok := m.channels[r.Header.Get(ChannelIDHeader)]
+	m.mutex.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(ChannelTokenHeader)), []byte(ch.Token)) != 1 {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if r.Header.Get(ResourceStateHeader) != StateSync {
+		m.queue(ch.Drive)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// register registers a new channel for driveID.
+func (m *Manager) register(ctx context.Context, driveID resource.ID) (Channel, error) {
+	id, err := randomString()
+	if err != nil {
+		return Channel{}, err
+	}
+	token, err := randomString()
+	if err != nil {
+		return Channel{}, err
+	}
+	ch := Channel{
+		ID:      id,
+		Drive:   driveID,
+		Address: m.address,
+		Token:   token,
+	}
+	if m.lifetime > 0 {
+		ch.Expiration = time.Now().Add(m.lifetime)
+	}
+
+	registered, err := m.registrar.Watch(ctx, ch)
+	if err != nil {
+		return Channel{}, fmt.Errorf("failed to register notification channel for drive %s: %v", driveID, err)
+	}
+	// The registrar may not echo every member of the channel.
+	registered.ID, registered.Drive, registered.Token = ch.ID, ch.Drive, ch.Token
+	return registered, nil
+}
+
+// renewDue renews the channels that are due for renewal and returns the
+// time until the next renewal is due, or -1 if no renewal is scheduled.
+func (m *Manager) renewDue(ctx context.Context) time.Duration {
+	now := time.Now()
+
+	type renewal struct {
+		drive resource.ID
+		old   Channel
+	}
+	var due []renewal
+	next := time.Duration(-1)
+	schedule := func(at time.Time) {
+		if wait := at.Sub(now); next < 0 || wait < next {
+			next = wait
+		}
+	}
+
+	m.mutex.Lock()
+	for driveID, id := range m.drives {
+		ch := m.channels[id]
+		if ch.Expiration.IsZero() {
+			continue
+		}
+		renewAt := ch.Expiration.Add(-m.renewBefore)
+		if retryAt, ok := m.retry[driveID]; ok && retryAt.After(renewAt) {
+			renewAt = retryAt
+		}
+		if renewAt.After(now) {
+			schedule(renewAt)
+			continue
+		}
+		due = append(due, renewal{drive: driveID, old: ch})
+	}
+	m.mutex.Unlock()
+
+	for _, r := range due {
+		ch, err := m.register(ctx, r.drive)
+		if err != nil {
+			m.log("DRIVE %s: WATCH: %v\n", r.drive, err)
+			m.mutex.Lock()
+			if !time.Now().Before(r.old.Expiration) {
+				// The old channel has expired, so the drive will only be
+				// updated by polling.
+				delete(m.drives, r.drive)
+				delete(m.channels, r.old.ID)
+				delete(m.retry, r.drive)
+				m.mutex.Unlock()
+				m.log("DRIVE %s: WATCH: Channel %s expired\n", r.drive, r.old.ID)
+				continue
+			}
+			retryAt := time.Now().Add(m.retryDelay)
+			m.retry[r.drive] = retryAt
+			m.mutex.Unlock()
+			schedule(retryAt)
+			continue
+		}
+
+		m.mutex.Lock()
+		current, watched := m.drives[r.drive]
+		if !watched || current != r.old.ID {
+			// The drive was unwatched while the channel was registered.
+			m.mutex.Unlock()
+			m.registrar.Stop(ctx, ch)
+			continue
+		}
+		m.channels[ch.ID] = ch
+		m.drives[r.drive] = ch.ID
+		delete(m.channels, r.old.ID)
+		delete(m.retry, r.drive)
+		m.mutex.Unlock()
+
+		// The old channel is stopped after the new one is in place so
+		// that no notifications are missed.
+		if err := m.registrar.Stop(ctx, r.old); err != nil {
+			m.log("DRIVE %s: WATCH: Failed to stop channel %s: %v\n", r.drive, r.old.ID, err)
+		}
+		m.log("DRIVE %s: WATCH: Renewed channel %s as %s (expires %s)\n", r.drive, r.old.ID, ch.ID, expiration(ch))
+		if !ch.Expiration.IsZero() {
+			schedule(ch.Expiration.Add(-m.renewBefore))
+		}
+	}
+
+	if next < 0 && len(due) > 0 {
+		return 0
+	}
+	return next
+}
+
+// stopAll stops every channel.
+func (m *Manager) stopAll() {
+	m.mutex.Lock()
+	channels := make([]Channel, 0, len(m.channels))
+	for _, ch := range m.channels {
+		channels = append(channels, ch)
+	}
+	m.channels = make(map[string]Channel)
+	m.drives = make(map[resource.ID]string)
+	m.retry = make(map[resource.ID]time.Time)
+	m.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	for _, ch := range channels {
+		if err := m.registrar.Stop(ctx, ch); err != nil {
+			m.log("DRIVE %s: WATCH: Failed to stop channel %s: %v\n", ch.Drive, ch.ID, err)
+		}
+	}
+}
+
+// queue records a notification for driveID.
+func (m *Manager) queue(driveID resource.ID) {
+	m.mutex.Lock()
+	if !m.queued[driveID] {
+		m.queued[driveID] = true
+		m.pending = append(m.pending, driveID)
+	}
+	m.mutex.Unlock()
+	m.signal(m.notified)
+}
+
+// signal sends a value to c without blocking.
+func (m *Manager) signal(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) log(format string, v ...interface{}) {
+	if m.stdout != nil {
+		fmt.Fprintf(m.stdout, format, v...)
+	}
+}
+
+// randomString returns a random hexadecimal string suitable for channel
+// IDs and tokens.
+func randomString() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// expiration returns the expiration time of ch as a string.
+func expiration(ch Channel) string {
+	if ch.Expiration.IsZero() {
+		return "never"
+	}
+	return ch.Expiration.Format(time.RFC3339)
+}
@@ -0,0 +1,202 @@
+package changewatch
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// registrar is a fake registrar that records the channels it registers and
+// stops. Registered channels expire after its lifetime.
+type registrar struct {
+	mutex    sync.Mutex
+	lifetime time.Duration
+	fail     bool
+	watched  []Channel
+	stopped  []Channel
+}
+
+func (r *registrar) Watch(ctx context.Context, ch Channel) (Channel, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.fail {
+		return Channel{}, errors.New("registration failed")
+	}
+	ch.ResourceID = "resource-" + string(ch.Drive)
+	ch.Expiration = time.Now().Add(r.lifetime)
+	r.watched = append(r.watched, ch)
+	return ch, nil
+}
+
+func (r *registrar) Stop(ctx context.Context, ch Channel) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.stopped = append(r.stopped, ch)
+	return nil
+}
+
+// last returns the channel that was most recently registered.
+func (r *registrar) last() Channel {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.watched[len(r.watched)-1]
+}
+
+// newManager returns a manager that registers channels with reg and
+// receives their notifications through a test server.
+func newManager(t *testing.T, reg *registrar, options ...Option) (*Manager, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(nil)
+	m := NewManager(reg, "http://"+srv.Listener.Addr().String(), options...)
+	srv.Config.Handler = m
+	srv.Start()
+	return m, srv
+}
+
+// watch registers a channel for driveID with m and returns it.
+func watch(t *testing.T, m *Manager, reg *registrar, driveID resource.ID) Channel {
+	t.Helper()
+	if err := m.Watch(context.Background(), driveID); err != nil {
+		t.Fatal(err)
+	}
+	return reg.last()
+}
+
+// expectRejected fails the test if err doesn't report that a notification
+// was rejected with the given status.
+func expectRejected(t *testing.T, err error, status string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), status) {
+		t.Fatalf("expected the notification to be rejected with %s, got %v", status, err)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	reg := &registrar{lifetime: time.Hour}
+	m, srv := newManager(t, reg)
+	defer srv.Close()
+
+	ctx := context.Background()
+	a := watch(t, m, reg, "a")
+	b := watch(t, m, reg, "b")
+
+	// The sync message sent when a channel is registered isn't a change.
+	if err := Send(ctx, srv.Client(), a, StateSync, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pending := m.Pending(); len(pending) != 0 {
+		t.Fatalf("expected no pending drives after a sync message, got %v", pending)
+	}
+
+	// Notifications for a drive are coalesced until Pending is called.
+	for i, ch := range []Channel{a, b, a, a} {
+		if err := Send(ctx, srv.Client(), ch, StateChange, int64(i+2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-m.Notified():
+	default:
+		t.Fatal("the manager didn't signal that drives were notified")
+	}
+	if pending := m.Pending(); len(pending) != 2 || pending[0] != "a" || pending[1] != "b" {
+		t.Fatalf("expected pending drives [a b], got %v", pending)
+	}
+	if pending := m.Pending(); len(pending) != 0 {
+		t.Fatalf("expected no pending drives, got %v", pending)
+	}
+
+	forged := a
+	forged.Token = "forged"
+	expectRejected(t, Send(ctx, srv.Client(), forged, StateChange, 6), "403")
+
+	unknown := a
+	unknown.ID = "unknown"
+	expectRejected(t, Send(ctx, srv.Client(), unknown, StateChange, 7), "404")
+
+	if pending := m.Pending(); len(pending) != 0 {
+		t.Fatalf("expected rejected notifications to be ignored, got %v", pending)
+	}
+}
+
+func TestRenewDue(t *testing.T) {
+	reg := &registrar{lifetime: time.Minute}
+	m, srv := newManager(t, reg, WithRenewBefore(5*time.Minute))
+	defer srv.Close()
+
+	ctx := context.Background()
+	old := watch(t, m, reg, "a")
+
+	// The channel expires within the renewal window, so it is renewed
+	// while it is still valid.
+	reg.lifetime = time.Hour
+	wait := m.renewDue(ctx)
+	renewed := reg.last()
+	if renewed.ID == old.ID {
+		t.Fatal("the channel was not renewed")
+	}
+	if !time.Now().Before(old.Expiration) {
+		t.Fatal("the channel expired before it was renewed")
+	}
+	if len(reg.stopped) != 1 || reg.stopped[0].ID != old.ID {
+		t.Fatalf("expected the old channel to be stopped, got %v", reg.stopped)
+	}
+	if wait < 54*time.Minute || wait > 56*time.Minute {
+		t.Fatalf("expected the next renewal in about 55m, got %s", wait)
+	}
+
+	expectRejected(t, Send(ctx, srv.Client(), old, StateChange, 1), "404")
+	if err := Send(ctx, srv.Client(), renewed, StateChange, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pending := m.Pending(); len(pending) != 1 || pending[0] != "a" {
+		t.Fatalf("expected pending drives [a], got %v", pending)
+	}
+
+	// Channels that aren't due are left alone.
+	m.renewDue(ctx)
+	if last := reg.last(); last.ID != renewed.ID {
+		t.Fatalf("a channel that wasn't due was renewed as %s", last.ID)
+	}
+}
+
+func TestRenewDueExpired(t *testing.T) {
+	reg := &registrar{lifetime: time.Minute}
+	m, srv := newManager(t, reg, WithRenewBefore(5*time.Minute), WithRetryDelay(time.Second))
+	defer srv.Close()
+
+	ctx := context.Background()
+	ch := watch(t, m, reg, "a")
+
+	// A failed renewal is retried while the channel is still valid.
+	reg.fail = true
+	if wait := m.renewDue(ctx); wait <= 0 || wait > 2*time.Second {
+		t.Fatalf("expected a retry within the retry delay, got %s", wait)
+	}
+	if !m.Watching("a") {
+		t.Fatal("the channel was dropped before it expired")
+	}
+
+	// Once the channel has expired, a failed renewal drops it.
+	m.mutex.Lock()
+	expired := m.channels[ch.ID]
+	expired.Expiration = time.Now().Add(-time.Second)
+	m.channels[ch.ID] = expired
+	m.retry["a"] = time.Now().Add(-time.Second)
+	m.mutex.Unlock()
+
+	m.renewDue(ctx)
+	if m.Watching("a") {
+		t.Fatal("the expired channel was not dropped")
+	}
+	if wait := m.renewDue(ctx); wait != -1 {
+		t.Fatalf("expected no renewal to be scheduled, got %s", wait)
+	}
+	expectRejected(t, Send(ctx, srv.Client(), ch, StateChange, 1), "404")
+}
@@ -0,0 +1,41 @@
+package changewatch
+
+import (
+	"io"
+	"time"
+)
+
+// Option is a configuration option for a manager.
+type Option func(*Manager)
+
+// WithLifetime causes the manager to request channels that expire after d.
+// The registrar may impose a shorter lifetime. A lifetime of zero accepts
+// the registrar's default.
+func WithLifetime(d time.Duration) Option {
+	return func(m *Manager) {
+		m.lifetime = d
+	}
+}
+
+// WithRenewBefore causes the manager to renew each channel when it is
+// within d of its expiration.
+func WithRenewBefore(d time.Duration) Option {
+	return func(m *Manager) {
+		m.renewBefore = d
+	}
+}
+
+// WithRetryDelay causes the manager to wait d before trying again when a
+// channel can't be renewed.
+func WithRetryDelay(d time.Duration) Option {
+	return func(m *Manager) {
+		m.retryDelay = d
+	}
+}
+
+// WithLogger causes the manager to write log output to w.
+func WithLogger(w io.Writer) Option {
+	return func(m *Manager) {
+		m.stdout = w
+	}
+}
@@ -0,0 +1,41 @@
+package changewatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Send delivers a notification for ch to its address in the same manner as
+// the Drive API. It is intended for testing receivers without registering
+// real channels. If client is nil, http.DefaultClient is used.
+func Send(ctx context.Context, client *http.Client, ch Channel, state string, message int64) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ch.Address, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(ChannelIDHeader, ch.ID)
+	req.Header.Set(ResourceIDHeader, ch.ResourceID)
+	req.Header.Set(ResourceStateHeader, state)
+	req.Header.Set(MessageNumberHeader, strconv.FormatInt(message, 10))
+	if ch.Token != "" {
+		req.Header.Set(ChannelTokenHeader, ch.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification for channel %s was rejected: %s", ch.ID, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// archiveVersion is the version of the archive format written by export.
+const archiveVersion = 1
+
+// Kinds of archive entries.
+const (
+	archiveHeader          = "archive"
+	archiveCollection      = "collection"
+	archiveCollectionState = "collection-state"
+	archivePage            = "page"
+	archiveCommit          = "commit"
+	archiveCommitState     = "commit-state"
+	archiveCommitFiles     = "commit-files"
+	archiveCommitTree      = "commit-tree"
+	archiveFileVersion     = "file-version"
+	archiveDriveVersion    = "drive-version"
+	archiveDriveView       = "drive-view"
+	archiveActors          = "actors"
+	archiveConsumer        = "consumer"
+)
+
+// archiveEntry is a single line of an archive. An archive holds a complete
+// copy of the drives it contains, written in the order in which the
+// entries must be added to a repository.
+//
+// The meaning of Seq and Sub depend on the kind of entry:
+//
+//	Kind               Seq          Sub      Data
+//	archive            version      -        -
+//	collection         collection   -        collection.Data
+//	collection-state   collection   state    collection.State
+//	page               collection   page     page.Data
+//	commit             commit       -        commit.Data
+//	commit-state       commit       state    commit.State
+//	commit-files       commit       -        []commit.FileChange
+//	commit-tree        commit       -        []commit.TreeChange
+//	file-version       version      -        resource.FileData
+//	drive-version      version      -        resource.DriveData
+//	drive-view         commit       -        resource.Version
+//	actors             -            -        []driveactor.Entry
+//	consumer           -            -        consumer.Data
+//
+// Consumer entries also hold the name of the consumer.
+type archiveEntry struct {
+	Kind  string          `json:"kind"`
+	Drive resource.ID     `json:"drive,omitempty"`
+	File  resource.ID     `json:"file,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Seq   int64           `json:"seq"`
+	Sub   int64           `json:"sub,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// archiveWriter writes drives to an archive.
+type archiveWriter struct {
+	repo drivestream.Repository
+	enc  *json.Encoder
+	seen map[fileVersionKey]bool
+}
+
+// newArchiveWriter returns an archive writer for repo that writes to w.
+// The archive header is written immediately.
+func newArchiveWriter(w io.Writer, repo drivestream.Repository) (*archiveWriter, error) {
+	aw := &archiveWriter{
+		repo: repo,
+		enc:  json.NewEncoder(w),
+		seen: make(map[fileVersionKey]bool),
+	}
+	if err := aw.enc.Encode(archiveEntry{Kind: archiveHeader, Seq: archiveVersion}); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+func (w *archiveWriter) write(kind string, drive, file resource.ID, seq, sub int64, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return w.enc.Encode(archiveEntry{
+		Kind:  kind,
+		Drive: drive,
+		File:  file,
+		Seq:   seq,
+		Sub:   sub,
+		Data:  b,
+	})
+}
+
+// Drive writes all of the data for a drive to the archive.
+func (w *archiveWriter) Drive(ctx context.Context, driveID resource.ID) error {
+	if err := w.collections(ctx, driveID); err != nil {
+		return err
+	}
+	if err := w.commits(ctx, driveID); err != nil {
+		return err
+	}
+	if err := w.driveVersions(driveID); err != nil {
+		return err
+	}
+	if err := w.driveView(driveID); err != nil {
+		return err
+	}
+	if err := w.actors(driveID); err != nil {
+		return err
+	}
+	return w.consumers(driveID)
+}
+
+func (w *archiveWriter) collections(ctx context.Context, driveID resource.ID) error {
+	cursor, err := collection.NewCursor(w.repo.Drive(driveID).Collections())
+	if err != nil {
+		return err
+	}
+	for cursor.First(); cursor.Valid(); cursor.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		seqNum := int64(cursor.SeqNum())
+
+		reader, err := cursor.Reader()
+		if err != nil {
+			return err
+		}
+		data, err := reader.Data()
+		if err != nil {
+			return err
+		}
+		if err := w.write(archiveCollection, driveID, "", seqNum, 0, data); err != nil {
+			return err
+		}
+
+		states, err := reader.States()
+		if err != nil {
+			return err
+		}
+		for i, state := range states {
+			if err := w.write(archiveCollectionState, driveID, "", seqNum, int64(i), state); err != nil {
+				return err
+			}
+		}
+
+		for pageNum := page.SeqNum(0); pageNum < reader.NextPage(); pageNum++ {
+			pg, err := reader.Page(pageNum)
+			if err != nil {
+				return err
+			}
+			if err := w.write(archivePage, driveID, "", seqNum, int64(pageNum), pg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *archiveWriter) commits(ctx context.Context, driveID resource.ID) error {
+	cursor, err := commit.NewCursor(w.repo.Drive(driveID).Commits())
+	if err != nil {
+		return err
+	}
+	for cursor.First(); cursor.Valid(); cursor.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		seqNum := int64(cursor.SeqNum())
+		com := w.repo.Drive(driveID).Commit(cursor.SeqNum())
+
+		reader, err := cursor.Reader()
+		if err != nil {
+			return err
+		}
+		data, err := reader.Data()
+		if err != nil {
+			return err
+		}
+		if err := w.write(archiveCommit, driveID, "", seqNum, 0, data); err != nil {
+			return err
+		}
+
+		files, err := com.Files().Read()
+		if err != nil {
+			return err
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].File < files[j].File })
+		for _, change := range files {
+			if err := w.fileVersion(change.File, change.Version); err != nil {
+				return err
+			}
+		}
+		if len(files) > 0 {
+			if err := w.write(archiveCommitFiles, driveID, "", seqNum, 0, files); err != nil {
+				return err
+			}
+		}
+
+		var tree []commit.TreeChange
+		parents, err := com.Tree().Parents()
+		if err != nil {
+			return err
+		}
+		sort.Slice(parents, func(i, j int) bool { return parents[i] < parents[j] })
+		for _, parent := range parents {
+			changes, err := com.Tree().Group(parent).Changes()
+			if err != nil {
+				return err
+			}
+			sort.Slice(changes, func(i, j int) bool { return changes[i].Child < changes[j].Child })
+			tree = append(tree, changes...)
+		}
+		if len(tree) > 0 {
+			if err := w.write(archiveCommitTree, driveID, "", seqNum, 0, tree); err != nil {
+				return err
+			}
+		}
+
+		// States are written last so that a finalized commit is only
+		// recorded once all of its changes have been restored.
+		states, err := reader.States()
+		if err != nil {
+			return err
+		}
+		for i, state := range states {
+			if err := w.write(archiveCommitState, driveID, "", seqNum, int64(i), state); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *archiveWriter) fileVersion(id resource.ID, version resource.Version) error {
+	if version < 0 {
+		return nil
+	}
+
+	key := fileVersionKey{File: id, Version: version}
+	if w.seen[key] {
+		return nil
+	}
+	w.seen[key] = true
+
+	data, err := w.repo.File(id).Version(version).Data()
+	if err != nil {
+		return err
+	}
+
+	return w.write(archiveFileVersion, "", id, int64(version), 0, data)
+}
+
+func (w *archiveWriter) driveVersions(driveID resource.ID) error {
+	seq := w.repo.Drive(driveID).Versions()
+	end, err := seq.Next()
+	if err != nil {
+		return err
+	}
+
+	buf := make([]resource.DriveData, 64)
+	for v := resource.Version(0); v < end; {
+		n, err := seq.Read(v, buf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		for i := 0; i < n; i++ {
+			if err := w.write(archiveDriveVersion, driveID, "", int64(v), 0, buf[i]); err != nil {
+				return err
+			}
+			v++
+		}
+	}
+	return nil
+}
+
+func (w *archiveWriter) driveView(driveID resource.ID) error {
+	entries, err := w.repo.Drive(driveID).View().Entries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := w.write(archiveDriveView, driveID, "", int64(entry.Commit), 0, entry.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *archiveWriter) actors(driveID resource.ID) error {
+	actors := w.repo.Drive(driveID).Actors()
+	emails, err := actors.List()
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		entries, err := actors.Ref(email).Read(time.Time{}, time.Time{})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if err := w.write(archiveActors, driveID, "", 0, 0, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *archiveWriter) consumers(driveID resource.ID) error {
+	consumers := w.repo.Drive(driveID).Consumers()
+	names, err := consumers.List()
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := consumers.Ref(name).Data()
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if err := w.enc.Encode(archiveEntry{Kind: archiveConsumer, Drive: driveID, Name: name, Data: b}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// auditFilter determines which audit events are reported.
+type auditFilter struct {
+	Files     []string
+	Prefix    string
+	Actors    []string
+	MimeTypes []string
+	Kinds     []string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Match returns true if the event passes the filter.
+func (f auditFilter) Match(e auditEvent) bool {
+	if len(f.Files) > 0 && !isWanted(f.Files, string(e.File)) {
+		return false
+	}
+	if f.Prefix != "" && !strings.HasPrefix(e.Path, f.Prefix) && !strings.HasPrefix(e.PreviousPath, f.Prefix) {
+		return false
+	}
+	if len(f.Actors) > 0 && !isWanted(f.Actors, e.ActorEmail, e.ActorName) {
+		return false
+	}
+	if len(f.MimeTypes) > 0 && !isWanted(f.MimeTypes, e.MimeType) {
+		return false
+	}
+	if len(f.Kinds) > 0 && !isWanted(f.Kinds, e.Kind) {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+func audit(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, filter auditFilter, format string, wanted []string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	w, err := newAuditWriter(os.Stdout, format)
+	if err != nil {
+		app.Fatalf("%v", err)
+	}
+	defer func() {
+		if err := w.Flush(); err != nil {
+			app.Fatalf("failed to write audit output: %v", err)
+		}
+	}()
+
+	ids, err := repo.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate drivestream database: %v", err)
+	}
+
+	for _, driveID := range ids {
+		drv := repo.Drive(driveID)
+
+		if data, ok := driveData(drv); ok {
+			if !isWanted(wanted, string(driveID), data.Name) {
+				continue
+			}
+		} else {
+			if !isWanted(wanted, string(driveID)) {
+				continue
+			}
+		}
+
+		replay := newAuditReplay(repo, driveID)
+
+		cursor, err := commit.NewCursor(drv.Commits())
+		if err != nil {
+			app.Fatalf("failed to create commit cursor for repository %s: %v", driveID, err)
+		}
+		for cursor.First(); cursor.Valid(); cursor.Next() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			reader, err := cursor.Reader()
+			if err != nil {
+				app.Fatalf("failed to create commit reader for repository %s: %v", driveID, err)
+			}
+			if reader.NextState() == 0 {
+				break
+			}
+			state, err := reader.LastState()
+			if err != nil {
+				app.Fatalf("failed to read commit state from repository %s: %v", driveID, err)
+			}
+			if state.Phase != commit.PhaseFinalized {
+				break
+			}
+
+			events, err := replay.Commit(cursor.SeqNum())
+			if err != nil {
+				app.Fatalf("failed to replay commit %d from repository %s: %v", cursor.SeqNum(), driveID, err)
+			}
+			for _, event := range events {
+				if !filter.Match(event) {
+					continue
+				}
+				if err := w.Write(event); err != nil {
+					app.Fatalf("failed to write audit output: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// parseAuditTime parses v as an RFC 3339 timestamp, a date, or a duration
+// relative to the current time. An empty value returns the zero time.
+func parseAuditTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
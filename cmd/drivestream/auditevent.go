@@ -0,0 +1,187 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Kinds of audit events.
+const (
+	auditCreate  = "create"
+	auditDelete  = "delete"
+	auditRename  = "rename"
+	auditMove    = "move"
+	auditContent = "content"
+)
+
+// auditEventKinds is the list of all audit event kinds.
+var auditEventKinds = []string{auditCreate, auditDelete, auditRename, auditMove, auditContent}
+
+// auditEvent describes a single change to a file within a drive.
+type auditEvent struct {
+	Time         time.Time        `json:"time"`
+	Drive        resource.ID      `json:"drive"`
+	Commit       commit.SeqNum    `json:"commit"`
+	Kind         string           `json:"kind"`
+	File         resource.ID      `json:"file"`
+	Version      resource.Version `json:"version"`
+	Name         string           `json:"name"`
+	Path         string           `json:"path"`
+	PreviousPath string           `json:"previousPath,omitempty"`
+	MimeType     string           `json:"mimeType,omitempty"`
+	ActorName    string           `json:"actorName,omitempty"`
+	ActorEmail   string           `json:"actorEmail,omitempty"`
+}
+
+// auditReplay reconstructs the state of a drive by replaying its commit
+// history, producing audit events as it goes.
+type auditReplay struct {
+	repo  drivestream.Repository
+	drive resource.ID
+	files map[resource.ID]resource.FileData
+}
+
+// newAuditReplay returns an audit replay for the given drive.
+func newAuditReplay(repo drivestream.Repository, driveID resource.ID) *auditReplay {
+	return &auditReplay{
+		repo:  repo,
+		drive: driveID,
+		files: make(map[resource.ID]resource.FileData),
+	}
+}
+
+// Commit applies the file changes of a commit to the replay and returns
+// the audit events that describe them.
+func (r *auditReplay) Commit(seqNum commit.SeqNum) (events []auditEvent, err error) {
+	com := r.repo.Drive(r.drive).Commit(seqNum)
+	data, err := com.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	colData, err := r.repo.Drive(r.drive).Collection(data.Source.Collection).Data()
+	if err != nil {
+		return nil, err
+	}
+	full := colData.Type == collection.Full
+
+	changes, err := com.Files().Read()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+
+	for _, change := range changes {
+		prev, existed := r.files[change.File]
+		prevPath := r.path(change.File)
+
+		event := auditEvent{
+			Time:    data.Time,
+			Drive:   r.drive,
+			Commit:  seqNum,
+			File:    change.File,
+			Version: change.Version,
+		}
+
+		if change.Version < 0 {
+			if !existed {
+				continue
+			}
+			delete(r.files, change.File)
+			event.Kind = auditDelete
+			event.Name = prev.Name
+			event.Path = prevPath
+			event.MimeType = prev.MimeType
+			events = append(events, event)
+			continue
+		}
+
+		next, err := r.repo.File(change.File).Version(change.Version).Data()
+		if err != nil {
+			return nil, err
+		}
+		r.files[change.File] = next
+
+		event.Name = next.Name
+		event.Path = r.path(change.File)
+		event.MimeType = next.MimeType
+		if next.LastModifier != nil {
+			event.ActorName = next.LastModifier.DisplayName
+			event.ActorEmail = next.LastModifier.EmailAddress
+		}
+		if event.Time.IsZero() {
+			event.Time = next.Modified
+		}
+
+		if !existed {
+			event.Kind = auditCreate
+			if full && !next.Created.IsZero() {
+				event.Time = next.Created
+			}
+			events = append(events, event)
+			continue
+		}
+
+		if prev.Name != next.Name {
+			e := event
+			e.Kind = auditRename
+			e.PreviousPath = prevPath
+			events = append(events, e)
+		}
+		if !reflect.DeepEqual(prev.Parents, next.Parents) {
+			e := event
+			e.Kind = auditMove
+			e.PreviousPath = prevPath
+			events = append(events, e)
+		}
+		if prev.MD5Checksum != next.MD5Checksum || prev.RevisionID != next.RevisionID || prev.Size != next.Size {
+			e := event
+			e.Kind = auditContent
+			if full && !next.Modified.IsZero() {
+				e.Time = next.Modified
+			}
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+// path returns the path of a file within the replayed drive. Path
+// components for unknown ancestors are omitted.
+func (r *auditReplay) path(id resource.ID) string {
+	const maxDepth = 256
+
+	var components []string
+	for depth := 0; depth < maxDepth; depth++ {
+		data, ok := r.files[id]
+		if !ok {
+			break
+		}
+		components = append(components, data.Name)
+		if len(data.Parents) == 0 {
+			break
+		}
+		id = resource.ID(data.Parents[0])
+		if id == r.drive {
+			break
+		}
+	}
+
+	if len(components) == 0 {
+		return ""
+	}
+
+	for i, j := 0, len(components)-1; i < j; i, j = i+1, j-1 {
+		components[i], components[j] = components[j], components[i]
+	}
+
+	return "/" + strings.Join(components, "/")
+}
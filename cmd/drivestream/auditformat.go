@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Audit output formats.
+const (
+	auditTable = "table"
+	auditCSV   = "csv"
+	auditJSONL = "jsonl"
+)
+
+// auditWriter writes audit events to an output stream.
+type auditWriter interface {
+	Write(event auditEvent) error
+	Flush() error
+}
+
+// newAuditWriter returns an audit writer for the requested format.
+func newAuditWriter(w io.Writer, format string) (auditWriter, error) {
+	switch format {
+	case auditTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tDRIVE\tCOMMIT\tKIND\tACTOR\tFILE\tPATH")
+		return auditTableWriter{w: tw}, nil
+	case auditCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(auditColumns); err != nil {
+			return nil, err
+		}
+		return auditCSVWriter{w: cw}, nil
+	case auditJSONL:
+		return auditJSONLWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized audit format: %s", format)
+	}
+}
+
+// auditColumns is the list of columns written in CSV output.
+var auditColumns = []string{"time", "drive", "commit", "kind", "file", "version", "name", "path", "previousPath", "mimeType", "actorName", "actorEmail"}
+
+type auditTableWriter struct {
+	w *tabwriter.Writer
+}
+
+func (t auditTableWriter) Write(e auditEvent) error {
+	path := e.Path
+	if e.PreviousPath != "" && e.PreviousPath != e.Path {
+		path = e.PreviousPath + " -> " + e.Path
+	}
+	_, err := fmt.Fprintf(t.w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Drive, e.Commit, e.Kind, e.ActorEmail, e.File, path)
+	return err
+}
+
+func (t auditTableWriter) Flush() error {
+	return t.w.Flush()
+}
+
+type auditCSVWriter struct {
+	w *csv.Writer
+}
+
+func (c auditCSVWriter) Write(e auditEvent) error {
+	return c.w.Write([]string{
+		e.Time.Format(time.RFC3339),
+		string(e.Drive),
+		strconv.FormatInt(int64(e.Commit), 10),
+		e.Kind,
+		string(e.File),
+		strconv.FormatInt(int64(e.Version), 10),
+		e.Name,
+		e.Path,
+		e.PreviousPath,
+		e.MimeType,
+		e.ActorName,
+		e.ActorEmail,
+	})
+}
+
+func (c auditCSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type auditJSONLWriter struct {
+	enc *json.Encoder
+}
+
+func (j auditJSONLWriter) Write(e auditEvent) error {
+	return j.enc.Encode(e)
+}
+
+func (j auditJSONLWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/tokenstore"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Authentication methods.
+const (
+	authOAuth          = "oauth"
+	authServiceAccount = "service-account"
+	authDefault        = "adc"
+)
+
+var authMethods = []string{authOAuth, authServiceAccount, authDefault}
+
+// authConfig describes how to authenticate with Google.
+type authConfig struct {
+	Method       string // Authentication method
+	Credentials  string // Client secret file or service account key file
+	Subject      string // Account impersonated through domain-wide delegation
+	Flow         string // OAuth authorization flow
+	TokenFile    string // File that OAuth tokens are saved to
+	TokenEncrypt bool   // Encrypt the saved OAuth token
+}
+
+// newGoogleClient returns an HTTP client that is authorized for scope
+// using the configured authentication method.
+//
+// The oauth method uses the client secrets in the credentials file and the
+// token saved in the token file. If no token has been saved, the user is
+// asked to authorize access with the configured flow. The service-account
+// method signs tokens with the key in the credentials file and
+// impersonates the subject through domain-wide delegation. The adc method
+// uses Application Default Credentials, which impersonate the subject when
+// they hold a service account key.
+func newGoogleClient(ctx context.Context, app *kingpin.Application, config authConfig, scope ...string) *http.Client {
+	switch config.Method {
+	case authOAuth, "":
+		return getClient(ctx, app, getConfig(app, config.Credentials, scope...), config.Flow, newTokenStore(app, config))
+	case authServiceAccount:
+		data, err := ioutil.ReadFile(config.Credentials)
+		if err != nil {
+			app.Fatalf("failed to read service account key: %v", err)
+		}
+		jwtConfig, err := google.JWTConfigFromJSON(data, scope...)
+		if err != nil {
+			app.Fatalf("failed to parse service account key: %v", err)
+		}
+		jwtConfig.Subject = config.Subject
+		return jwtConfig.Client(ctx)
+	case authDefault:
+		creds, err := google.FindDefaultCredentialsWithParams(ctx, google.CredentialsParams{
+			Scopes:  scope,
+			Subject: config.Subject,
+		})
+		if err != nil {
+			app.Fatalf("failed to find application default credentials: %v", err)
+		}
+		return oauth2.NewClient(ctx, creds.TokenSource)
+	default:
+		app.Fatalf("unrecognized authentication method: %s", config.Method)
+		return nil
+	}
+}
+
+// defaultTokenFile returns the default path of the saved OAuth token,
+// within the configuration directory of the user. It falls back to
+// token.json in the working directory when the user has no configuration
+// directory.
+func defaultTokenFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "token.json"
+	}
+	return filepath.Join(dir, "drivestream", "token.json")
+}
+
+// newTokenStore returns the store that OAuth tokens are saved to.
+func newTokenStore(app *kingpin.Application, config authConfig) tokenstore.Store {
+	if !config.TokenEncrypt {
+		return tokenstore.NewFile(config.TokenFile)
+	}
+	c, err := codec.Lookup(codec.EncryptedName)
+	if err != nil {
+		app.Fatalf("encryption keys must be provided to encrypt the oauth token")
+	}
+	return tokenstore.NewFile(config.TokenFile, tokenstore.WithCodec(c))
+}
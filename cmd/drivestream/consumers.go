@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+func listConsumers(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, wanted []string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	ids, err := repo.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate drivestream database: %v", err)
+	}
+
+	for _, driveID := range ids {
+		drv := repo.Drive(driveID)
+		if data, ok := driveData(drv); ok {
+			if !isWanted(wanted, string(driveID), data.Name) {
+				continue
+			}
+		} else if !isWanted(wanted, string(driveID)) {
+			continue
+		}
+
+		names, err := drv.Consumers().List()
+		if err != nil {
+			app.Fatalf("failed to list consumers of drive %s: %v", driveID, err)
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		commits, err := drv.Commits().Next()
+		if err != nil {
+			app.Fatalf("failed to retrieve commits of drive %s: %v", driveID, err)
+		}
+
+		for _, name := range names {
+			data, err := drv.Consumers().Ref(name).Data()
+			if err != nil {
+				fmt.Printf("DRIVE %s: CONSUMER %s: %v\n", driveID, name, err)
+				continue
+			}
+			behind := commits - data.Next()
+			if behind < 0 {
+				behind = 0
+			}
+			fmt.Printf("DRIVE %s: CONSUMER %s: commit %d acknowledged %s (%d behind)\n", driveID, name, data.Commit, data.Time.Format(time.RFC3339), behind)
+		}
+	}
+}
+
+func resetConsumer(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, wanted, name string, next commit.SeqNum) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	ids, err := repo.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate drivestream database: %v", err)
+	}
+
+	var matches []resource.ID
+	for _, driveID := range ids {
+		values := []string{string(driveID)}
+		if data, ok := driveData(repo.Drive(driveID)); ok {
+			values = append(values, data.Name)
+		}
+		if isWanted([]string{wanted}, values...) {
+			matches = append(matches, driveID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		app.Fatalf("drive not found: %s", wanted)
+	case 1:
+	default:
+		app.Fatalf("more than one drive matches %s: %v", wanted, matches)
+	}
+	driveID := matches[0]
+
+	drv := repo.Drive(driveID)
+	commits, err := drv.Commits().Next()
+	if err != nil {
+		app.Fatalf("failed to retrieve commits of drive %s: %v", driveID, err)
+	}
+	if next < 0 || next > commits {
+		app.Fatalf("the next commit must be between 0 and %d", commits)
+	}
+
+	ref := drv.Consumers().Ref(name)
+	if next == 0 {
+		if err := ref.Delete(); err != nil {
+			app.Fatalf("failed to reset consumer %s: %v", name, err)
+		}
+		fmt.Printf("DRIVE %s: CONSUMER %s: RESET to the first commit\n", driveID, name)
+		return
+	}
+
+	if err := ref.Set(consumer.Data{Commit: next - 1, Time: time.Now()}); err != nil {
+		app.Fatalf("failed to reset consumer %s: %v", name, err)
+	}
+	fmt.Printf("DRIVE %s: CONSUMER %s: RESET to commit %d\n", driveID, name, next)
+}
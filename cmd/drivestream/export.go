@@ -0,0 +1,555 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+func export(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, types []string, format string, output string, from []string, wanted []string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	cursor, err := parseExportCursor(from)
+	if err != nil {
+		app.Fatalf("invalid --from value: %v", err)
+	}
+
+	if format == exportArchive {
+		if len(cursor) > 0 {
+			app.Fatalf("archives always contain complete drives and cannot be exported incrementally")
+		}
+		exportArchiveFile(ctx, app, repo, output, wanted)
+		return
+	}
+
+	types = uniqueStrings(types)
+	if output == "-" && len(types) != 1 {
+		app.Fatalf("exporting more than one record type requires an output directory")
+	}
+
+	e := &exporter{
+		repo:    repo,
+		writers: make(map[string]exportWriter),
+		seen:    make(map[fileVersionKey]bool),
+	}
+
+	var files []*os.File
+	for _, t := range types {
+		w := os.Stdout
+		if output != "-" {
+			if err := os.MkdirAll(output, 0755); err != nil {
+				app.Fatalf("failed to create export directory: %v", err)
+			}
+			f, err := os.Create(filepath.Join(output, t+"."+format))
+			if err != nil {
+				app.Fatalf("failed to create export file: %v", err)
+			}
+			files = append(files, f)
+			w = f
+		}
+		ew, err := newExportWriter(w, format, exportSchemas[t])
+		if err != nil {
+			app.Fatalf("%v", err)
+		}
+		e.writers[t] = ew
+	}
+	defer func() {
+		for t, w := range e.writers {
+			if err := w.Close(); err != nil {
+				app.Fatalf("failed to write %s export: %v", t, err)
+			}
+		}
+		for _, f := range files {
+			if err := f.Close(); err != nil {
+				app.Fatalf("failed to close export file: %v", err)
+			}
+		}
+	}()
+
+	ids, err := repo.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate drivestream database: %v", err)
+	}
+
+	matched := make(map[string]bool, len(cursor))
+	for _, driveID := range ids {
+		drv := repo.Drive(driveID)
+
+		var name string
+		if data, ok := driveData(drv); ok {
+			name = data.Name
+			if !isWanted(wanted, string(driveID), data.Name) {
+				continue
+			}
+		} else {
+			if !isWanted(wanted, string(driveID)) {
+				continue
+			}
+		}
+
+		from, key := cursor.From(driveID, name)
+		if key != "" {
+			matched[key] = true
+		}
+
+		next, err := e.Drive(ctx, driveID, from)
+		if err != nil {
+			app.Fatalf("failed to export drive %s: %v", driveID, err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		if next > from {
+			fmt.Fprintf(os.Stderr, "DRIVE %s: exported commits %d through %d, resume with --from %s=%d\n", driveID, from, next-1, driveID, next)
+		} else {
+			fmt.Fprintf(os.Stderr, "DRIVE %s: no finalized commits to export, resume with --from %s=%d\n", driveID, driveID, next)
+		}
+	}
+
+	for key := range cursor {
+		if !matched[key] {
+			fmt.Fprintf(os.Stderr, "WARNING: --from %s doesn't match an exported drive\n", key)
+		}
+	}
+}
+
+// exportCursor holds the sequence number of the first commit to export
+// for each drive, keyed by drive ID or name. Drives that aren't in the
+// cursor are exported from the beginning.
+type exportCursor map[string]commit.SeqNum
+
+// parseExportCursor parses a set of values of the form drive=seqnum, where
+// drive is the ID or name of a drive.
+func parseExportCursor(values []string) (exportCursor, error) {
+	cursor := make(exportCursor, len(values))
+	for _, value := range values {
+		i := strings.LastIndex(value, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("\"%s\" must be of the form drive=seqnum", value)
+		}
+		key := value[:i]
+		seqNum, err := strconv.ParseInt(value[i+1:], 10, 64)
+		if err != nil || seqNum < 0 {
+			return nil, fmt.Errorf("\"%s\" has an invalid commit sequence number", value)
+		}
+		if _, exists := cursor[key]; exists {
+			return nil, fmt.Errorf("drive \"%s\" was given more than once", key)
+		}
+		cursor[key] = commit.SeqNum(seqNum)
+	}
+	return cursor, nil
+}
+
+// From returns the sequence number of the first commit to export for a
+// drive, and the key of the cursor that it was found under. Drive IDs
+// take precedence over names. If the drive isn't in the cursor the
+// sequence number is 0 and the key is empty.
+func (c exportCursor) From(driveID resource.ID, name string) (commit.SeqNum, string) {
+	if seqNum, ok := c[string(driveID)]; ok {
+		return seqNum, string(driveID)
+	}
+	if name == "" {
+		return 0, ""
+	}
+	for key, seqNum := range c {
+		if strings.EqualFold(key, name) {
+			return seqNum, key
+		}
+	}
+	return 0, ""
+}
+
+// exportArchiveFile writes a complete archive of the wanted drives to
+// output, which is a file path or - for standard output.
+func exportArchiveFile(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, output string, wanted []string) {
+	w := os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			app.Fatalf("failed to create archive file: %v", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				app.Fatalf("failed to close archive file: %v", err)
+			}
+		}()
+		w = f
+	}
+
+	aw, err := newArchiveWriter(w, repo)
+	if err != nil {
+		app.Fatalf("failed to write archive: %v", err)
+	}
+
+	ids, err := repo.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate drivestream database: %v", err)
+	}
+
+	for _, driveID := range ids {
+		drv := repo.Drive(driveID)
+
+		if data, ok := driveData(drv); ok {
+			if !isWanted(wanted, string(driveID), data.Name) {
+				continue
+			}
+		} else {
+			if !isWanted(wanted, string(driveID)) {
+				continue
+			}
+		}
+
+		if err := aw.Drive(ctx, driveID); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			app.Fatalf("failed to archive drive %s: %v", driveID, err)
+		}
+	}
+}
+
+// fileVersionKey identifies a file version.
+type fileVersionKey struct {
+	File    resource.ID
+	Version resource.Version
+}
+
+// exporter writes records from a repository to a set of export writers,
+// one for each selected record type.
+type exporter struct {
+	repo    drivestream.Repository
+	writers map[string]exportWriter
+	seen    map[fileVersionKey]bool
+}
+
+// write writes record to the writer for the record type t, if that type
+// has been selected.
+func (e *exporter) write(t string, record exportRecord) error {
+	w, ok := e.writers[t]
+	if !ok {
+		return nil
+	}
+	return w.Write(record)
+}
+
+// selected returns true if any of the given record types have been
+// selected.
+func (e *exporter) selected(types ...string) bool {
+	for _, t := range types {
+		if _, ok := e.writers[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Drive exports the records for a drive, starting with the commit at from.
+// It returns the sequence number of the first commit that was not
+// exported, which can be used as the starting point of a subsequent
+// incremental export.
+//
+// Only finalized commits are exported. Collection and page records are
+// exported for the collections that follow the source of the commit
+// before from, up to and including the source of the last exported
+// commit. Drive versions are exported when they are first viewed by an
+// exported commit. Each collection and drive version is therefore
+// exported exactly once by a series of incremental exports.
+func (e *exporter) Drive(ctx context.Context, driveID resource.ID, from commit.SeqNum) (next commit.SeqNum, err error) {
+	drv := e.repo.Drive(driveID)
+
+	firstCollection := collection.SeqNum(0)
+	if from > 0 {
+		data, err := drv.Commit(from - 1).Data()
+		if err != nil {
+			return from, err
+		}
+		firstCollection = data.Source.Collection + 1
+	}
+	lastCollection := collection.SeqNum(-1)
+
+	next = from
+	cursor, err := commit.NewCursor(drv.Commits())
+	if err != nil {
+		return next, err
+	}
+	for cursor.Seek(from); cursor.Valid(); cursor.Next() {
+		if ctx.Err() != nil {
+			return next, nil
+		}
+
+		reader, err := cursor.Reader()
+		if err != nil {
+			return next, err
+		}
+		if reader.NextState() == 0 {
+			break
+		}
+		state, err := reader.LastState()
+		if err != nil {
+			return next, err
+		}
+		if state.Phase != commit.PhaseFinalized {
+			break
+		}
+		data, err := reader.Data()
+		if err != nil {
+			return next, err
+		}
+
+		lastCollection = data.Source.Collection
+
+		if err := e.commit(driveID, cursor.SeqNum(), data, state); err != nil {
+			return next, err
+		}
+
+		next = cursor.SeqNum() + 1
+	}
+
+	if lastCollection >= firstCollection && e.selected(exportCollections, exportPages) {
+		if err := e.collections(ctx, driveID, firstCollection, lastCollection); err != nil {
+			return next, err
+		}
+	}
+
+	if next > from && e.selected(exportDriveVersions) {
+		if err := e.driveVersions(driveID, from, next); err != nil {
+			return next, err
+		}
+	}
+
+	return next, nil
+}
+
+func (e *exporter) commit(driveID resource.ID, seqNum commit.SeqNum, data commit.Data, state commit.State) error {
+	err := e.write(exportCommits, commitRecord{
+		Drive:      driveID,
+		Commit:     seqNum,
+		Time:       data.Time,
+		Collection: data.Source.Collection,
+		Page:       data.Source.Page,
+		Index:      data.Source.Index,
+		Phase:      state.Phase.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	com := e.repo.Drive(driveID).Commit(seqNum)
+
+	if e.selected(exportFileChanges, exportFileVersions) {
+		changes, err := com.Files().Read()
+		if err != nil {
+			return err
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+		for _, change := range changes {
+			err := e.write(exportFileChanges, fileChangeRecord{
+				Drive:   driveID,
+				Commit:  seqNum,
+				File:    change.File,
+				Version: change.Version,
+				Removed: change.Version < 0,
+			})
+			if err != nil {
+				return err
+			}
+			if err := e.fileVersion(change.File, change.Version); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.selected(exportTreeChanges) {
+		parents, err := com.Tree().Parents()
+		if err != nil {
+			return err
+		}
+		sort.Slice(parents, func(i, j int) bool { return parents[i] < parents[j] })
+		for _, parent := range parents {
+			changes, err := com.Tree().Group(parent).Changes()
+			if err != nil {
+				return err
+			}
+			sort.Slice(changes, func(i, j int) bool { return changes[i].Child < changes[j].Child })
+			for _, change := range changes {
+				err := e.write(exportTreeChanges, treeChangeRecord{
+					Drive:   driveID,
+					Commit:  seqNum,
+					Parent:  change.Parent,
+					Child:   change.Child,
+					Removed: change.Removed,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) fileVersion(id resource.ID, version resource.Version) error {
+	if version < 0 || !e.selected(exportFileVersions) {
+		return nil
+	}
+
+	key := fileVersionKey{File: id, Version: version}
+	if e.seen[key] {
+		return nil
+	}
+	e.seen[key] = true
+
+	data, err := e.repo.File(id).Version(version).Data()
+	if err != nil {
+		return err
+	}
+
+	return e.write(exportFileVersions, newFileVersionRecord(id, version, data))
+}
+
+func (e *exporter) collections(ctx context.Context, driveID resource.ID, first, last collection.SeqNum) error {
+	cursor, err := collection.NewCursor(e.repo.Drive(driveID).Collections())
+	if err != nil {
+		return err
+	}
+	for cursor.Seek(first); cursor.Valid() && cursor.SeqNum() <= last; cursor.Next() {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		reader, err := cursor.Reader()
+		if err != nil {
+			return err
+		}
+		data, err := reader.Data()
+		if err != nil {
+			return err
+		}
+
+		record := collectionRecord{
+			Drive:      driveID,
+			Collection: cursor.SeqNum(),
+			Type:       data.Type.String(),
+			StartToken: data.StartToken,
+		}
+		if reader.NextState() > 0 {
+			state, err := reader.LastState()
+			if err != nil {
+				return err
+			}
+			record.Phase = state.Phase.String()
+			record.Updated = state.Time
+		}
+		if err := e.write(exportCollections, record); err != nil {
+			return err
+		}
+
+		if !e.selected(exportPages) {
+			continue
+		}
+		for pageNum := page.SeqNum(0); pageNum < reader.NextPage(); pageNum++ {
+			pg, err := reader.Page(pageNum)
+			if err != nil {
+				return err
+			}
+			err = e.write(exportPages, pageRecord{
+				Drive:          driveID,
+				Collection:     cursor.SeqNum(),
+				Page:           pageNum,
+				Type:           pg.Type.String(),
+				Collected:      pg.Collected,
+				PageToken:      pg.PageToken,
+				NextPageToken:  pg.NextPageToken,
+				NextStartToken: pg.NextStartToken,
+				Changes:        len(pg.Changes),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// driveVersions exports the drive versions that were first viewed by the
+// commits from from up to next.
+func (e *exporter) driveVersions(driveID resource.ID, from, next commit.SeqNum) error {
+	drv := e.repo.Drive(driveID)
+	entries, err := drv.View().Entries()
+	if err != nil {
+		return err
+	}
+
+	// Versions are created in order as they are viewed.
+	var first, end resource.Version
+	for _, entry := range entries {
+		switch {
+		case entry.Commit < from:
+			if entry.Version >= first {
+				first = entry.Version + 1
+			}
+		case entry.Commit < next:
+			if entry.Version >= end {
+				end = entry.Version + 1
+			}
+		}
+	}
+
+	seq := drv.Versions()
+	buf := make([]resource.DriveData, 64)
+	for v := first; v < end; {
+		n, err := seq.Read(v, buf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		if n > int(end-v) {
+			n = int(end - v)
+		}
+		for i := 0; i < n; i++ {
+			err := e.write(exportDriveVersions, driveVersionRecord{
+				Drive:       driveID,
+				Version:     v,
+				Name:        buf[i].Name,
+				Created:     buf[i].Created,
+				Permissions: len(buf[i].Permissions),
+			})
+			if err != nil {
+				return err
+			}
+			v++
+		}
+	}
+	return nil
+}
+
+// uniqueStrings returns values with duplicates removed, preserving order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	output := make([]string, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		output = append(output, v)
+	}
+	return output
+}
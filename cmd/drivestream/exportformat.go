@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Export output formats.
+const (
+	exportJSONL   = "jsonl"
+	exportCSV     = "csv"
+	exportParquet = "parquet"
+	exportArchive = "archive"
+)
+
+// exportWriter writes export records of a single record type to an
+// output stream.
+type exportWriter interface {
+	Write(record exportRecord) error
+	Close() error
+}
+
+// newExportWriter returns an export writer for the requested format that
+// writes records with the given columns.
+func newExportWriter(w io.Writer, format string, columns []exportColumn) (exportWriter, error) {
+	switch format {
+	case exportJSONL:
+		return exportJSONLWriter{enc: json.NewEncoder(w)}, nil
+	case exportCSV:
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.Name
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return exportCSVWriter{w: cw, columns: columns}, nil
+	case exportParquet:
+		md := make([]string, len(columns))
+		for i, column := range columns {
+			md[i] = parquetColumn(column)
+		}
+		pw, err := writer.NewCSVWriterFromWriter(md, w, 1)
+		if err != nil {
+			return nil, err
+		}
+		pw.CompressionType = parquet.CompressionCodec_SNAPPY
+		return exportParquetWriter{w: pw, columns: columns}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized export format: %s", format)
+	}
+}
+
+type exportJSONLWriter struct {
+	enc *json.Encoder
+}
+
+func (j exportJSONLWriter) Write(record exportRecord) error {
+	return j.enc.Encode(record)
+}
+
+func (j exportJSONLWriter) Close() error {
+	return nil
+}
+
+type exportCSVWriter struct {
+	w       *csv.Writer
+	columns []exportColumn
+}
+
+func (c exportCSVWriter) Write(record exportRecord) error {
+	values := record.Values()
+	row := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		switch column.Type {
+		case exportString:
+			row[i] = values[i].(string)
+		case exportInt:
+			row[i] = strconv.FormatInt(values[i].(int64), 10)
+		case exportBool:
+			row[i] = strconv.FormatBool(values[i].(bool))
+		case exportTime:
+			if t := values[i].(time.Time); !t.IsZero() {
+				row[i] = t.Format(time.RFC3339Nano)
+			}
+		case exportList:
+			row[i] = strings.Join(values[i].([]string), ",")
+		}
+	}
+	return c.w.Write(row)
+}
+
+func (c exportCSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type exportParquetWriter struct {
+	w       *writer.CSVWriter
+	columns []exportColumn
+}
+
+func (p exportParquetWriter) Write(record exportRecord) error {
+	values := record.Values()
+	row := make([]interface{}, len(p.columns))
+	for i, column := range p.columns {
+		switch column.Type {
+		case exportTime:
+			if t := values[i].(time.Time); !t.IsZero() {
+				row[i] = t.UnixNano() / int64(time.Millisecond)
+			}
+		case exportList:
+			row[i] = strings.Join(values[i].([]string), ",")
+		default:
+			row[i] = values[i]
+		}
+	}
+	return p.w.Write(row)
+}
+
+func (p exportParquetWriter) Close() error {
+	return p.w.WriteStop()
+}
+
+// parquetColumn returns the parquet schema metadata for column.
+func parquetColumn(column exportColumn) string {
+	switch column.Type {
+	case exportInt:
+		return fmt.Sprintf("name=%s, type=INT64", column.Name)
+	case exportBool:
+		return fmt.Sprintf("name=%s, type=BOOLEAN", column.Name)
+	case exportTime:
+		return fmt.Sprintf("name=%s, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL", column.Name)
+	default:
+		return fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8", column.Name)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Export record types.
+const (
+	exportCollections   = "collections"
+	exportPages         = "pages"
+	exportCommits       = "commits"
+	exportFileChanges   = "file-changes"
+	exportTreeChanges   = "tree-changes"
+	exportFileVersions  = "file-versions"
+	exportDriveVersions = "drive-versions"
+)
+
+// exportRecordTypes is the list of all export record types.
+var exportRecordTypes = []string{
+	exportCollections,
+	exportPages,
+	exportCommits,
+	exportFileChanges,
+	exportTreeChanges,
+	exportFileVersions,
+	exportDriveVersions,
+}
+
+// exportColumnType is the type of value held by an export column.
+type exportColumnType int
+
+// Export column types.
+const (
+	exportString exportColumnType = iota // string
+	exportInt                            // int64
+	exportBool                           // bool
+	exportTime                           // time.Time
+	exportList                           // []string
+)
+
+// exportColumn describes a column of an export record.
+type exportColumn struct {
+	Name string
+	Type exportColumnType
+}
+
+// exportRecord is a record that can be exported. The values it returns
+// must match the columns of its record type, in order.
+type exportRecord interface {
+	Values() []interface{}
+}
+
+// exportSchemas maps each export record type to its columns.
+//
+// These schemas are part of the export format. Columns may be appended
+// but must not be renamed, reordered or removed.
+var exportSchemas = map[string][]exportColumn{
+	exportCollections: {
+		{"drive", exportString},
+		{"collection", exportInt},
+		{"type", exportString},
+		{"startToken", exportString},
+		{"phase", exportString},
+		{"updated", exportTime},
+	},
+	exportPages: {
+		{"drive", exportString},
+		{"collection", exportInt},
+		{"page", exportInt},
+		{"type", exportString},
+		{"collected", exportTime},
+		{"pageToken", exportString},
+		{"nextPageToken", exportString},
+		{"nextStartToken", exportString},
+		{"changes", exportInt},
+	},
+	exportCommits: {
+		{"drive", exportString},
+		{"commit", exportInt},
+		{"time", exportTime},
+		{"collection", exportInt},
+		{"page", exportInt},
+		{"index", exportInt},
+		{"phase", exportString},
+	},
+	exportFileChanges: {
+		{"drive", exportString},
+		{"commit", exportInt},
+		{"file", exportString},
+		{"version", exportInt},
+		{"removed", exportBool},
+	},
+	exportTreeChanges: {
+		{"drive", exportString},
+		{"commit", exportInt},
+		{"parent", exportString},
+		{"child", exportString},
+		{"removed", exportBool},
+	},
+	exportFileVersions: {
+		{"file", exportString},
+		{"version", exportInt},
+		{"name", exportString},
+		{"mimeType", exportString},
+		{"description", exportString},
+		{"originalFilename", exportString},
+		{"headRevisionId", exportString},
+		{"md5Checksum", exportString},
+		{"size", exportInt},
+		{"createdTime", exportTime},
+		{"modifiedTime", exportTime},
+		{"parents", exportList},
+		{"lastModifierName", exportString},
+		{"lastModifierEmail", exportString},
+	},
+	exportDriveVersions: {
+		{"drive", exportString},
+		{"version", exportInt},
+		{"name", exportString},
+		{"createdTime", exportTime},
+		{"permissions", exportInt},
+	},
+}
+
+type collectionRecord struct {
+	Drive      resource.ID       `json:"drive"`
+	Collection collection.SeqNum `json:"collection"`
+	Type       string            `json:"type"`
+	StartToken string            `json:"startToken"`
+	Phase      string            `json:"phase"`
+	Updated    time.Time         `json:"updated"`
+}
+
+func (r collectionRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Collection), r.Type, r.StartToken, r.Phase, r.Updated}
+}
+
+type pageRecord struct {
+	Drive          resource.ID       `json:"drive"`
+	Collection     collection.SeqNum `json:"collection"`
+	Page           page.SeqNum       `json:"page"`
+	Type           string            `json:"type"`
+	Collected      time.Time         `json:"collected"`
+	PageToken      string            `json:"pageToken"`
+	NextPageToken  string            `json:"nextPageToken"`
+	NextStartToken string            `json:"nextStartToken"`
+	Changes        int               `json:"changes"`
+}
+
+func (r pageRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Collection), int64(r.Page), r.Type, r.Collected, r.PageToken, r.NextPageToken, r.NextStartToken, int64(r.Changes)}
+}
+
+type commitRecord struct {
+	Drive      resource.ID       `json:"drive"`
+	Commit     commit.SeqNum     `json:"commit"`
+	Time       time.Time         `json:"time"`
+	Collection collection.SeqNum `json:"collection"`
+	Page       page.SeqNum       `json:"page"`
+	Index      int               `json:"index"`
+	Phase      string            `json:"phase"`
+}
+
+func (r commitRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Commit), r.Time, int64(r.Collection), int64(r.Page), int64(r.Index), r.Phase}
+}
+
+type fileChangeRecord struct {
+	Drive   resource.ID      `json:"drive"`
+	Commit  commit.SeqNum    `json:"commit"`
+	File    resource.ID      `json:"file"`
+	Version resource.Version `json:"version"`
+	Removed bool             `json:"removed"`
+}
+
+func (r fileChangeRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Commit), string(r.File), int64(r.Version), r.Removed}
+}
+
+type treeChangeRecord struct {
+	Drive   resource.ID   `json:"drive"`
+	Commit  commit.SeqNum `json:"commit"`
+	Parent  resource.ID   `json:"parent"`
+	Child   resource.ID   `json:"child"`
+	Removed bool          `json:"removed"`
+}
+
+func (r treeChangeRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Commit), string(r.Parent), string(r.Child), r.Removed}
+}
+
+type fileVersionRecord struct {
+	File              resource.ID      `json:"file"`
+	Version           resource.Version `json:"version"`
+	Name              string           `json:"name"`
+	MimeType          string           `json:"mimeType"`
+	Description       string           `json:"description"`
+	OriginalName      string           `json:"originalFilename"`
+	RevisionID        string           `json:"headRevisionId"`
+	MD5Checksum       string           `json:"md5Checksum"`
+	Size              int64            `json:"size"`
+	Created           time.Time        `json:"createdTime"`
+	Modified          time.Time        `json:"modifiedTime"`
+	Parents           []string         `json:"parents"`
+	LastModifierName  string           `json:"lastModifierName"`
+	LastModifierEmail string           `json:"lastModifierEmail"`
+}
+
+func newFileVersionRecord(id resource.ID, version resource.Version, data resource.FileData) fileVersionRecord {
+	r := fileVersionRecord{
+		File:         id,
+		Version:      version,
+		Name:         data.Name,
+		MimeType:     data.MimeType,
+		Description:  data.Description,
+		OriginalName: data.OriginalName,
+		RevisionID:   data.RevisionID,
+		MD5Checksum:  data.MD5Checksum,
+		Size:         data.Size,
+		Created:      data.Created,
+		Modified:     data.Modified,
+		Parents:      data.Parents,
+	}
+	if r.Parents == nil {
+		r.Parents = []string{}
+	}
+	if data.LastModifier != nil {
+		r.LastModifierName = data.LastModifier.DisplayName
+		r.LastModifierEmail = data.LastModifier.EmailAddress
+	}
+	return r
+}
+
+func (r fileVersionRecord) Values() []interface{} {
+	return []interface{}{
+		string(r.File),
+		int64(r.Version),
+		r.Name,
+		r.MimeType,
+		r.Description,
+		r.OriginalName,
+		r.RevisionID,
+		r.MD5Checksum,
+		r.Size,
+		r.Created,
+		r.Modified,
+		r.Parents,
+		r.LastModifierName,
+		r.LastModifierEmail,
+	}
+}
+
+type driveVersionRecord struct {
+	Drive       resource.ID      `json:"drive"`
+	Version     resource.Version `json:"version"`
+	Name        string           `json:"name"`
+	Created     time.Time        `json:"createdTime"`
+	Permissions int              `json:"permissions"`
+}
+
+func (r driveVersionRecord) Values() []interface{} {
+	return []interface{}{string(r.Drive), int64(r.Version), r.Name, r.Created, int64(r.Permissions)}
+}
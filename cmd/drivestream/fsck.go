@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/readonly"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+func fsck(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, repair bool) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Checks only read from the database, so they can be performed on a
+	// database that was opened in read-only mode.
+	if ro, ok := repo.(readonly.Repository); ok {
+		if repair {
+			app.Fatalf("a database opened in read-only mode cannot be repaired")
+		}
+		repo = ro.Unwrap()
+	}
+
+	checker, ok := repo.(*boltSession)
+	if !ok {
+		app.Fatalf("integrity checks are not supported by %s databases", repo.Type())
+	}
+
+	problems, err := checker.Check()
+	if err != nil {
+		app.Fatalf("failed to check drivestream database: %v", err)
+	}
+
+	repairable := 0
+	for _, problem := range problems {
+		if problem.Repairable {
+			repairable++
+			fmt.Printf("PROBLEM (REPAIRABLE): %v\n", problem)
+		} else {
+			fmt.Printf("PROBLEM: %v\n", problem)
+		}
+	}
+
+	remaining := len(problems)
+	if repair && repairable > 0 {
+		repaired, err := checker.Repair(problems)
+		if err != nil {
+			app.Fatalf("failed to repair drivestream database: %v", err)
+		}
+		for _, problem := range repaired {
+			fmt.Printf("REPAIRED: %v\n", problem)
+		}
+		remaining -= len(repaired)
+	}
+
+	fmt.Printf("%d problems found (%d repairable), %d remaining\n", len(problems), repairable, remaining)
+	if remaining > 0 {
+		if !repair && repairable > 0 {
+			app.Fatalf("the database contains problems; run with --repair to fix the repairable ones")
+		}
+		app.Fatalf("the database contains problems")
+	}
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// importMaxLine is the maximum length of a single archive entry.
+const importMaxLine = 256 * 1024 * 1024
+
+func importArchive(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, path string, wanted []string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Drives can be selected by name, but the name of a drive is recorded
+	// after its commits. Look up the names of the drives first.
+	var names map[resource.ID]string
+	if len(wanted) > 0 {
+		f, err := os.Open(path)
+		if err != nil {
+			app.Fatalf("failed to open archive: %v", err)
+		}
+		names, err = archiveDriveNames(ctx, f)
+		f.Close()
+		if err != nil {
+			app.Fatalf("invalid archive: %v", err)
+		}
+	}
+
+	// The archive is read twice. The first pass validates the archive
+	// without modifying the repository, so that a bad archive isn't
+	// partially imported.
+	for _, apply := range []bool{false, true} {
+		f, err := os.Open(path)
+		if err != nil {
+			app.Fatalf("failed to open archive: %v", err)
+		}
+		imp := newImporter(repo, apply, wanted, names)
+		line, err := imp.Read(ctx, f)
+		f.Close()
+		if err != nil {
+			if apply {
+				app.Fatalf("failed to import archive line %d: %v", line, err)
+			}
+			app.Fatalf("invalid archive line %d: %v", line, err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if apply {
+			for _, driveID := range imp.order {
+				d := imp.drives[driveID]
+				fmt.Printf("DRIVE %s: imported %d collections, %d commits, %d drive versions, %d consumers\n", driveID, d.collections, d.commits, d.versions, d.consumers)
+			}
+		}
+	}
+}
+
+// archiveDriveNames returns the name of the latest version of each drive
+// within the archive read from r.
+func archiveDriveNames(ctx context.Context, r io.Reader) (map[resource.ID]string, error) {
+	names := make(map[resource.ID]string)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, importMaxLine)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var entry archiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		if entry.Kind != archiveDriveVersion {
+			continue
+		}
+		var data resource.DriveData
+		if err := json.Unmarshal(entry.Data, &data); err != nil {
+			return nil, driveversion.InvalidData{Drive: entry.Drive, Version: resource.Version(entry.Seq)}
+		}
+		names[entry.Drive] = data.Name
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// importDrive tracks the next expected sequence numbers for a drive
+// while an archive is imported.
+type importDrive struct {
+	collections      collection.SeqNum
+	collectionStates map[collection.SeqNum]collection.StateNum
+	pages            map[collection.SeqNum]page.SeqNum
+	commits          commit.SeqNum
+	commitStates     map[commit.SeqNum]commit.StateNum
+	versions         resource.Version
+	views            commit.SeqNum
+	consumers        int
+}
+
+// importer validates the entries of an archive and optionally adds them to
+// a repository.
+type importer struct {
+	repo   drivestream.Repository
+	apply  bool
+	wanted []string
+	names  map[resource.ID]string
+	header bool
+	drives map[resource.ID]*importDrive
+	order  []resource.ID
+	files  map[fileVersionKey]bool
+}
+
+// newImporter returns an importer for repo. If apply is false the importer
+// only validates the archive. Drives are selected by matching wanted
+// against their IDs and the names recorded in names.
+func newImporter(repo drivestream.Repository, apply bool, wanted []string, names map[resource.ID]string) *importer {
+	return &importer{
+		repo:   repo,
+		apply:  apply,
+		wanted: wanted,
+		names:  names,
+		drives: make(map[resource.ID]*importDrive),
+		files:  make(map[fileVersionKey]bool),
+	}
+}
+
+// Read processes every entry in r. It returns the line number of the last
+// entry processed.
+func (imp *importer) Read(ctx context.Context, r io.Reader) (line int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, importMaxLine)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return line, nil
+		}
+		line++
+		var entry archiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return line, err
+		}
+		if err := imp.Entry(entry); err != nil {
+			return line, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return line, err
+	}
+	if !imp.header {
+		return line, fmt.Errorf("the archive is empty")
+	}
+	return line, nil
+}
+
+// drive returns the import state for a drive. The first time a drive is
+// encountered it must not already contain any collections or commits in
+// the repository.
+func (imp *importer) drive(driveID resource.ID) (*importDrive, error) {
+	if d, ok := imp.drives[driveID]; ok {
+		return d, nil
+	}
+
+	drv := imp.repo.Drive(driveID)
+	collections, err := drv.Collections().Next()
+	if err != nil {
+		return nil, err
+	}
+	commits, err := drv.Commits().Next()
+	if err != nil {
+		return nil, err
+	}
+	if collections != 0 || commits != 0 {
+		return nil, fmt.Errorf("drive %s already contains data in the destination repository", driveID)
+	}
+
+	d := &importDrive{
+		collectionStates: make(map[collection.SeqNum]collection.StateNum),
+		pages:            make(map[collection.SeqNum]page.SeqNum),
+		commitStates:     make(map[commit.SeqNum]commit.StateNum),
+	}
+	imp.drives[driveID] = d
+	imp.order = append(imp.order, driveID)
+	return d, nil
+}
+
+// Entry processes a single archive entry.
+func (imp *importer) Entry(entry archiveEntry) error {
+	if !imp.header {
+		if entry.Kind != archiveHeader {
+			return fmt.Errorf("the archive header is missing")
+		}
+		if entry.Seq != archiveVersion {
+			return fmt.Errorf("unsupported archive version %d", entry.Seq)
+		}
+		imp.header = true
+		return nil
+	}
+
+	switch entry.Kind {
+	case archiveFileVersion:
+		return imp.fileVersion(entry)
+	case archiveHeader:
+		return fmt.Errorf("unexpected archive header")
+	}
+
+	if entry.Drive == "" {
+		return fmt.Errorf("%s entry is missing a drive ID", entry.Kind)
+	}
+	if !isWanted(imp.wanted, string(entry.Drive), imp.names[entry.Drive]) {
+		return nil
+	}
+
+	d, err := imp.drive(entry.Drive)
+	if err != nil {
+		return err
+	}
+
+	switch entry.Kind {
+	case archiveCollection:
+		return imp.collection(d, entry)
+	case archiveCollectionState:
+		return imp.collectionState(d, entry)
+	case archivePage:
+		return imp.page(d, entry)
+	case archiveCommit:
+		return imp.commit(d, entry)
+	case archiveCommitState:
+		return imp.commitState(d, entry)
+	case archiveCommitFiles:
+		return imp.commitFiles(d, entry)
+	case archiveCommitTree:
+		return imp.commitTree(d, entry)
+	case archiveDriveVersion:
+		return imp.driveVersion(d, entry)
+	case archiveDriveView:
+		return imp.driveView(d, entry)
+	case archiveActors:
+		return imp.actors(entry)
+	case archiveConsumer:
+		return imp.consumer(d, entry)
+	default:
+		return fmt.Errorf("unrecognized archive entry kind \"%s\"", entry.Kind)
+	}
+}
+
+func (imp *importer) collection(d *importDrive, entry archiveEntry) error {
+	seqNum := collection.SeqNum(entry.Seq)
+	if seqNum != d.collections {
+		return collection.OutOfOrder{Drive: entry.Drive, Collection: seqNum, Expected: d.collections}
+	}
+	var data collection.Data
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return collection.DataInvalid{Drive: entry.Drive, Collection: seqNum}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Collection(seqNum).Create(data); err != nil {
+			return err
+		}
+	}
+	d.collections++
+	return nil
+}
+
+func (imp *importer) collectionState(d *importDrive, entry archiveEntry) error {
+	seqNum := collection.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.collections {
+		return collection.NotFound{Drive: entry.Drive, Collection: seqNum}
+	}
+	stateNum := collection.StateNum(entry.Sub)
+	if expected := d.collectionStates[seqNum]; stateNum != expected {
+		return collection.StateOutOfOrder{Drive: entry.Drive, Collection: seqNum, State: stateNum, Expected: expected}
+	}
+	var state collection.State
+	if err := json.Unmarshal(entry.Data, &state); err != nil {
+		return collection.StateInvalid{Drive: entry.Drive, Collection: seqNum, State: stateNum}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Collection(seqNum).State(stateNum).Create(state); err != nil {
+			return err
+		}
+	}
+	d.collectionStates[seqNum]++
+	return nil
+}
+
+func (imp *importer) page(d *importDrive, entry archiveEntry) error {
+	seqNum := collection.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.collections {
+		return collection.NotFound{Drive: entry.Drive, Collection: seqNum}
+	}
+	pageNum := page.SeqNum(entry.Sub)
+	if expected := d.pages[seqNum]; pageNum != expected {
+		return collection.PageOutOfOrder{Drive: entry.Drive, Collection: seqNum, Page: pageNum, Expected: expected}
+	}
+	var data page.Data
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return collection.PageDataInvalid{Drive: entry.Drive, Collection: seqNum, Page: pageNum}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Collection(seqNum).Page(pageNum).Create(data); err != nil {
+			return err
+		}
+	}
+	d.pages[seqNum]++
+	return nil
+}
+
+func (imp *importer) commit(d *importDrive, entry archiveEntry) error {
+	seqNum := commit.SeqNum(entry.Seq)
+	if seqNum != d.commits {
+		return commit.OutOfOrder{Drive: entry.Drive, Commit: seqNum, Expected: d.commits}
+	}
+	var data commit.Data
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return commit.DataInvalid{Drive: entry.Drive, Commit: seqNum}
+	}
+	if data.Source.Collection < 0 || data.Source.Collection >= d.collections {
+		return collection.NotFound{Drive: entry.Drive, Collection: data.Source.Collection}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Commit(seqNum).Create(data); err != nil {
+			return err
+		}
+	}
+	d.commits++
+	return nil
+}
+
+func (imp *importer) commitState(d *importDrive, entry archiveEntry) error {
+	seqNum := commit.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.commits {
+		return commit.NotFound{Drive: entry.Drive, Commit: seqNum}
+	}
+	stateNum := commit.StateNum(entry.Sub)
+	if expected := d.commitStates[seqNum]; stateNum != expected {
+		return commit.StateOutOfOrder{Drive: entry.Drive, Commit: seqNum, State: stateNum, Expected: expected}
+	}
+	var state commit.State
+	if err := json.Unmarshal(entry.Data, &state); err != nil {
+		return commit.StateInvalid{Drive: entry.Drive, Commit: seqNum, State: stateNum}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Commit(seqNum).State(stateNum).Create(state); err != nil {
+			return err
+		}
+	}
+	d.commitStates[seqNum]++
+	return nil
+}
+
+func (imp *importer) commitFiles(d *importDrive, entry archiveEntry) error {
+	seqNum := commit.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.commits {
+		return commit.NotFound{Drive: entry.Drive, Commit: seqNum}
+	}
+	var changes []commit.FileChange
+	if err := json.Unmarshal(entry.Data, &changes); err != nil {
+		return commit.DataInvalid{Drive: entry.Drive, Commit: seqNum}
+	}
+
+	views := make([]fileview.Data, 0, len(changes))
+	for _, change := range changes {
+		if change.Version >= 0 && !imp.files[fileVersionKey{File: change.File, Version: change.Version}] {
+			if _, err := imp.repo.File(change.File).Version(change.Version).Data(); err != nil {
+				return fileversion.NotFound{File: change.File, Version: change.Version}
+			}
+		}
+		views = append(views, fileview.Data{
+			File:    change.File,
+			Drive:   entry.Drive,
+			Commit:  seqNum,
+			Version: change.Version,
+		})
+	}
+
+	if !imp.apply {
+		return nil
+	}
+	if err := imp.repo.Files().AddViewData(views...); err != nil {
+		return err
+	}
+	return imp.repo.Drive(entry.Drive).Commit(seqNum).Files().Add(changes...)
+}
+
+func (imp *importer) commitTree(d *importDrive, entry archiveEntry) error {
+	seqNum := commit.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.commits {
+		return commit.NotFound{Drive: entry.Drive, Commit: seqNum}
+	}
+	var changes []commit.TreeChange
+	if err := json.Unmarshal(entry.Data, &changes); err != nil {
+		return commit.DataInvalid{Drive: entry.Drive, Commit: seqNum}
+	}
+	if !imp.apply {
+		return nil
+	}
+	return imp.repo.Drive(entry.Drive).Commit(seqNum).Tree().Add(changes...)
+}
+
+func (imp *importer) fileVersion(entry archiveEntry) error {
+	if entry.File == "" {
+		return fmt.Errorf("%s entry is missing a file ID", entry.Kind)
+	}
+	version := resource.Version(entry.Seq)
+	var data resource.FileData
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return fileversion.InvalidData{File: entry.File, Version: version}
+	}
+	if imp.apply {
+		if err := imp.repo.File(entry.File).Version(version).Create(data); err != nil {
+			return err
+		}
+	}
+	imp.files[fileVersionKey{File: entry.File, Version: version}] = true
+	return nil
+}
+
+func (imp *importer) driveVersion(d *importDrive, entry archiveEntry) error {
+	version := resource.Version(entry.Seq)
+	if version != d.versions {
+		return driveversion.OutOfOrder{Drive: entry.Drive, Version: version, Expected: d.versions}
+	}
+	var data resource.DriveData
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return driveversion.InvalidData{Drive: entry.Drive, Version: version}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Version(version).Create(data); err != nil {
+			return err
+		}
+	}
+	d.versions++
+	return nil
+}
+
+func (imp *importer) driveView(d *importDrive, entry archiveEntry) error {
+	seqNum := commit.SeqNum(entry.Seq)
+	if seqNum < 0 || seqNum >= d.commits {
+		return commit.NotFound{Drive: entry.Drive, Commit: seqNum}
+	}
+	if seqNum < d.views {
+		return fmt.Errorf("drive %s: the view at commit %d is out of order", entry.Drive, seqNum)
+	}
+	var version resource.Version
+	if err := json.Unmarshal(entry.Data, &version); err != nil {
+		return fmt.Errorf("drive %s: invalid view data at commit %d: %v", entry.Drive, seqNum, err)
+	}
+	if version < 0 || version >= d.versions {
+		return driveversion.NotFound{Drive: entry.Drive, Version: version}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).View().Add(seqNum, version); err != nil {
+			return err
+		}
+	}
+	d.views = seqNum + 1
+	return nil
+}
+
+func (imp *importer) actors(entry archiveEntry) error {
+	var entries []driveactor.Entry
+	if err := json.Unmarshal(entry.Data, &entries); err != nil {
+		return fmt.Errorf("drive %s: invalid actor data: %v", entry.Drive, err)
+	}
+	if !imp.apply {
+		return nil
+	}
+	return imp.repo.Drive(entry.Drive).Actors().Add(entries...)
+}
+
+func (imp *importer) consumer(d *importDrive, entry archiveEntry) error {
+	if entry.Name == "" {
+		return consumer.InvalidName{Drive: entry.Drive}
+	}
+	var data consumer.Data
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return consumer.InvalidData{Drive: entry.Drive, Name: entry.Name}
+	}
+	if data.Next() > d.commits {
+		return commit.NotFound{Drive: entry.Drive, Commit: data.Commit}
+	}
+	if imp.apply {
+		if err := imp.repo.Drive(entry.Drive).Consumers().Ref(entry.Name).Set(data); err != nil {
+			return err
+		}
+	}
+	d.consumers++
+	return nil
+}
@@ -5,6 +5,10 @@ import (
 	"syscall"
 
 	"github.com/gentlemanautomaton/signaler"
+	"github.com/scjalliance/drivestream/cache"
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/publish"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -13,10 +17,31 @@ func main() {
 		app             = kingpin.New("drivestream", "Collects and preserves team drive metadata.")
 		dbType          = app.Flag("db", "database type").Default("bolt").Envar("DB_TYPE").String()
 		dbPath          = app.Flag("file", "database file path").Default("drivestream.db").Envar("DB_PATH").String()
+		dbCodec         = app.Flag("codec", "value encoding for new data written to a bolt database").Envar("DB_CODEC").Enum(append(codec.Names(), codec.EncryptedName)...)
+		dbReadOnly      = app.Flag("read-only", "open the database in read-only mode").Envar("DB_READ_ONLY").Bool()
+		dbCache         = app.Flag("cache", "cache immutable data read from the database in memory").Envar("DB_CACHE").Bool()
+		dbKeyFile       = app.Flag("key-file", "file holding the encryption keys of a bolt database (the DB_KEYS variable can hold the keys instead)").Envar("DB_KEY_FILE").String()
 		includeMemStats = app.Flag("memstats", "include memory statistics in output").Envar("INCLUDE_MEMORY_STATS").Bool()
 		updateCommand   = app.Command("update", "Collects metadata and updates a drivestream database.")
 		updateEmail     = updateCommand.Flag("email", "email address of group or account to use during collection").Envar("GOOGLE_ACCOUNT").Required().String()
 		updateInterval  = updateCommand.Flag("interval", "interval between updates").Short('i').Envar("INTERVAL").Duration()
+		updateRetention = updateCommand.Flag("retention", "prune the pages of committed collections older than this (720h is 30 days)").Envar("PAGE_RETENTION").Duration()
+		updateFull      = updateCommand.Flag("full-interval", "perform a full collection and reconcile drift when the last one is older than this").Envar("FULL_COLLECTION_INTERVAL").Duration()
+		publishWebhook  = updateCommand.Flag("publish-webhook", "URL to post an event to for each finalized commit").Envar("PUBLISH_WEBHOOK").String()
+		publishSecret   = updateCommand.Flag("publish-secret", "secret used to sign webhook events").Envar("PUBLISH_SECRET").String()
+		publishSpool    = updateCommand.Flag("publish-spool", "directory to write NDJSON files of events for each finalized commit to").Envar("PUBLISH_SPOOL").String()
+		publishSpoolMax = updateCommand.Flag("publish-spool-max", "size at which spool files are rotated").Default("64M").Envar("PUBLISH_SPOOL_MAX").String()
+		publishConsumer = updateCommand.Flag("publish-consumer", "name of the consumer checkpoint that records publishing progress").Default(publish.DefaultConsumer).Envar("PUBLISH_CONSUMER").String()
+		authMethod      = updateCommand.Flag("auth", "method used to authenticate with Google").Default(authOAuth).Envar("AUTH_METHOD").Enum(authMethods...)
+		authCredentials = updateCommand.Flag("credentials", "OAuth client secret file, or service account key file when --auth is service-account").Default("credentials.json").Envar("CREDENTIALS_FILE").String()
+		authFlow        = updateCommand.Flag("oauth-flow", "flow used to authorize access when --auth is oauth and no token has been saved").Default(flowLoopback).Envar("OAUTH_FLOW").Enum(oauthFlows...)
+		authTokenFile   = updateCommand.Flag("token-file", "file that the oauth token is saved to").Default(defaultTokenFile()).Envar("TOKEN_FILE").String()
+		authTokenCrypt  = updateCommand.Flag("token-encrypt", "encrypt the saved oauth token with the database encryption keys").Envar("TOKEN_ENCRYPT").Bool()
+		updateMyDrive   = updateCommand.Flag("my-drive", "collect the My Drive of the account instead of team drives").Envar("MY_DRIVE").Bool()
+		watchAddress    = updateCommand.Flag("watch-address", "public HTTPS URL that drive change notifications are sent to (enables notifications)").Envar("WATCH_ADDRESS").String()
+		watchListen     = updateCommand.Flag("watch-listen", "address to receive drive change notifications on").Default(":8443").Envar("WATCH_LISTEN").String()
+		watchCert       = updateCommand.Flag("watch-cert", "TLS certificate file for the notification receiver").Envar("WATCH_CERT").String()
+		watchKey        = updateCommand.Flag("watch-key", "TLS key file for the notification receiver").Envar("WATCH_KEY").String()
 		updateWanted    = updateCommand.Arg("wanted", "team drives to update (name or ID)").Strings()
 		statsCommand    = app.Command("stats", "Reports statistics about a drivestream database.")
 		statsSelections = statsCommand.Flag("select", "statistics to select").Short('s').Default("collections", "commits").Strings()
@@ -24,6 +49,44 @@ func main() {
 		dumpCommand     = app.Command("dump", "Dumps team drive metadata currently stored within a drivestream database.")
 		dumpSelections  = dumpCommand.Flag("selection", "kinds of data to dump").Short('s').Default("collections", "commits").Strings()
 		dumpWanted      = dumpCommand.Arg("wanted", "team drives to dump (name or ID)").Strings()
+		auditCommand    = app.Command("audit", "Reports changes recorded within a drivestream database.")
+		auditFiles      = auditCommand.Flag("file", "file IDs to report changes for").Strings()
+		auditPath       = auditCommand.Flag("path", "path prefix to report changes for").String()
+		auditActors     = auditCommand.Flag("actor", "actors to report changes for (email or name)").Strings()
+		auditMimeTypes  = auditCommand.Flag("mime", "mime types to report changes for").Strings()
+		auditKinds      = auditCommand.Flag("kind", "kinds of changes to report").Enums(auditEventKinds...)
+		auditSince      = auditCommand.Flag("since", "report changes at or after this time (RFC 3339, date or duration ago)").String()
+		auditUntil      = auditCommand.Flag("until", "report changes before this time (RFC 3339, date or duration ago)").String()
+		auditFormat     = auditCommand.Flag("format", "output format").Short('f').Default(auditTable).Enum(auditTable, auditCSV, auditJSONL)
+		auditWanted     = auditCommand.Arg("wanted", "team drives to audit (name or ID)").Strings()
+		exportCommand   = app.Command("export", "Exports records from a drivestream database in a stable format.")
+		exportTypes     = exportCommand.Flag("type", "record types to export").Short('t').Default(exportCommits).Enums(exportRecordTypes...)
+		exportFormat    = exportCommand.Flag("format", "output format").Short('f').Default(exportJSONL).Enum(exportJSONL, exportCSV, exportParquet, exportArchive)
+		exportOutput    = exportCommand.Flag("output", "output directory (archive file for the archive format), or - for standard output").Short('o').Default("-").String()
+		exportFrom      = exportCommand.Flag("from", "first commit sequence number to export for a drive, as drive=seqnum (repeatable; drive is a name or ID)").Strings()
+		exportWanted    = exportCommand.Arg("wanted", "team drives to export (name or ID)").Strings()
+		importCommand   = app.Command("import", "Imports an archive created by export into a drivestream database.")
+		importPath      = importCommand.Arg("archive", "archive file path").Required().String()
+		importWanted    = importCommand.Arg("wanted", "team drives to import (name or ID)").Strings()
+		migrateCommand  = app.Command("migrate", "Copies drives from one drivestream database to another.")
+		migrateFrom     = migrateCommand.Flag("from", "source database (type:path)").Required().String()
+		migrateTo       = migrateCommand.Flag("to", "destination database (type:path)").Required().String()
+		migrateWanted   = migrateCommand.Arg("wanted", "team drives to migrate (name or ID)").Strings()
+		serveCommand    = app.Command("serve", "Serves a read-only HTTP/JSON API for a drivestream database.")
+		serveAddr       = serveCommand.Flag("listen", "address to listen on").Default(":8080").Envar("LISTEN_ADDR").String()
+		hostCommand     = app.Command("serve-repo", "Exposes a drivestream database to remote clients, including writes.")
+		hostAddr        = hostCommand.Flag("listen", "address to listen on").Default(":8081").Envar("LISTEN_ADDR").String()
+		hostToken       = hostCommand.Flag("token", "bearer token that clients must present (the DB_REMOTE_TOKEN variable holds the client's token)").Envar("REMOTE_TOKEN").String()
+		hostInsecure    = hostCommand.Flag("insecure", "allow any client to connect without a token").Bool()
+		consumerCommand = app.Command("consumers", "Manages the checkpoints of downstream consumers.")
+		consumerList    = consumerCommand.Command("list", "Lists consumers and their progress.").Default()
+		consumerWanted  = consumerList.Arg("wanted", "team drives to list consumers for (name or ID)").Strings()
+		consumerReset   = consumerCommand.Command("reset", "Resets the progress of a consumer.")
+		consumerNext    = consumerReset.Flag("next", "sequence number of the next commit the consumer should process").Int64()
+		consumerDrive   = consumerReset.Arg("drive", "team drive of the consumer (name or ID)").Required().String()
+		consumerName    = consumerReset.Arg("consumer", "name of the consumer").Required().String()
+		fsckCommand     = app.Command("fsck", "Checks a drivestream database for corruption.").Alias("verify")
+		fsckRepair      = fsckCommand.Flag("repair", "remove malformed keys that can be safely discarded").Bool()
 	)
 
 	shutdown := signaler.New().Capture(os.Interrupt, syscall.SIGTERM)
@@ -32,15 +95,78 @@ func main() {
 
 	command := kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	repo, repoClose := NewRepository(app, *dbType, *dbPath)
+	LoadKeys(app, *dbKeyFile, os.Getenv("DB_KEYS"))
+
+	// The migrate command opens its own source and destination databases.
+	if command == migrateCommand.FullCommand() {
+		migrate(ctx, app, *migrateFrom, *migrateTo, *dbCodec, *migrateWanted)
+		return
+	}
+
+	repo, repoClose := NewRepository(app, *dbType, *dbPath, *dbReadOnly)
 	defer repoClose()
+	if *dbCodec != "" {
+		SetCodec(app, repo, *dbCodec)
+	}
+	lock, _ := repo.(releaser)
+	if *dbCache && command != fsckCommand.FullCommand() {
+		repo = cache.New(repo)
+	}
 
 	switch command {
 	case updateCommand.FullCommand():
-		update(ctx, app, repo, *includeMemStats, *updateEmail, *updateInterval, *updateWanted)
+		pub := newPublisher(app, repo, *publishConsumer, *publishWebhook, *publishSecret, *publishSpool, *publishSpoolMax)
+		auth := authConfig{
+			Method:       *authMethod,
+			Credentials:  *authCredentials,
+			Subject:      *updateEmail,
+			Flow:         *authFlow,
+			TokenFile:    *authTokenFile,
+			TokenEncrypt: *authTokenCrypt,
+		}
+		watch := watchConfig{
+			Address: *watchAddress,
+			Listen:  *watchListen,
+			Cert:    *watchCert,
+			Key:     *watchKey,
+		}
+		update(ctx, app, repo, lock, pub, *includeMemStats, *updateEmail, *updateInterval, *updateRetention, *updateFull, *updateMyDrive, auth, watch, *updateWanted)
 	case statsCommand.FullCommand():
 		stats(ctx, app, repo, *statsSelections, *statsWanted)
 	case dumpCommand.FullCommand():
 		dump(ctx, app, repo, *dumpSelections, *dumpWanted)
+	case auditCommand.FullCommand():
+		since, err := parseAuditTime(*auditSince)
+		if err != nil {
+			app.Fatalf("invalid since time: %v", err)
+		}
+		until, err := parseAuditTime(*auditUntil)
+		if err != nil {
+			app.Fatalf("invalid until time: %v", err)
+		}
+		filter := auditFilter{
+			Files:     *auditFiles,
+			Prefix:    *auditPath,
+			Actors:    *auditActors,
+			MimeTypes: *auditMimeTypes,
+			Kinds:     *auditKinds,
+			Since:     since,
+			Until:     until,
+		}
+		audit(ctx, app, repo, filter, *auditFormat, *auditWanted)
+	case exportCommand.FullCommand():
+		export(ctx, app, repo, *exportTypes, *exportFormat, *exportOutput, *exportFrom, *exportWanted)
+	case importCommand.FullCommand():
+		importArchive(ctx, app, repo, *importPath, *importWanted)
+	case serveCommand.FullCommand():
+		serve(ctx, app, repo, *serveAddr)
+	case hostCommand.FullCommand():
+		serveRepository(ctx, app, repo, *hostAddr, *hostToken, *hostInsecure)
+	case consumerList.FullCommand():
+		listConsumers(ctx, app, repo, *consumerWanted)
+	case consumerReset.FullCommand():
+		resetConsumer(ctx, app, repo, *consumerDrive, *consumerName, commit.SeqNum(*consumerNext))
+	case fsckCommand.FullCommand():
+		fsck(ctx, app, repo, *fsckRepair)
 	}
 }
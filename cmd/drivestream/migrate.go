@@ -0,0 +1,550 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// migrateProgressInterval is the minimum interval between progress
+// reports during a migration.
+const migrateProgressInterval = 5 * time.Second
+
+func migrate(ctx context.Context, app *kingpin.Application, from, to, codecName string, wanted []string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	srcType, srcPath, err := parseRepositorySpec(from)
+	if err != nil {
+		app.Fatalf("invalid source repository: %v", err)
+	}
+	dstType, dstPath, err := parseRepositorySpec(to)
+	if err != nil {
+		app.Fatalf("invalid destination repository: %v", err)
+	}
+	if srcType == dstType && srcPath == dstPath {
+		app.Fatalf("the source and destination repositories must be different")
+	}
+
+	src, srcClose := NewRepository(app, srcType, srcPath, false)
+	defer srcClose()
+	dst, dstClose := NewRepository(app, dstType, dstPath, false)
+	defer dstClose()
+	if codecName != "" {
+		SetCodec(app, dst, codecName)
+	}
+
+	ids, err := src.Drives().List()
+	if err != nil {
+		app.Fatalf("failed to enumerate source repository: %v", err)
+	}
+
+	m := newMigrator(src, dst)
+
+	var migrated []resource.ID
+	for _, driveID := range ids {
+		drv := src.Drive(driveID)
+
+		if data, ok := driveData(drv); ok {
+			if !isWanted(wanted, string(driveID), data.Name) {
+				continue
+			}
+		} else {
+			if !isWanted(wanted, string(driveID)) {
+				continue
+			}
+		}
+
+		if err := m.Drive(ctx, driveID); err != nil {
+			if ctx.Err() != nil {
+				fmt.Printf("DRIVE %s: migration interrupted, run the command again to resume\n", driveID)
+				return
+			}
+			app.Fatalf("failed to migrate drive %s: %v", driveID, err)
+		}
+		migrated = append(migrated, driveID)
+	}
+
+	failed := false
+	for _, driveID := range migrated {
+		mismatches, err := verifyMigration(src.Drive(driveID), dst.Drive(driveID))
+		if err != nil {
+			app.Fatalf("failed to verify drive %s: %v", driveID, err)
+		}
+		if len(mismatches) == 0 {
+			fmt.Printf("DRIVE %s: VERIFIED\n", driveID)
+			continue
+		}
+		failed = true
+		for _, mismatch := range mismatches {
+			fmt.Printf("DRIVE %s: MISMATCH: %s\n", driveID, mismatch)
+		}
+	}
+	if failed {
+		app.Fatalf("the destination repository does not match the source")
+	}
+}
+
+// parseRepositorySpec parses a repository specification of the form
+// type:path.
+func parseRepositorySpec(spec string) (dbType, path string, err error) {
+	i := strings.Index(spec, ":")
+	if i <= 0 {
+		return "", "", fmt.Errorf("\"%s\" is not of the form type:path", spec)
+	}
+	return spec[:i], spec[i+1:], nil
+}
+
+// verifyMigration compares the record counts reported by the statistics
+// of two drives. It returns a description of each count that differs.
+func verifyMigration(src, dst drivestream.DriveReference) (mismatches []string, err error) {
+	a, err := src.Stats()
+	if err != nil {
+		return nil, err
+	}
+	b, err := dst.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	compare := func(name string, x, y int64) {
+		if x != y {
+			mismatches = append(mismatches, fmt.Sprintf("%s: source %d, destination %d", name, x, y))
+		}
+	}
+	compare("collections", a.Collections, b.Collections)
+	compare("commits", a.Commits, b.Commits)
+	compare("reconciliations", a.Reconciliations, b.Reconciliations)
+	compare("drive versions", a.Versions, b.Versions)
+	compare("drive view commits", a.ViewCommits, b.ViewCommits)
+	compare("files", a.Files.Count, b.Files.Count)
+	compare("file versions", a.Files.Versions, b.Files.Versions)
+	compare("file view commits", a.Files.ViewCommits, b.Files.ViewCommits)
+	return mismatches, nil
+}
+
+// migrator copies drives from one repository to another.
+//
+// Each part of a drive is copied by comparing the length of its sequence
+// in both repositories and copying only what is missing from the
+// destination, so an interrupted migration can be resumed by running it
+// again.
+type migrator struct {
+	src   drivestream.Repository
+	dst   drivestream.Repository
+	files map[resource.ID]bool
+	last  time.Time
+}
+
+// newMigrator returns a migrator that copies from src to dst.
+func newMigrator(src, dst drivestream.Repository) *migrator {
+	return &migrator{
+		src:   src,
+		dst:   dst,
+		files: make(map[resource.ID]bool),
+	}
+}
+
+// progress prints a progress report if enough time has passed since the
+// last one.
+func (m *migrator) progress(driveID resource.ID, format string, args ...interface{}) {
+	now := time.Now()
+	if now.Sub(m.last) < migrateProgressInterval {
+		return
+	}
+	m.last = now
+	fmt.Printf("DRIVE %s: %s\n", driveID, fmt.Sprintf(format, args...))
+}
+
+// Drive copies a drive from the source repository to the destination.
+func (m *migrator) Drive(ctx context.Context, driveID resource.ID) error {
+	fmt.Printf("DRIVE %s: MIGRATING\n", driveID)
+	if err := m.collections(ctx, driveID); err != nil {
+		return err
+	}
+	if err := m.commits(ctx, driveID); err != nil {
+		return err
+	}
+	if err := m.driveVersions(driveID); err != nil {
+		return err
+	}
+	if err := m.driveView(driveID); err != nil {
+		return err
+	}
+	if err := m.actors(driveID); err != nil {
+		return err
+	}
+	if err := m.consumers(driveID); err != nil {
+		return err
+	}
+	fmt.Printf("DRIVE %s: MIGRATED\n", driveID)
+	return nil
+}
+
+func (m *migrator) collections(ctx context.Context, driveID resource.ID) error {
+	src := m.src.Drive(driveID)
+	dst := m.dst.Drive(driveID)
+
+	end, err := src.Collections().Next()
+	if err != nil {
+		return err
+	}
+	have, err := dst.Collections().Next()
+	if err != nil {
+		return err
+	}
+	if have > end {
+		return fmt.Errorf("the destination has %d collections but the source only has %d", have, end)
+	}
+
+	for seqNum := collection.SeqNum(0); seqNum < end; seqNum++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		srcCol := src.Collection(seqNum)
+		dstCol := dst.Collection(seqNum)
+
+		if seqNum >= have {
+			data, err := srcCol.Data()
+			if err != nil {
+				return err
+			}
+			if err := dstCol.Create(data); err != nil {
+				return err
+			}
+		}
+
+		// Pages are copied before states so that a collection is not
+		// marked finalized in the destination before all of its pages
+		// are present.
+		pageEnd, err := srcCol.Pages().Next()
+		if err != nil {
+			return err
+		}
+		pageNum, err := dstCol.Pages().Next()
+		if err != nil {
+			return err
+		}
+		for ; pageNum < pageEnd; pageNum++ {
+			data, err := srcCol.Page(pageNum).Data()
+			if err != nil {
+				return err
+			}
+			if err := dstCol.Page(pageNum).Create(data); err != nil {
+				return err
+			}
+		}
+
+		stateEnd, err := srcCol.States().Next()
+		if err != nil {
+			return err
+		}
+		stateNum, err := dstCol.States().Next()
+		if err != nil {
+			return err
+		}
+		for ; stateNum < stateEnd; stateNum++ {
+			state, err := srcCol.State(stateNum).Data()
+			if err != nil {
+				return err
+			}
+			if err := dstCol.State(stateNum).Create(state); err != nil {
+				return err
+			}
+		}
+
+		m.progress(driveID, "COLLECTION %d/%d", seqNum+1, end)
+	}
+
+	return nil
+}
+
+func (m *migrator) commits(ctx context.Context, driveID resource.ID) error {
+	src := m.src.Drive(driveID)
+	dst := m.dst.Drive(driveID)
+
+	end, err := src.Commits().Next()
+	if err != nil {
+		return err
+	}
+	have, err := dst.Commits().Next()
+	if err != nil {
+		return err
+	}
+	if have > end {
+		return fmt.Errorf("the destination has %d commits but the source only has %d", have, end)
+	}
+
+	for seqNum := commit.SeqNum(0); seqNum < end; seqNum++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		srcCom := src.Commit(seqNum)
+		dstCom := dst.Commit(seqNum)
+
+		if seqNum >= have {
+			data, err := srcCom.Data()
+			if err != nil {
+				return err
+			}
+			if err := dstCom.Create(data); err != nil {
+				return err
+			}
+		}
+
+		stateEnd, err := srcCom.States().Next()
+		if err != nil {
+			return err
+		}
+		stateNum, err := dstCom.States().Next()
+		if err != nil {
+			return err
+		}
+		if stateNum >= stateEnd {
+			continue
+		}
+
+		// The file and tree changes of a commit are copied in full
+		// whenever its states are incomplete. Both are maps, so copying
+		// them again after an interruption is harmless.
+		if err := m.commitFiles(driveID, srcCom, dstCom); err != nil {
+			return err
+		}
+		if err := m.commitTree(srcCom, dstCom); err != nil {
+			return err
+		}
+		drift, err := srcCom.Drift()
+		if err != nil {
+			return err
+		}
+		if !drift.IsZero() {
+			if err := dstCom.SetDrift(drift); err != nil {
+				return err
+			}
+		}
+
+		// States are copied last so that a commit is not marked
+		// finalized in the destination before all of its changes are
+		// present.
+		for ; stateNum < stateEnd; stateNum++ {
+			state, err := srcCom.State(stateNum).Data()
+			if err != nil {
+				return err
+			}
+			if err := dstCom.State(stateNum).Create(state); err != nil {
+				return err
+			}
+		}
+
+		m.progress(driveID, "COMMIT %d/%d", seqNum+1, end)
+	}
+
+	return nil
+}
+
+func (m *migrator) commitFiles(driveID resource.ID, src, dst commit.Reference) error {
+	changes, err := src.Files().Read()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	views := make([]fileview.Data, 0, len(changes))
+	for _, change := range changes {
+		if err := m.fileVersions(change.File); err != nil {
+			return err
+		}
+		views = append(views, fileview.Data{
+			File:    change.File,
+			Drive:   driveID,
+			Commit:  src.SeqNum(),
+			Version: change.Version,
+		})
+	}
+
+	if err := m.dst.Files().AddViewData(views...); err != nil {
+		return err
+	}
+	return dst.Files().Add(changes...)
+}
+
+func (m *migrator) commitTree(src, dst commit.Reference) error {
+	parents, err := src.Tree().Parents()
+	if err != nil {
+		return err
+	}
+	var changes []commit.TreeChange
+	for _, parent := range parents {
+		group, err := src.Tree().Group(parent).Changes()
+		if err != nil {
+			return err
+		}
+		changes = append(changes, group...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return dst.Tree().Add(changes...)
+}
+
+// fileVersions copies every version of a file that is missing from the
+// destination. Each file is only examined once per migration.
+func (m *migrator) fileVersions(id resource.ID) error {
+	if m.files[id] {
+		return nil
+	}
+
+	versions, err := m.src.File(id).Versions().List()
+	if err != nil {
+		return err
+	}
+	existing, err := m.dst.File(id).Versions().List()
+	if err != nil {
+		return err
+	}
+	have := make(map[resource.Version]bool, len(existing))
+	for _, v := range existing {
+		have[v] = true
+	}
+
+	for _, v := range versions {
+		if have[v] {
+			continue
+		}
+		data, err := m.src.File(id).Version(v).Data()
+		if err != nil {
+			return err
+		}
+		if err := m.dst.File(id).Version(v).Create(data); err != nil {
+			return err
+		}
+	}
+
+	m.files[id] = true
+	return nil
+}
+
+func (m *migrator) driveVersions(driveID resource.ID) error {
+	src := m.src.Drive(driveID).Versions()
+	dst := m.dst.Drive(driveID).Versions()
+
+	end, err := src.Next()
+	if err != nil {
+		return err
+	}
+	v, err := dst.Next()
+	if err != nil {
+		return err
+	}
+	if v > end {
+		return fmt.Errorf("the destination has %d drive versions but the source only has %d", v, end)
+	}
+
+	buf := make([]resource.DriveData, 64)
+	for v < end {
+		n, err := src.Read(v, buf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		for i := 0; i < n; i++ {
+			if err := dst.Ref(v).Create(buf[i]); err != nil {
+				return err
+			}
+			v++
+		}
+	}
+	return nil
+}
+
+func (m *migrator) driveView(driveID resource.ID) error {
+	entries, err := m.src.Drive(driveID).View().Entries()
+	if err != nil {
+		return err
+	}
+	dst := m.dst.Drive(driveID).View()
+	for _, entry := range entries {
+		if err := dst.Add(entry.Commit, entry.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migrator) actors(driveID resource.ID) error {
+	type key struct {
+		Time   int64
+		Commit commit.SeqNum
+		File   resource.ID
+	}
+
+	src := m.src.Drive(driveID).Actors()
+	dst := m.dst.Drive(driveID).Actors()
+
+	emails, err := src.List()
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		entries, err := src.Ref(email).Read(time.Time{}, time.Time{})
+		if err != nil {
+			return err
+		}
+		existing, err := dst.Ref(email).Read(time.Time{}, time.Time{})
+		if err != nil {
+			return err
+		}
+		have := make(map[key]bool, len(existing))
+		for _, entry := range existing {
+			have[key{entry.Time.UnixNano(), entry.Commit, entry.File}] = true
+		}
+		var missing []driveactor.Entry
+		for _, entry := range entries {
+			if !have[key{entry.Time.UnixNano(), entry.Commit, entry.File}] {
+				missing = append(missing, entry)
+			}
+		}
+		if len(missing) > 0 {
+			if err := dst.Add(missing...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *migrator) consumers(driveID resource.ID) error {
+	src := m.src.Drive(driveID).Consumers()
+	dst := m.dst.Drive(driveID).Consumers()
+
+	names, err := src.List()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		data, err := src.Ref(name).Data()
+		if err != nil {
+			return err
+		}
+		if err := dst.Ref(name).Set(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,82 +2,152 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"net"
 	"net/http"
-	"os"
+	"time"
 
+	"github.com/scjalliance/drivestream/tokenstore"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
-// https://developers.google.com/drive/api/v3/quickstart/go
+// OAuth authorization flows.
+//
+// Google's device flow isn't offered. It only grants a few scopes, and
+// drive.readonly, which update requires, isn't one of them.
+const flowLoopback = "loopback"
 
-func getConfig(scope ...string) *oauth2.Config {
-	b, err := ioutil.ReadFile("credentials.json")
+var oauthFlows = []string{flowLoopback}
+
+// authorizationTimeout limits the time spent waiting for the user to
+// authorize access.
+const authorizationTimeout = 10 * time.Minute
+
+func getConfig(app *kingpin.Application, path string, scope ...string) *oauth2.Config {
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
-		log.Fatalf("Unable to read client secret file: %v", err)
+		app.Fatalf("unable to read client secret file: %v", err)
 	}
 
-	// If modifying these scopes, delete your previously saved token.json.
+	// If modifying these scopes, delete your previously saved token.
 	config, err := google.ConfigFromJSON(b, scope...)
 	if err != nil {
-		log.Fatalf("Unable to parse client secret file to config: %v", err)
+		app.Fatalf("unable to parse client secret file to config: %v", err)
 	}
 
 	return config
 }
 
-// Retrieve a token, saves the token, then returns the generated client.
-func getClient(config *oauth2.Config) *http.Client {
-	tokenFile := "token.json"
-	tok, err := tokenFromFile(tokenFile)
+// getClient loads a token from store, or requests one from the user with
+// the given flow if none has been saved, and returns a client that uses
+// it. Refreshed tokens are written back to store.
+func getClient(ctx context.Context, app *kingpin.Application, config *oauth2.Config, flow string, store tokenstore.Store) *http.Client {
+	tok, err := store.Load()
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(tokenFile, tok)
+		if _, ok := err.(tokenstore.NotFound); !ok {
+			app.Fatalf("unable to load oauth token: %v", err)
+		}
+		switch flow {
+		case flowLoopback, "":
+			tok, err = getTokenFromLoopback(ctx, config)
+		default:
+			app.Fatalf("unrecognized oauth flow: %s", flow)
+		}
+		if err != nil {
+			app.Fatalf("unable to retrieve oauth token: %v", err)
+		}
+		if err := store.Save(tok); err != nil {
+			app.Fatalf("unable to save oauth token: %v", err)
+		}
 	}
-	return config.Client(context.Background(), tok)
+	return oauth2.NewClient(ctx, tokenstore.TokenSource(ctx, config, store, tok))
 }
 
-// Request a token from the web, then returns the retrieved token.
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Printf("Go to the following link in your browser then type the "+
-		"authorization code: \n%v\n", authURL)
+// getTokenFromLoopback requests a token by sending the user to an
+// authorization page that redirects back to a temporary server on the
+// loopback interface.
+func getTokenFromLoopback(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, authorizationTimeout)
+	defer cancel()
 
-	var authCode string
-	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("Unable to read authorization code %v", err)
+	state, err := randomState()
+	if err != nil {
+		return nil, err
 	}
+	verifier := oauth2.GenerateVerifier()
 
-	tok, err := config.Exchange(oauth2.NoContext, authCode)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web %v", err)
+		return nil, fmt.Errorf("unable to listen for the authorization redirect: %v", err)
 	}
-	return tok
-}
 
-// Retrieves a token from a local file.
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
-	defer f.Close()
-	if err != nil {
-		return nil, err
+	redirected := *config
+	redirected.RedirectURL = fmt.Sprintf("http://%s/", listener.Addr())
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	deliver := func(r result) {
+		select {
+		case results <- r:
+		default:
+		}
 	}
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 {
+				http.Error(w, "Invalid authorization state.", http.StatusBadRequest)
+				return
+			}
+			if reason := query.Get("error"); reason != "" {
+				http.Error(w, "Authorization failed.", http.StatusForbidden)
+				deliver(result{err: fmt.Errorf("authorization failed: %s", reason)})
+				return
+			}
+			code := query.Get("code")
+			if code == "" {
+				http.Error(w, "Missing authorization code.", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, "Authorization complete. You can close this window.")
+			deliver(result{code: code})
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := redirected.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("Go to the following link in your browser to authorize access:\n%v\n", authURL)
+
+	var r result
+	select {
+	case r = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return redirected.Exchange(ctx, r.code, oauth2.VerifierOption(verifier))
 }
 
-// Saves a token to a file path.
-func saveToken(path string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	defer f.Close()
-	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
+// randomState returns a random value for the state parameter of an
+// authorization request.
+func randomState() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
 	}
-	json.NewEncoder(f).Encode(token)
+	return hex.EncodeToString(b[:]), nil
 }
@@ -15,7 +15,7 @@ func listPermissions(ctx context.Context, s *drive.Service, id string) (perms []
 	for {
 		call := s.Permissions.List(id)
 		call.Context(ctx)
-		call.SupportsTeamDrives(true)
+		call.SupportsAllDrives(true)
 		call.UseDomainAdminAccess(true)
 		call.Fields("nextPageToken", "permissions(id,type,emailAddress,domain,role,displayName,expirationTime,deleted)")
 
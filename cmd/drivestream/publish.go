@@ -0,0 +1,41 @@
+package main
+
+import (
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/publish"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// newPublisher returns a publisher for the configured sinks, or nil if no
+// sinks are configured.
+func newPublisher(app *kingpin.Application, repo drivestream.Repository, consumer, webhook, secret, spool, spoolMax string) *publish.Publisher {
+	options := []publish.Option{publish.WithConsumer(consumer)}
+
+	if webhook != "" {
+		var webhookOptions []publish.WebhookOption
+		if secret != "" {
+			webhookOptions = append(webhookOptions, publish.WithSecret([]byte(secret)))
+		}
+		options = append(options, publish.WithSink(publish.NewWebhook(webhook, webhookOptions...)))
+	} else if secret != "" {
+		app.Fatalf("a publishing secret was provided without a webhook")
+	}
+
+	if spool != "" {
+		size, err := bytefmt.ToBytes(spoolMax)
+		if err != nil {
+			app.Fatalf("invalid spool size \"%s\": %v", spoolMax, err)
+		}
+		sink, err := publish.NewSpool(spool, publish.WithMaxSize(int64(size)))
+		if err != nil {
+			app.Fatalf("failed to prepare spool directory: %v", err)
+		}
+		options = append(options, publish.WithSink(sink))
+	}
+
+	if len(options) == 1 {
+		return nil
+	}
+	return publish.New(repo, options...)
+}
@@ -1,26 +1,160 @@
 package main
 
 import (
+	"io/ioutil"
+	"os"
+	"time"
+
 	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream"
 	"github.com/scjalliance/drivestream/boltrepo"
+	"github.com/scjalliance/drivestream/codec"
 	"github.com/scjalliance/drivestream/memrepo"
+	"github.com/scjalliance/drivestream/readonly"
+	"github.com/scjalliance/drivestream/remoterepo"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
-// NewRepository returns an instance of the requested database.
-func NewRepository(app *kingpin.Application, dbType, path string) (drivestream.Repository, func() error) {
+// boltLockTimeout is how long to wait for the file lock of a bolt
+// database. Bolt holds an exclusive lock for as long as a database is open
+// for writing, so a process that is running update would otherwise block
+// every other process indefinitely.
+const boltLockTimeout = 10 * time.Second
+
+// NewRepository returns an instance of the requested database. If readOnly
+// is true the repository is wrapped so that it cannot be modified, and bolt
+// databases are opened in read-only mode.
+func NewRepository(app *kingpin.Application, dbType, path string, readOnly bool) (drivestream.Repository, func() error) {
+	var (
+		repo   drivestream.Repository
+		closer func() error
+	)
 	switch dbType {
 	case "bolt":
-		boltDB, err := bolt.Open(path, 0600, nil)
-		if err != nil {
-			app.Errorf("failed to create or open bolt database: %v", err)
+		session, err := openBoltSession(path, readOnly)
+		switch {
+		case err == bolt.ErrTimeout && readOnly:
+			app.Fatalf("failed to open bolt database: the database is locked by a writer; read-only commands can't run while another process has it open for writing")
+		case err == bolt.ErrTimeout:
+			app.Fatalf("failed to open bolt database: the database is locked by another process")
+		case err != nil:
+			app.Fatalf("failed to open bolt database: %v", err)
 		}
-		return boltrepo.New(boltDB), boltDB.Close
+		repo, closer = session, session.Release
 	case "in-memory", "mem", "memory":
-		return memrepo.New(), func() error { return nil }
+		repo, closer = memrepo.New(), func() error { return nil }
+	case "remote":
+		// The path is the base URL of a server started by serve-repo.
+		repo, closer = remoterepo.NewClient(path, remoterepo.WithToken(os.Getenv("DB_REMOTE_TOKEN"))), func() error { return nil }
 	default:
 		app.Fatalf("unrecognized database type: %s", dbType)
 		return nil, nil
 	}
+	if readOnly {
+		repo = readonly.New(repo)
+	}
+	return repo, closer
+}
+
+// boltSession is a bolt repository that can release its database and
+// open it again later. Bolt holds an exclusive lock on a database for as
+// long as it is open for writing, so update releases the database while it
+// sleeps to let read-only commands open it between update cycles.
+//
+// The repository must not be used while the database is released.
+type boltSession struct {
+	boltrepo.Repository
+	path     string
+	readOnly bool
+	db       *bolt.DB
+}
+
+// openBoltSession opens the bolt database at path and returns a session
+// that holds it.
+func openBoltSession(path string, readOnly bool) (*boltSession, error) {
+	s := &boltSession{path: path, readOnly: readOnly}
+	if err := s.Acquire(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Acquire opens the database of the session if it has been released. It
+// returns bolt.ErrTimeout if the database is locked by another process.
+func (s *boltSession) Acquire() error {
+	if s.db != nil {
+		return nil
+	}
+	db, err := bolt.Open(s.path, 0600, &bolt.Options{ReadOnly: s.readOnly, Timeout: boltLockTimeout})
+	if err != nil {
+		return err
+	}
+	repo, err := boltrepo.Open(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+	s.db, s.Repository = db, repo
+	return nil
+}
+
+// Release closes the database of the session, which releases its lock.
+func (s *boltSession) Release() error {
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db, s.Repository = nil, boltrepo.Repository{}
+	return err
+}
+
+// LoadKeys registers an encrypted codec holding the keys within the file
+// at path, or within keys if path is empty. The codec compresses values
+// with zstd before encrypting them. Nothing is registered if no keys are
+// provided.
+func LoadKeys(app *kingpin.Application, path, keys string) {
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			app.Fatalf("failed to read encryption keys: %v", err)
+		}
+		keys = string(data)
+	}
+	if keys == "" {
+		return
+	}
+	keyring, err := codec.ParseKeyring(keys)
+	if err != nil {
+		app.Fatalf("invalid encryption keys: %v", err)
+	}
+	codec.Register(codec.NewEncrypted(codec.ZstdJSON, keyring))
+}
+
+// SetCodec changes the codec used to encode new values written to repo.
+// Only bolt repositories encode their values.
+func SetCodec(app *kingpin.Application, repo drivestream.Repository, name string) {
+	c, err := codec.Lookup(name)
+	if err != nil {
+		if name == codec.EncryptedName {
+			app.Fatalf("encryption keys must be provided to use the %s codec", name)
+		}
+		app.Fatalf("%v", err)
+	}
+	if _, ok := repo.(readonly.Repository); ok {
+		app.Fatalf("the codec of a read-only database cannot be changed")
+	}
+	session, ok := repo.(*boltSession)
+	if !ok {
+		app.Fatalf("value codecs are not supported by %s databases", repo.Type())
+	}
+	current, err := session.Codec()
+	if err != nil {
+		app.Fatalf("failed to determine the codec of the bolt database: %v", err)
+	}
+	if current.ID() == c.ID() {
+		return
+	}
+	if err := session.SetCodec(c); err != nil {
+		app.Fatalf("failed to change the codec of the bolt database: %v", err)
+	}
 }
@@ -10,34 +10,34 @@ import (
 	drive "google.golang.org/api/drive/v3"
 )
 
-func selectTeamDrives(ctx context.Context, s *drive.Service, email string, wanted []string) (drives []resource.Drive, err error) {
+func selectDrives(ctx context.Context, s *drive.Service, email string, wanted []string) (drives []resource.Drive, err error) {
 	var token string
 	for {
-		call := s.Teamdrives.List()
+		call := s.Drives.List()
 		call.Context(ctx)
-		call.Fields("nextPageToken", "teamDrives(id,name,capabilities,createdTime)")
+		call.Fields("nextPageToken", "drives(id,name,capabilities,createdTime)")
 		if token != "" {
 			call.PageToken(token)
 		}
 
 		list, err := call.Do()
 		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve team drive list: %v", err)
+			return nil, fmt.Errorf("unable to retrieve shared drive list: %v", err)
 		}
 
-		for _, teamDrive := range list.TeamDrives {
-			if !isWanted(wanted, teamDrive.Id, teamDrive.Name) {
+		for _, sharedDrive := range list.Drives {
+			if !isWanted(wanted, sharedDrive.Id, sharedDrive.Name) {
 				continue
 			}
 
 			switch {
-			case teamDrive.Capabilities == nil:
-			case !teamDrive.Capabilities.CanListChildren:
-			//case !teamDrive.Capabilities.CanReadRevisions:
+			case sharedDrive.Capabilities == nil:
+			case !sharedDrive.Capabilities.CanListChildren:
+			//case !sharedDrive.Capabilities.CanReadRevisions:
 			default:
-				if perms, err := listPermissions(ctx, s, teamDrive.Id); err == nil {
+				if perms, err := listPermissions(ctx, s, sharedDrive.Id); err == nil {
 					if hasDirectMembership(email, perms) {
-						if record, err := driveapicollector.MarshalDrive(teamDrive); err == nil {
+						if record, err := driveapicollector.MarshalDrive(sharedDrive); err == nil {
 							drives = append(drives, record)
 						}
 					}
@@ -53,6 +53,19 @@ func selectTeamDrives(ctx context.Context, s *drive.Service, email string, wante
 	}
 }
 
+// selectMyDrive returns the My Drive of the account that the drive service
+// acts on behalf of, if it is wanted.
+func selectMyDrive(ctx context.Context, s *drive.Service, wanted []string) (drives []resource.Drive, err error) {
+	record, err := driveapicollector.LookupMyDrive(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if !isWanted(wanted, string(record.ID), record.Name) {
+		return nil, nil
+	}
+	return []resource.Drive{record}, nil
+}
+
 func isWanted(wanted []string, values ...string) bool {
 	if len(wanted) == 0 {
 		return true
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/httpapi"
+	"github.com/scjalliance/drivestream/readonly"
+	"github.com/scjalliance/drivestream/remoterepo"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+func serve(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, addr string) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	// The API only reads from the repository, so make sure it can't write.
+	if _, ok := repo.(readonly.Repository); !ok {
+		repo = readonly.New(repo)
+	}
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: httpapi.New(repo),
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving the %s API for %s repository on %s\n", httpapi.Version, repo.Type(), addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		app.Fatalf("failed to serve API: %v", err)
+	}
+}
+
+func serveRepository(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, addr, token string, insecure bool) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	if token == "" {
+		if !insecure {
+			app.Fatalf("no token was provided: pass --token, or --insecure to let any client that can reach %s modify the database", addr)
+		}
+		fmt.Printf("WARNING: No token was provided. Any client that can reach %s can modify the database.\n", addr)
+	}
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: remoterepo.NewServer(repo, remoterepo.RequireToken(token)),
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving %s repository to remote clients on %s\n", repo.Type(), addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		app.Fatalf("failed to serve repository: %v", err)
+	}
+}
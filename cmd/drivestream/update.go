@@ -4,56 +4,107 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/boltdb/bolt"
 	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/cache"
 	"github.com/scjalliance/drivestream/driveapicollector"
+	"github.com/scjalliance/drivestream/publish"
+	"github.com/scjalliance/drivestream/resource"
 	drive "google.golang.org/api/drive/v3"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
-func update(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, includeMemStats bool, email string, interval time.Duration, wanted []string) {
+// releaser is implemented by repositories that can release their database
+// between updates, so that other processes can open it while update
+// sleeps.
+type releaser interface {
+	Acquire() error
+	Release() error
+}
+
+// updateConfig holds the settings used to update each drive.
+type updateConfig struct {
+	Retention    time.Duration
+	FullInterval time.Duration
+}
+
+func update(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, lock releaser, pub *publish.Publisher, includeMemStats bool, email string, interval, retention, fullInterval time.Duration, myDrive bool, auth authConfig, watch watchConfig, wanted []string) {
 	if ctx.Err() != nil {
 		return
 	}
 
-	client := getClient(getConfig(drive.DriveReadonlyScope))
+	client := newGoogleClient(ctx, app, auth, drive.DriveReadonlyScope)
 	driveService, err := drive.New(client)
 	if err != nil {
 		app.Fatalf("failed to create google drive client: %v", err)
 	}
 
+	if pub != nil {
+		defer pub.Close()
+	}
+
+	manager, stopWatching := startWatching(ctx, app, driveService, watch)
+	defer stopWatching()
+
+	// Notifications can be lost, so keep polling at a relaxed interval.
+	if manager != nil && interval == 0 {
+		interval = defaultWatchInterval
+	}
+
+	config := updateConfig{
+		Retention:    retention,
+		FullInterval: fullInterval,
+	}
+
+	var selected map[resource.ID]resource.Drive
 	for {
-		if ctx.Err() != nil {
+		if !acquire(ctx, app, lock) {
 			return
 		}
 
-		selection, err := selectTeamDrives(ctx, driveService, email, wanted)
-		if err != nil {
-			app.Fatalf("failed to enumerate team drives: %v", err)
+		var selection []resource.Drive
+		if myDrive {
+			selection, err = selectMyDrive(ctx, driveService, wanted)
+			if err != nil {
+				app.Fatalf("failed to look up my drive: %v", err)
+			}
+		} else {
+			selection, err = selectDrives(ctx, driveService, email, wanted)
+			if err != nil {
+				app.Fatalf("failed to enumerate team drives: %v", err)
+			}
 		}
 
+		previous := selected
+		selected = make(map[resource.ID]resource.Drive, len(selection))
 		for _, driveData := range selection {
 			if ctx.Err() != nil {
 				return
 			}
 
-			prefix := fmt.Sprintf("DRIVE %s", driveData.ID)
-
-			fmt.Printf("%s: NAME: %s\n", prefix, driveData.Name)
+			selected[driveData.ID] = driveData
+			updateDrive(ctx, app, repo, driveService, pub, config, driveData)
 
-			drv := repo.Drive(driveData.ID)
-			exists, err := drv.Exists()
-			if err != nil {
-				app.Fatalf("failed to enumerate team drives: %v", err)
-			}
-			if !exists {
-				fmt.Printf("%s: INIT: Repository (%s)\n", prefix, repo.Type())
+			if manager != nil {
+				if err := manager.Watch(ctx, driveData.ID); err != nil {
+					fmt.Printf("DRIVE %s: WATCH: ERROR: %v\n", driveData.ID, err)
+				}
 			}
+		}
 
-			collector := driveapicollector.New(driveService, string(driveData.ID))
-			stream := drivestream.New(repo, driveData.ID, drivestream.WithLogger(os.Stdout))
-			stream.Update(ctx, collector)
+		// Stop watching drives that are no longer selected.
+		if manager != nil {
+			for driveID := range previous {
+				if _, ok := selected[driveID]; ok {
+					continue
+				}
+				if err := manager.Unwatch(ctx, driveID); err != nil {
+					fmt.Printf("DRIVE %s: WATCH: ERROR: %v\n", driveID, err)
+				}
+			}
 		}
 
 		if includeMemStats {
@@ -61,6 +112,10 @@ func update(ctx context.Context, app *kingpin.Application, repo drivestream.Repo
 			// TODO: Include database statistics
 		}
 
+		if cached, ok := repo.(*cache.Repository); ok {
+			fmt.Println(strings.Join(cached.Stats().Summary(), "\n"))
+		}
+
 		if interval == 0 {
 			return
 		}
@@ -69,16 +124,119 @@ func update(ctx context.Context, app *kingpin.Application, repo drivestream.Repo
 			return
 		}
 
+		release(app, lock)
+
 		fmt.Printf("Sleeping %s\n", interval)
 
+		// Drives that receive notifications are updated while sleeping.
+		var notified <-chan struct{}
+		if manager != nil {
+			notified = manager.Notified()
+		}
+
 		t := time.NewTimer(interval)
-		select {
-		case <-t.C:
-		case <-ctx.Done():
-			if !t.Stop() {
-				<-t.C
+	sleep:
+		for {
+			select {
+			case <-t.C:
+				break sleep
+			case <-notified:
+				pending := manager.Pending()
+				if len(pending) == 0 {
+					continue
+				}
+				if !acquire(ctx, app, lock) {
+					return
+				}
+				for _, driveID := range pending {
+					driveData, ok := selected[driveID]
+					if !ok {
+						continue
+					}
+					fmt.Printf("DRIVE %s: NOTIFIED\n", driveID)
+					updateDrive(ctx, app, repo, driveService, pub, config, driveData)
+				}
+				release(app, lock)
+			case <-ctx.Done():
+				if !t.Stop() {
+					<-t.C
+				}
+				return
 			}
-			return
+		}
+	}
+}
+
+// acquire opens the database of the repository for an update cycle if it
+// was released. It waits for as long as other processes hold the database,
+// and returns false if ctx is cancelled first.
+func acquire(ctx context.Context, app *kingpin.Application, lock releaser) bool {
+	if lock == nil {
+		return ctx.Err() == nil
+	}
+	for ctx.Err() == nil {
+		err := lock.Acquire()
+		switch {
+		case err == nil:
+			return true
+		case err == bolt.ErrTimeout:
+			fmt.Printf("DATABASE: LOCKED: Waiting for other processes to close the database\n")
+		default:
+			app.Fatalf("failed to open bolt database: %v", err)
+		}
+	}
+	return false
+}
+
+// release closes the database of the repository between update cycles.
+func release(app *kingpin.Application, lock releaser) {
+	if lock == nil {
+		return
+	}
+	if err := lock.Release(); err != nil {
+		app.Fatalf("failed to close bolt database: %v", err)
+	}
+}
+
+// updateDrive updates the stream of a single drive and publishes its new
+// commits.
+func updateDrive(ctx context.Context, app *kingpin.Application, repo drivestream.Repository, driveService *drive.Service, pub *publish.Publisher, config updateConfig, driveData resource.Drive) {
+	prefix := fmt.Sprintf("DRIVE %s", driveData.ID)
+
+	fmt.Printf("%s: NAME: %s\n", prefix, driveData.Name)
+
+	drv := repo.Drive(driveData.ID)
+	exists, err := drv.Exists()
+	if err != nil {
+		app.Fatalf("failed to enumerate team drives: %v", err)
+	}
+	if !exists {
+		fmt.Printf("%s: INIT: Repository (%s)\n", prefix, repo.Type())
+	}
+
+	options := []drivestream.Option{
+		drivestream.WithLogger(os.Stdout),
+		drivestream.WithPageRetention(config.Retention),
+		drivestream.WithFullCollectionInterval(config.FullInterval),
+	}
+
+	var collector drivestream.Collector
+	if root, ok := driveapicollector.MyDriveRoot(driveData.ID); ok {
+		collector = driveapicollector.NewMyDrive(driveService, root)
+		options = append(options, drivestream.WithRootFolder(resource.ID(root)))
+	} else {
+		collector = driveapicollector.New(driveService, string(driveData.ID))
+	}
+	stream := drivestream.New(repo, driveData.ID, options...)
+	stream.Update(ctx, collector)
+
+	if pub != nil {
+		published, err := pub.Publish(ctx, driveData.ID)
+		if err != nil {
+			fmt.Printf("%s: PUBLISH: ERROR: %v\n", prefix, err)
+		}
+		if published > 0 {
+			fmt.Printf("%s: PUBLISH: %d commit(s)\n", prefix, published)
 		}
 	}
 }
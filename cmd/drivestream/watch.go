@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/scjalliance/drivestream/changewatch"
+	"github.com/scjalliance/drivestream/driveapicollector"
+	drive "google.golang.org/api/drive/v3"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// defaultWatchInterval is the polling interval used as a fallback when
+// notifications are received and no interval was requested.
+const defaultWatchInterval = time.Hour
+
+// watchConfig holds the settings for receiving push notifications.
+type watchConfig struct {
+	Address string // Public URL that notifications are sent to
+	Listen  string // Local address the receiver listens on
+	Cert    string // TLS certificate file
+	Key     string // TLS key file
+}
+
+// startWatching starts a receiver for push notifications and returns the
+// manager of its channels. It returns nil if no address was configured.
+//
+// The returned function stops the receiver and every channel. It must be
+// called before the drive service is discarded.
+func startWatching(ctx context.Context, app *kingpin.Application, driveService *drive.Service, config watchConfig) (*changewatch.Manager, func()) {
+	if config.Address == "" {
+		return nil, func() {}
+	}
+	if (config.Cert == "") != (config.Key == "") {
+		app.Fatalf("both a certificate and a key must be provided to receive notifications over TLS")
+	}
+
+	manager := changewatch.NewManager(driveapicollector.NewWatcher(driveService), config.Address, changewatch.WithLogger(os.Stdout))
+
+	server := &http.Server{
+		Addr:    config.Listen,
+		Handler: manager,
+	}
+
+	go func() {
+		var err error
+		if config.Cert != "" {
+			fmt.Printf("Receiving notifications for %s on %s (TLS)\n", config.Address, config.Listen)
+			err = server.ListenAndServeTLS(config.Cert, config.Key)
+		} else {
+			// Without a certificate the receiver is expected to sit behind
+			// a proxy that terminates TLS.
+			fmt.Printf("Receiving notifications for %s on %s\n", config.Address, config.Listen)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			app.Fatalf("failed to receive notifications: %v", err)
+		}
+	}()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		manager.Run(runCtx)
+	}()
+
+	return manager, func() {
+		cancel()
+		<-done
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		server.Shutdown(shutdownCtx)
+	}
+}
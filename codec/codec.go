@@ -0,0 +1,122 @@
+package codec
+
+import (
+	"sort"
+	"sync"
+)
+
+// Codec is a value encoding.
+type Codec interface {
+	// ID returns the identifier that is recorded with each value encoded
+	// by the codec.
+	ID() ID
+
+	// Name returns the name of the codec.
+	Name() string
+
+	// Marshal returns the encoding of v, without the codec ID.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into v. The codec ID has already been
+	// removed from data.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	registryMutex sync.RWMutex
+	registryByID  = make(map[ID]Codec)
+	registryNames = make(map[string]Codec)
+)
+
+func init() {
+	Register(JSON)
+	Register(ZstdJSON)
+}
+
+// Register makes a codec available for encoding and decoding values.
+// It panics if the codec's ID is invalid, or if its ID or name has already
+// been registered.
+func Register(c Codec) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	id, name := c.ID(), c.Name()
+	if !id.Valid() {
+		panic("codec: invalid ID for codec " + name)
+	}
+	if _, exists := registryByID[id]; exists {
+		panic("codec: ID registered twice for codec " + name)
+	}
+	if _, exists := registryNames[name]; exists {
+		panic("codec: codec registered twice: " + name)
+	}
+	registryByID[id] = c
+	registryNames[name] = c
+}
+
+// Lookup returns the registered codec with the given name.
+func Lookup(name string) (Codec, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	c, ok := registryNames[name]
+	if !ok {
+		return nil, UnknownName{Name: name}
+	}
+	return c, nil
+}
+
+// Names returns the names of all registered codecs in sorted order.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registryNames))
+	for name := range registryNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Encode encodes v with c and prefixes the result with the ID of c. Values
+// encoded with the JSON codec are not prefixed.
+func Encode(c Codec, v interface{}) ([]byte, error) {
+	data, err := c.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	id := c.ID()
+	if id == JSONID {
+		return data, nil
+	}
+	value := make([]byte, len(data)+1)
+	value[0] = byte(id)
+	copy(value[1:], data)
+	return value, nil
+}
+
+// Decode decodes a value produced by Encode into v, using whichever codec
+// the value was encoded with.
+func Decode(value []byte, v interface{}) error {
+	id := Identify(value)
+	if id == JSONID {
+		return JSON.Unmarshal(value, v)
+	}
+
+	registryMutex.RLock()
+	c, ok := registryByID[id]
+	registryMutex.RUnlock()
+	if !ok {
+		return UnknownID{ID: id}
+	}
+	return c.Unmarshal(value[1:], v)
+}
+
+// Identify returns the ID of the codec that produced value.
+func Identify(value []byte) ID {
+	if len(value) == 0 || !ID(value[0]).Valid() {
+		return JSONID
+	}
+	return ID(value[0])
+}
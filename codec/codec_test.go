@@ -0,0 +1,200 @@
+package codec_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// benchPageSize is the number of changes in each benchmarked page.
+const benchPageSize = 1000
+
+var (
+	mimeTypes = []string{
+		"application/vnd.google-apps.folder",
+		"application/vnd.google-apps.document",
+		"application/vnd.google-apps.spreadsheet",
+		"application/pdf",
+		"image/jpeg",
+		"text/plain",
+	}
+	extensions = []string{"", "", "", ".pdf", ".jpg", ".txt"}
+	words      = []string{"budget", "report", "draft", "final", "meeting", "notes", "invoice", "plan", "summary", "photo"}
+	users      = []resource.UserData{
+		{DisplayName: "Alice Example", PermissionID: "01234567890123456789", EmailAddress: "alice@example.com"},
+		{DisplayName: "Bob Example", PermissionID: "12345678901234567890", EmailAddress: "bob@example.com"},
+		{DisplayName: "Carol Example", PermissionID: "23456789012345678901", EmailAddress: "carol@example.com"},
+	}
+	epoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// benchValue is a kind of value that is benchmarked.
+type benchValue struct {
+	Name  string
+	Value interface{}        // The value to encode
+	New   func() interface{} // Returns a pointer to decode into
+}
+
+// benchValues returns the kinds of values stored by repositories that are
+// benchmarked: a single file version and a collection page of files.
+func benchValues() []benchValue {
+	changes := make([]resource.Change, benchPageSize)
+	for i := range changes {
+		changes[i] = fileChange(i)
+	}
+	return []benchValue{
+		{
+			Name:  "version",
+			Value: changes[benchPageSize/2].File.FileData,
+			New:   func() interface{} { return new(resource.FileData) },
+		},
+		{
+			Name: "page",
+			Value: page.Data{
+				Type:          page.FileList,
+				Collected:     epoch,
+				PageToken:     pageToken(0),
+				NextPageToken: pageToken(benchPageSize),
+				Changes:       changes,
+			},
+			New: func() interface{} { return new(page.Data) },
+		},
+	}
+}
+
+var registerOnce sync.Once
+
+// benchCodecs returns every registered codec. The encrypted codec is
+// registered with a fixed key the first time it is called.
+func benchCodecs(b *testing.B) []codec.Codec {
+	registerOnce.Do(func() {
+		keyring, err := codec.NewKeyring(codec.Key{ID: "bench", Secret: make([]byte, 32)})
+		if err != nil {
+			b.Fatal(err)
+		}
+		codec.Register(codec.NewEncrypted(codec.ZstdJSON, keyring))
+	})
+
+	var codecs []codec.Codec
+	for _, name := range codec.Names() {
+		c, err := codec.Lookup(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		codecs = append(codecs, c)
+	}
+	return codecs
+}
+
+// BenchmarkEncode measures the time taken by each codec to encode file
+// versions and collection pages, and reports the size of the encoded
+// values.
+func BenchmarkEncode(b *testing.B) {
+	values := benchValues()
+	for _, c := range benchCodecs(b) {
+		for _, v := range values {
+			b.Run(c.Name()+"/"+v.Name, func(b *testing.B) {
+				var size int
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					data, err := codec.Encode(c, v.Value)
+					if err != nil {
+						b.Fatal(err)
+					}
+					size = len(data)
+				}
+				b.ReportMetric(float64(size), "bytes/value")
+			})
+		}
+	}
+}
+
+// BenchmarkDecode measures the time taken by each codec to decode file
+// versions and collection pages.
+func BenchmarkDecode(b *testing.B) {
+	values := benchValues()
+	for _, c := range benchCodecs(b) {
+		for _, v := range values {
+			data, err := codec.Encode(c, v.Value)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(c.Name()+"/"+v.Name, func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := codec.Decode(data, v.New()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// fileID returns a file ID resembling those issued by Google Drive.
+func fileID(i int) resource.ID {
+	sum := md5.Sum([]byte(fmt.Sprintf("file-%d", i)))
+	return resource.ID("1" + hex.EncodeToString(sum[:])[:32])
+}
+
+// fileTime returns a timestamp for the file at index i.
+func fileTime(i int) time.Time {
+	return epoch.Add(time.Duration(i) * 37 * time.Second)
+}
+
+// pageToken returns a page token for the file at index i.
+func pageToken(i int) string {
+	return fmt.Sprintf("~!!~AI9FV7%032d", i)
+}
+
+// fileChange returns a change describing a synthetic file at index i.
+// Roughly one file in ten is a folder, and every file's parent is a
+// folder that precedes it.
+func fileChange(i int) resource.Change {
+	kind := i % len(mimeTypes)
+	if i%10 == 0 {
+		kind = 0
+	}
+	parent := "0AEXAMPLEDRIVEUk9PVA"
+	if i >= 10 {
+		parent = string(fileID((i / 10 / 10) * 10))
+	}
+
+	sum := md5.Sum([]byte(fmt.Sprintf("content-%d", i)))
+	user := users[i%len(users)]
+	name := fmt.Sprintf("%s %s %d%s", words[i%len(words)], words[(i/len(words))%len(words)], i, extensions[kind])
+
+	data := resource.FileData{
+		Name:         name,
+		MimeType:     mimeTypes[kind],
+		RevisionID:   fmt.Sprintf("0B%032x", i),
+		Created:      fileTime(i),
+		Modified:     fileTime(i).Add(time.Duration(i%1000) * time.Hour),
+		Parents:      []string{parent},
+		LastModifier: &user,
+	}
+	if kind > 2 {
+		data.OriginalName = name
+		data.MD5Checksum = hex.EncodeToString(sum[:])
+		data.Size = int64(i%100000) * 1024
+	}
+
+	return resource.Change{
+		Type:  resource.TypeFile,
+		Time:  fileTime(i),
+		Actor: &user,
+		File: resource.File{
+			ID:       fileID(i),
+			Version:  resource.Version(i%50 + 1),
+			FileData: data,
+		},
+	}
+}
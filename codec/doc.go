@@ -0,0 +1,11 @@
+// Package codec provides pluggable encodings for values stored within
+// drivestream repositories.
+//
+// Each encoded value records the codec that produced it, so values written
+// with different codecs can be mixed freely within a repository. Values
+// produced by the JSON codec are stored as plain JSON without any prefix,
+// which keeps data written before codecs were introduced readable. All
+// other codecs prefix their output with a single byte holding their ID.
+// Codec IDs are restricted to control characters that cannot begin a JSON
+// document, so the two forms are never ambiguous.
+package codec
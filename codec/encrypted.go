@@ -0,0 +1,59 @@
+package codec
+
+// EncryptedName is the name of the codecs returned by NewEncrypted.
+const EncryptedName = "aes-gcm"
+
+// NewEncrypted returns a codec that encrypts the values encoded by inner
+// with AES-GCM, using the primary key of keys. Each value records the ID
+// of the key that encrypted it, so values encrypted before the primary key
+// was rotated can be decrypted as long as their key remains in the
+// keyring.
+//
+// Values encrypted by the codec can only be decoded once it has been
+// registered. Only one encrypted codec can be registered at a time.
+//
+// The codec is a Hasher. Its keyed hashes are derived from the keys in
+// the keyring.
+func NewEncrypted(inner Codec, keys *Keyring) Codec {
+	return encryptedCodec{
+		inner: inner,
+		keys:  keys,
+	}
+}
+
+type encryptedCodec struct {
+	inner Codec
+	keys  *Keyring
+}
+
+func (c encryptedCodec) ID() ID {
+	return EncryptedID
+}
+
+func (c encryptedCodec) Name() string {
+	return EncryptedName
+}
+
+func (c encryptedCodec) Marshal(v interface{}) ([]byte, error) {
+	plaintext, err := Encode(c.inner, v)
+	if err != nil {
+		return nil, err
+	}
+	return c.keys.seal(plaintext)
+}
+
+func (c encryptedCodec) Hash(data []byte) []byte {
+	return c.keys.Hash(data)
+}
+
+func (c encryptedCodec) Hashes(data []byte) [][]byte {
+	return c.keys.Hashes(data)
+}
+
+func (c encryptedCodec) Unmarshal(data []byte, v interface{}) error {
+	plaintext, err := c.keys.open(data)
+	if err != nil {
+		return err
+	}
+	return Decode(plaintext, v)
+}
@@ -0,0 +1,50 @@
+package codec
+
+import "fmt"
+
+// UnknownID reports that a value was encoded with a codec that has not
+// been registered.
+type UnknownID struct {
+	ID ID
+}
+
+// Error returns a string representation of the error.
+func (e UnknownID) Error() string {
+	return fmt.Sprintf("drivestream: value was encoded with unknown codec %d", e.ID)
+}
+
+// UnknownName reports that a codec with the requested name has not been
+// registered.
+type UnknownName struct {
+	Name string
+}
+
+// Error returns a string representation of the error.
+func (e UnknownName) Error() string {
+	return fmt.Sprintf("drivestream: unknown codec \"%s\"", e.Name)
+}
+
+// KeyNotFound reports that a value was encrypted with a key that is not
+// present in the keyring.
+type KeyNotFound struct {
+	Key string
+}
+
+// Error returns a string representation of the error.
+func (e KeyNotFound) Error() string {
+	return fmt.Sprintf("drivestream: value was encrypted with key \"%s\", which is not present in the keyring", e.Key)
+}
+
+// DecryptionFailed reports that an encrypted value was malformed or could
+// not be authenticated with its key.
+type DecryptionFailed struct {
+	Key string
+}
+
+// Error returns a string representation of the error.
+func (e DecryptionFailed) Error() string {
+	if e.Key == "" {
+		return "drivestream: encrypted value is malformed"
+	}
+	return fmt.Sprintf("drivestream: value could not be decrypted with key \"%s\"", e.Key)
+}
@@ -0,0 +1,48 @@
+package codec
+
+import "crypto/sha256"
+
+// HashSize is the length of the hashes returned by Hash and Hashes.
+const HashSize = sha256.Size
+
+// A Hasher is a codec that can compute keyed hashes. Repositories use
+// keyed hashes in place of sensitive data that must be stored within
+// keys, which codecs never see.
+type Hasher interface {
+	// Hash returns a keyed hash of data, computed with the key that the
+	// codec uses for new values.
+	Hash(data []byte) []byte
+
+	// Hashes returns every keyed hash of data that the codec can compute,
+	// starting with the one returned by Hash.
+	Hashes(data []byte) [][]byte
+}
+
+// Hash returns the hash of data that should be stored in place of data
+// when values are encoded with c. If c is a Hasher the hash is keyed,
+// otherwise it is the SHA-256 hash of data.
+func Hash(c Codec, data []byte) []byte {
+	if h, ok := c.(Hasher); ok {
+		return h.Hash(data)
+	}
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// Hashes returns every hash that Hash might have returned for data with
+// any of the registered codecs, including hashes computed with keys that
+// are no longer primary.
+func Hashes(data []byte) [][]byte {
+	sum := sha256.Sum256(data)
+	hashes := [][]byte{sum[:]}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, c := range registryByID {
+		if h, ok := c.(Hasher); ok {
+			hashes = append(hashes, h.Hashes(data)...)
+		}
+	}
+	return hashes
+}
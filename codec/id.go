@@ -0,0 +1,28 @@
+package codec
+
+// ID is a codec identifier. It is recorded as the first byte of each value
+// encoded by a codec other than JSON.
+type ID byte
+
+// Codec IDs of the built-in codecs.
+const (
+	JSONID      ID = 0
+	ZstdJSONID  ID = 1
+	EncryptedID ID = 2
+)
+
+// Valid returns true if id can be recorded as a value prefix. The JSON
+// codec is always valid. Other IDs must be control characters that are
+// not JSON whitespace, so that they can never begin a JSON document.
+func (id ID) Valid() bool {
+	switch {
+	case id == JSONID:
+		return true
+	case id >= 0x20:
+		return false
+	case id == '\t', id == '\n', id == '\r':
+		return false
+	default:
+		return true
+	}
+}
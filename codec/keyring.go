@@ -0,0 +1,168 @@
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Key is an AES key. Keys are identified by an ID that is recorded with
+// each value they encrypt.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring holds the keys used by an encrypted codec. The first key is the
+// primary key, which encrypts new values. The remaining keys are only used
+// to decrypt values that were encrypted before the primary key was
+// rotated.
+type Keyring struct {
+	primary string
+	ids     []string
+	aeads   map[string]cipher.AEAD
+	macs    map[string][]byte
+}
+
+// macLabel is mixed with each secret to derive the key used for keyed
+// hashes, so that the AES key itself is never used for anything else.
+const macLabel = "drivestream keyed hash"
+
+// NewKeyring returns a keyring holding keys. The first key is the primary
+// key. Secrets must be 16, 24 or 32 bytes long, selecting AES-128, AES-192
+// or AES-256.
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("a keyring requires at least one key")
+	}
+	k := &Keyring{
+		primary: keys[0].ID,
+		aeads:   make(map[string]cipher.AEAD, len(keys)),
+		macs:    make(map[string][]byte, len(keys)),
+	}
+	for _, key := range keys {
+		if key.ID == "" || len(key.ID) > 255 {
+			return nil, fmt.Errorf("key ID \"%s\" must be between 1 and 255 bytes long", key.ID)
+		}
+		if _, exists := k.aeads[key.ID]; exists {
+			return nil, fmt.Errorf("key \"%s\" appears in the keyring more than once", key.ID)
+		}
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("key \"%s\": %v", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key \"%s\": %v", key.ID, err)
+		}
+		k.aeads[key.ID] = aead
+		k.ids = append(k.ids, key.ID)
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write([]byte(macLabel))
+		k.macs[key.ID] = mac.Sum(nil)
+	}
+	return k, nil
+}
+
+// ParseKeyring parses a keyring from text. Each key is written as an ID
+// and the base64 encoding of its secret, separated by a colon. Keys are
+// separated by newlines or commas. Blank lines and lines beginning with #
+// are ignored. The first key is the primary key.
+func ParseKeyring(text string) (*Keyring, error) {
+	var keys []Key
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			sep := strings.LastIndex(entry, ":")
+			if sep < 0 {
+				return nil, fmt.Errorf("key \"%s\" is not of the form id:base64", entry)
+			}
+			id := entry[:sep]
+			secret, err := base64.StdEncoding.DecodeString(entry[sep+1:])
+			if err != nil {
+				return nil, fmt.Errorf("key \"%s\": %v", id, err)
+			}
+			keys = append(keys, Key{ID: id, Secret: secret})
+		}
+	}
+	return NewKeyring(keys...)
+}
+
+// Primary returns the ID of the primary key.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Hash returns a keyed hash of data, computed with a key derived from the
+// primary key.
+func (k *Keyring) Hash(data []byte) []byte {
+	return k.hash(k.primary, data)
+}
+
+// Hashes returns the keyed hashes of data for every key in the keyring,
+// starting with the primary key.
+func (k *Keyring) Hashes(data []byte) [][]byte {
+	hashes := make([][]byte, 0, len(k.ids))
+	for _, id := range k.ids {
+		hashes = append(hashes, k.hash(id, data))
+	}
+	return hashes
+}
+
+// hash returns the HMAC-SHA256 of data with the hash key derived from the
+// key with the given ID.
+func (k *Keyring) hash(id string, data []byte) []byte {
+	mac := hmac.New(sha256.New, k.macs[id])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// seal encrypts plaintext with the primary key. The result holds the
+// length of the key ID, the key ID, the nonce and the ciphertext. The key
+// ID is authenticated along with the plaintext.
+func (k *Keyring) seal(plaintext []byte) ([]byte, error) {
+	aead := k.aeads[k.primary]
+	header := make([]byte, 1+len(k.primary)+aead.NonceSize())
+	header[0] = byte(len(k.primary))
+	copy(header[1:], k.primary)
+	nonce := header[1+len(k.primary):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plaintext, header[:1+len(k.primary)]), nil
+}
+
+// open decrypts a value produced by seal.
+func (k *Keyring) open(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(data) < 1+int(data[0]) {
+		return nil, DecryptionFailed{}
+	}
+	id := string(data[1 : 1+int(data[0])])
+	aead, ok := k.aeads[id]
+	if !ok {
+		return nil, KeyNotFound{Key: id}
+	}
+	start := 1 + len(id)
+	if len(data) < start+aead.NonceSize() {
+		return nil, DecryptionFailed{Key: id}
+	}
+	nonce := data[start : start+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, data[start+aead.NonceSize():], data[:start])
+	if err != nil {
+		return nil, DecryptionFailed{Key: id}
+	}
+	return plaintext, nil
+}
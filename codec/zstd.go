@@ -0,0 +1,61 @@
+package codec
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ZstdJSON is a codec that encodes values as zstd-compressed JSON.
+var ZstdJSON Codec = &zstdJSONCodec{}
+
+type zstdJSONCodec struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	err     error
+}
+
+func (c *zstdJSONCodec) ID() ID {
+	return ZstdJSONID
+}
+
+func (c *zstdJSONCodec) Name() string {
+	return "zstd"
+}
+
+func (c *zstdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *zstdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	if err := c.init(); err != nil {
+		return err
+	}
+	data, err := c.decoder.DecodeAll(data, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// init prepares the encoder and decoder, which are safe for concurrent use
+// by EncodeAll and DecodeAll.
+func (c *zstdJSONCodec) init() error {
+	c.once.Do(func() {
+		c.encoder, c.err = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		if c.err != nil {
+			return
+		}
+		c.decoder, c.err = zstd.NewReader(nil)
+	})
+	return c.err
+}
@@ -0,0 +1,22 @@
+package collection
+
+import "time"
+
+// PruneData summarizes the changes that were discarded when the pages of a
+// collection were pruned by a retention policy.
+//
+// Pruned pages retain their type, collection time and page tokens, so the
+// collection can still be followed and resumed from, but they no longer
+// hold any changes.
+type PruneData struct {
+	Time    time.Time `json:"time"`
+	Pages   int64     `json:"pages"`
+	Changes int64     `json:"changes"`
+	Bytes   int64     `json:"bytes"`
+}
+
+// IsZero returns true if d is the zero value, which indicates that the
+// collection has not been pruned.
+func (d PruneData) IsZero() bool {
+	return d.Time.IsZero()
+}
@@ -35,4 +35,15 @@ type Reference interface {
 
 	// Page returns a page reference. Equivalent to Pages().Ref(s).
 	Page(s page.SeqNum) page.Reference
+
+	// Prune discards the changes held by the pages of the collection and
+	// records a summary of what was discarded. The page tokens of each page
+	// are retained. If the collection has already been pruned its existing
+	// summary is returned.
+	Prune() (PruneData, error)
+
+	// Pruned returns a summary of the changes that were discarded when the
+	// collection was pruned. If the collection has not been pruned a zero
+	// value is returned.
+	Pruned() (PruneData, error)
 }
@@ -0,0 +1,25 @@
+package commit
+
+import "time"
+
+// DriftData summarizes the differences that were found when a full
+// collection was reconciled against the state of the drive at the commit
+// before it. Each difference is corrected by a synthetic file change
+// within the reconciling commit.
+type DriftData struct {
+	Time    time.Time `json:"time"`
+	Added   int64     `json:"added"`
+	Changed int64     `json:"changed"`
+	Removed int64     `json:"removed"`
+}
+
+// IsZero returns true if d is the zero value, which indicates that the
+// commit did not reconcile a full collection.
+func (d DriftData) IsZero() bool {
+	return d.Time.IsZero()
+}
+
+// Total returns the total number of differences that were found.
+func (d DriftData) Total() int64 {
+	return d.Added + d.Changed + d.Removed
+}
@@ -31,4 +31,13 @@ type Reference interface {
 
 	// Tree returns the map of tree changes for the commit.
 	Tree() TreeMap
+
+	// SetDrift records the drift that was corrected by the commit when it
+	// reconciled a full collection.
+	SetDrift(data DriftData) error
+
+	// Drift returns the drift that was corrected by the commit. If the
+	// commit did not reconcile a full collection the zero value is
+	// returned.
+	Drift() (DriftData, error)
 }
@@ -0,0 +1,22 @@
+package consumer
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+)
+
+// Data records the progress of a consumer through the commits of a drive.
+type Data struct {
+	// Commit is the last commit acknowledged by the consumer.
+	Commit commit.SeqNum
+
+	// Time is the time at which the commit was acknowledged.
+	Time time.Time
+}
+
+// Next returns the sequence number of the first commit that the consumer
+// hasn't acknowledged.
+func (data Data) Next() commit.SeqNum {
+	return data.Commit + 1
+}
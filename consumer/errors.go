@@ -0,0 +1,41 @@
+package consumer
+
+import (
+	"fmt"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// NotFound reports that a consumer could not be found within the
+// repository.
+type NotFound struct {
+	Drive resource.ID
+	Name  string
+}
+
+// Error returns a string representation of the error.
+func (e NotFound) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: consumer %s could not be found", e.Drive, e.Name)
+}
+
+// InvalidData reports that a consumer contains invalid or unparsable
+// data.
+type InvalidData struct {
+	Drive resource.ID
+	Name  string
+}
+
+// Error returns a string representation of the error.
+func (e InvalidData) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: consumer %s contains invalid data", e.Drive, e.Name)
+}
+
+// InvalidName reports that a consumer name is empty.
+type InvalidName struct {
+	Drive resource.ID
+}
+
+// Error returns a string representation of the error.
+func (e InvalidName) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: consumer names must not be empty", e.Drive)
+}
@@ -0,0 +1,10 @@
+package consumer
+
+// A Map is a map of named consumers that process the commits of a drive.
+type Map interface {
+	// List returns the names of all consumers within the map.
+	List() (names []string, err error)
+
+	// Ref returns a reference to the named consumer.
+	Ref(name string) Reference
+}
@@ -0,0 +1,18 @@
+package consumer
+
+import "github.com/scjalliance/drivestream/commit"
+
+// Next returns the sequence number of the first commit that the consumer
+// referenced by ref hasn't acknowledged. It returns zero for consumers
+// that haven't acknowledged a commit.
+func Next(ref Reference) (commit.SeqNum, error) {
+	data, err := ref.Data()
+	switch err.(type) {
+	case nil:
+		return data.Next(), nil
+	case NotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
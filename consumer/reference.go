@@ -0,0 +1,27 @@
+package consumer
+
+import "github.com/scjalliance/drivestream/resource"
+
+// Reference is a reference to a named consumer of the commits of a drive.
+type Reference interface {
+	// Drive returns the ID of the drive.
+	Drive() resource.ID
+
+	// Name returns the name of the consumer.
+	Name() string
+
+	// Exists returns true if the consumer has acknowledged a commit.
+	Exists() (bool, error)
+
+	// Data returns the progress of the consumer. If the consumer hasn't
+	// acknowledged a commit a NotFound error is returned.
+	Data() (Data, error)
+
+	// Set records the progress of the consumer, replacing any progress
+	// recorded before.
+	Set(data Data) error
+
+	// Delete removes the consumer, so that it starts again with the first
+	// commit.
+	Delete() error
+}
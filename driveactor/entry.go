@@ -0,0 +1,16 @@
+package driveactor
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Entry records a change made by an actor within a drive.
+type Entry struct {
+	Actor  resource.UserData
+	Time   time.Time
+	Commit commit.SeqNum
+	File   resource.ID
+}
@@ -0,0 +1,19 @@
+package driveactor
+
+import (
+	"fmt"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// InvalidData reports that an actor entry contains invalid or unparsable
+// data.
+type InvalidData struct {
+	Drive resource.ID
+	Email string
+}
+
+// Error returns a string representation of the error.
+func (e InvalidData) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: actor %s contains invalid data", e.Drive, e.Email)
+}
@@ -0,0 +1,14 @@
+package driveactor
+
+// A Map is a map of actors that have made changes within a drive.
+type Map interface {
+	// List returns the email addresses of all actors within the map.
+	List() (actors []string, err error)
+
+	// Ref returns a reference to the changes made by an actor.
+	Ref(email string) Reference
+
+	// Add adds the given entries to the map in bulk, indexed by the email
+	// address of each entry's actor.
+	Add(entries ...Entry) error
+}
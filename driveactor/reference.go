@@ -0,0 +1,21 @@
+package driveactor
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Reference is a reference to the changes made by an actor within a drive.
+type Reference interface {
+	// Drive returns the ID of the drive.
+	Drive() resource.ID
+
+	// Email returns the email address of the actor.
+	Email() string
+
+	// Read returns the entries for changes made by the actor at or after
+	// since and before until, in chronological order. A zero value for
+	// either time leaves that end of the range unbounded.
+	Read(since, until time.Time) (entries []Entry, err error)
+}
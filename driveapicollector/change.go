@@ -14,7 +14,14 @@ func MarshalChange(change *drive.Change) (resource.Change, error) {
 		return resource.Change{}, fmt.Errorf("invalid change time: %v", err)
 	}
 
-	switch change.Type {
+	// The deprecated type field is consulted when the change type isn't
+	// present.
+	changeType := change.ChangeType
+	if changeType == "" {
+		changeType = change.Type
+	}
+
+	switch changeType {
 	case "file":
 		if change.File == nil {
 			return resource.Change{
@@ -34,8 +41,30 @@ func MarshalChange(change *drive.Change) (resource.Change, error) {
 			Type:    resource.TypeFile,
 			Time:    changed,
 			Removed: change.Removed,
+			Actor:   record.LastModifier,
 			File:    record,
 		}, nil
+	case "drive":
+		if change.Drive == nil {
+			return resource.Change{
+				Type:    resource.TypeDrive,
+				Time:    changed,
+				Removed: change.Removed,
+				Drive: resource.Drive{
+					ID: resource.ID(change.DriveId),
+				},
+			}, nil
+		}
+		record, err := MarshalDrive(change.Drive)
+		if err != nil {
+			return resource.Change{}, err
+		}
+		return resource.Change{
+			Type:    resource.TypeDrive,
+			Time:    changed,
+			Removed: change.Removed,
+			Drive:   record,
+		}, nil
 	case "teamDrive":
 		if change.TeamDrive == nil {
 			return resource.Change{
@@ -47,7 +76,7 @@ func MarshalChange(change *drive.Change) (resource.Change, error) {
 				},
 			}, nil
 		}
-		record, err := MarshalDrive(change.TeamDrive)
+		record, err := MarshalTeamDrive(change.TeamDrive)
 		if err != nil {
 			return resource.Change{}, err
 		}
@@ -58,6 +87,6 @@ func MarshalChange(change *drive.Change) (resource.Change, error) {
 			Drive:   record,
 		}, nil
 	default:
-		return resource.Change{}, fmt.Errorf("unknown change type: \"%s\"", change.Type)
+		return resource.Change{}, fmt.Errorf("unknown change type: \"%s\"", changeType)
 	}
 }
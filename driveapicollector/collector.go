@@ -8,7 +8,7 @@ import (
 	drive "google.golang.org/api/drive/v3"
 )
 
-// A Collector is responsible for collecting team drive file data from a
+// A Collector is responsible for collecting shared drive file data from a
 // drive service.
 //
 // Collectors should be created by calling New.
@@ -17,10 +17,10 @@ type Collector struct {
 	service *drive.Service
 }
 
-// New returns a new collector for the requested team drive.
-func New(s *drive.Service, teamDriveID string) *Collector {
+// New returns a new collector for the requested shared drive.
+func New(s *drive.Service, driveID string) *Collector {
 	return &Collector{
-		id:      teamDriveID,
+		id:      driveID,
 		service: s,
 	}
 }
@@ -29,8 +29,8 @@ func New(s *drive.Service, teamDriveID string) *Collector {
 func (c *Collector) ChangeToken(ctx context.Context) (startToken string, err error) {
 	call := c.service.Changes.GetStartPageToken()
 	call.Context(ctx)
-	call.SupportsTeamDrives(true)
-	call.TeamDriveId(c.id)
+	call.SupportsAllDrives(true)
+	call.DriveId(c.id)
 
 	result, err := call.Do()
 	if err != nil {
@@ -47,7 +47,7 @@ func (c *Collector) Drive(ctx context.Context) (resource.Change, error) {
 		return resource.Change{}, err
 	}
 
-	call := c.service.Teamdrives.Get(c.id)
+	call := c.service.Drives.Get(c.id)
 	call.Context(ctx)
 	call.Fields("id,name,createdTime")
 
@@ -93,13 +93,13 @@ func (c *Collector) Files(ctx context.Context, token string, p []resource.Change
 
 		call := c.service.Files.List()
 		call.Context(ctx)
-		call.SupportsTeamDrives(true)
-		call.IncludeTeamDriveItems(true)
-		call.TeamDriveId(c.id)
-		call.Corpora("teamDrive")
+		call.SupportsAllDrives(true)
+		call.IncludeItemsFromAllDrives(true)
+		call.DriveId(c.id)
+		call.Corpora("drive")
 		call.Spaces("drive")
 		call.Fields("nextPageToken", "files(id,name,mimeType,description,parents,version,createdTime,modifiedTime,lastModifyingUser,originalFilename,md5Checksum,headRevisionId,size)")
-		call.PageSize(c.pageSize(bufferSize - n))
+		call.PageSize(pageSize(bufferSize - n))
 		if token != "" {
 			call.PageToken(token)
 		}
@@ -116,9 +116,10 @@ func (c *Collector) Files(ctx context.Context, token string, p []resource.Change
 
 			}
 			p[n] = resource.Change{
-				Type: resource.TypeFile,
-				Time: record.Modified,
-				File: record,
+				Type:  resource.TypeFile,
+				Time:  record.Modified,
+				Actor: record.LastModifier,
+				File:  record,
 			}
 			n++
 		}
@@ -161,14 +162,14 @@ func (c *Collector) Changes(ctx context.Context, token string, p []resource.Chan
 
 		call := c.service.Changes.List(nextToken)
 		call.Context(ctx)
-		call.SupportsTeamDrives(true)
-		call.IncludeTeamDriveItems(true)
+		call.SupportsAllDrives(true)
+		call.IncludeItemsFromAllDrives(true)
 		//call.IncludeCorpusRemovals(true)
 		call.IncludeRemoved(true)
-		call.TeamDriveId(c.id)
+		call.DriveId(c.id)
 		call.Spaces("drive")
-		call.Fields("nextPageToken", "newStartPageToken", "changes(fileId,removed,time,file(id,name,mimeType,description,parents,version,createdTime,modifiedTime,lastModifyingUser,originalFilename,md5Checksum,headRevisionId,size),type,teamDriveId,teamDrive(id,name,createdTime))")
-		call.PageSize(c.pageSize(bufferSize - n))
+		call.Fields("nextPageToken", "newStartPageToken", "changes(fileId,removed,time,file(id,name,mimeType,description,parents,version,createdTime,modifiedTime,lastModifyingUser,originalFilename,md5Checksum,headRevisionId,size),changeType,driveId,drive(id,name,createdTime))")
+		call.PageSize(pageSize(bufferSize - n))
 
 		result, err := call.Do()
 		if err != nil {
@@ -203,7 +204,9 @@ func (c *Collector) Changes(ctx context.Context, token string, p []resource.Chan
 	return n, nextToken, nextStartToken, nil
 }
 
-func (c *Collector) pageSize(bufferSize int) int64 {
+// pageSize returns the page size to request when bufferSize entries
+// remain to be collected.
+func pageSize(bufferSize int) int64 {
 	const maxPageSize = 1000
 
 	if bufferSize > maxPageSize {
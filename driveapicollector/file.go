@@ -33,6 +33,7 @@ func MarshalFile(file *drive.File) (resource.File, error) {
 			Created:      created,
 			Modified:     modified,
 			Parents:      file.Parents,
+			LastModifier: MarshalUser(file.LastModifyingUser),
 		},
 	}, nil
 }
@@ -0,0 +1,394 @@
+package driveapicollector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/scjalliance/drivestream/resource"
+	drive "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// MyDrivePrefix is the prefix of the synthetic drive IDs assigned to the
+// My Drive of each user. The remainder of the ID is the ID of the user's
+// root folder.
+const MyDrivePrefix = "mydrive-"
+
+// MyDriveID returns the synthetic drive ID of the My Drive with the given
+// root folder.
+func MyDriveID(rootFolderID string) resource.ID {
+	return resource.ID(MyDrivePrefix + rootFolderID)
+}
+
+// MyDriveRoot returns the root folder ID of driveID if it is the synthetic
+// ID of a My Drive.
+func MyDriveRoot(driveID resource.ID) (rootFolderID string, ok bool) {
+	if !strings.HasPrefix(string(driveID), MyDrivePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(driveID), MyDrivePrefix), true
+}
+
+// LookupMyDrive returns the My Drive of the user that the drive service
+// acts on behalf of. The drive is identified by a synthetic ID that is
+// derived from the ID of the user's root folder.
+func LookupMyDrive(ctx context.Context, s *drive.Service) (resource.Drive, error) {
+	aboutCall := s.About.Get()
+	aboutCall.Context(ctx)
+	aboutCall.Fields("user(displayName,emailAddress)")
+
+	about, err := aboutCall.Do()
+	if err != nil {
+		return resource.Drive{}, fmt.Errorf("about get call failed: %v", err)
+	}
+
+	call := s.Files.Get("root")
+	call.Context(ctx)
+	call.Fields("id,name,createdTime")
+
+	root, err := call.Do()
+	if err != nil {
+		return resource.Drive{}, fmt.Errorf("root folder get call failed: %v", err)
+	}
+
+	return marshalMyDrive(root, about.User)
+}
+
+// A MyDriveCollector is responsible for collecting the file data of a
+// user's My Drive from a drive service.
+//
+// The drive service must act on behalf of the user. The user's root
+// folder serves as the drive resource. Files are collected with their
+// parents as reported by the drive service, so files within the root
+// folder list the root folder's ID as a parent. Streams for the drive
+// should be created with drivestream.WithRootFolder so that those files
+// are recorded as children of the drive in the tree changes of commits.
+//
+// Files that are owned by other users but have been shared with the user
+// are only collected when they have been placed in a folder within the
+// My Drive. Files that are only present in the user's "Shared with me"
+// list are not part of the My Drive. Changes to files outside of the My
+// Drive are reported as removals, so that a file that leaves the My Drive
+// is recorded as removed. Streams drop the removals of files that were
+// never part of the drive.
+//
+// MyDriveCollectors should be created by calling NewMyDrive.
+type MyDriveCollector struct {
+	id      resource.ID
+	root    string
+	service *drive.Service
+}
+
+// NewMyDrive returns a new collector for the My Drive with the given root
+// folder. The root folder ID can be determined by calling LookupMyDrive.
+func NewMyDrive(s *drive.Service, rootFolderID string) *MyDriveCollector {
+	return &MyDriveCollector{
+		id:      MyDriveID(rootFolderID),
+		root:    rootFolderID,
+		service: s,
+	}
+}
+
+// ChangeToken returns the starting token for a new stream of changes.
+func (c *MyDriveCollector) ChangeToken(ctx context.Context) (startToken string, err error) {
+	call := c.service.Changes.GetStartPageToken()
+	call.Context(ctx)
+
+	result, err := call.Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get starting token for change list: %v", err)
+	}
+
+	return result.StartPageToken, nil
+}
+
+// Drive collects the current drive data, formatted in the same manner as
+// a change.
+func (c *MyDriveCollector) Drive(ctx context.Context) (resource.Change, error) {
+	if err := ctx.Err(); err != nil {
+		return resource.Change{}, err
+	}
+
+	aboutCall := c.service.About.Get()
+	aboutCall.Context(ctx)
+	aboutCall.Fields("user(displayName,emailAddress)")
+
+	about, err := aboutCall.Do()
+	if err != nil {
+		return resource.Change{}, fmt.Errorf("about get call failed: %v", err)
+	}
+
+	call := c.service.Files.Get(c.root)
+	call.Context(ctx)
+	call.Fields("id,name,createdTime")
+
+	root, err := call.Do()
+	if err != nil {
+		return resource.Change{}, fmt.Errorf("root folder get call failed: %v", err)
+	}
+
+	record, err := marshalMyDrive(root, about.User)
+	if err != nil {
+		return resource.Change{}, err
+	}
+	if record.ID != c.id {
+		return resource.Change{}, fmt.Errorf("the drive service acts on behalf of a user with a different root folder (%s)", root.Id)
+	}
+
+	return resource.Change{
+		Type:  resource.TypeDrive,
+		Time:  record.Created,
+		Drive: record,
+	}, nil
+}
+
+// Files collects a set of files into p, starting from the file identified
+// by token. It returns the number of files collected as n, and returns a
+// non-empty nextToken if there are additional files in the list yet to be
+// read.
+//
+// If the provided token is empty it will start at the first file within
+// the user's corpus.
+//
+// If the length of p is zero Files will panic.
+func (c *MyDriveCollector) Files(ctx context.Context, token string, p []resource.Change) (n int, nextToken string, err error) {
+	bufferSize := len(p)
+	if bufferSize == 0 {
+		if p == nil {
+			panic("unable to collect files into nil buffer")
+		}
+		panic("unable to collect files into empty buffer")
+	}
+
+	members := c.membership()
+
+	for n < bufferSize {
+		if err := ctx.Err(); err != nil {
+			return 0, token, err
+		}
+
+		call := c.service.Files.List()
+		call.Context(ctx)
+		call.Corpora("user")
+		call.Spaces("drive")
+		call.Fields("nextPageToken", "files(id,name,mimeType,description,parents,version,createdTime,modifiedTime,lastModifyingUser,originalFilename,md5Checksum,headRevisionId,size,ownedByMe)")
+		call.PageSize(pageSize(bufferSize - n))
+		if token != "" {
+			call.PageToken(token)
+		}
+
+		result, err := call.Do()
+		if err != nil {
+			return n, token, fmt.Errorf("file list call failed: %v", err)
+		}
+
+		for i, file := range result.Files {
+			member, err := members.contains(ctx, file)
+			if err != nil {
+				return n, token, fmt.Errorf("file list parsing failed: record %d: %v", i, err)
+			}
+			if !member {
+				continue
+			}
+			record, err := MarshalFile(file)
+			if err != nil {
+				return n, token, fmt.Errorf("file list parsing failed: record %d: %v", i, err)
+			}
+			p[n] = resource.Change{
+				Type:  resource.TypeFile,
+				Time:  record.Modified,
+				Actor: record.LastModifier,
+				File:  record,
+			}
+			n++
+		}
+
+		if result.NextPageToken != "" {
+			token = result.NextPageToken
+		} else {
+			return n, "", nil
+		}
+	}
+
+	return n, token, nil
+}
+
+// Changes collects a set of changes into p, up to len(p), starting from
+// the change identified by token.
+//
+// If len(p) is zero it will panic.
+//
+// The number of changes collected are returned in n.
+//
+// If there more changes to be collected in the current set, nextToken
+// will be non-empty. If there are no more changes in the current set
+// then nextStartToken will hold the starting token for the next set.
+func (c *MyDriveCollector) Changes(ctx context.Context, token string, p []resource.Change) (n int, nextToken string, nextStartToken string, err error) {
+	bufferSize := len(p)
+	if bufferSize == 0 {
+		if p == nil {
+			panic("unable to collect changes into nil buffer")
+		}
+		panic("unable to collect changes into empty buffer")
+	}
+
+	members := c.membership()
+	nextToken = token
+
+	for n < bufferSize {
+		if err := ctx.Err(); err != nil {
+			return n, nextToken, nextStartToken, err
+		}
+
+		call := c.service.Changes.List(nextToken)
+		call.Context(ctx)
+		call.RestrictToMyDrive(true)
+		call.IncludeRemoved(true)
+		call.Spaces("drive")
+		call.Fields("nextPageToken", "newStartPageToken", "changes(fileId,removed,time,file(id,name,mimeType,description,parents,version,createdTime,modifiedTime,lastModifyingUser,originalFilename,md5Checksum,headRevisionId,size,ownedByMe),changeType)")
+		call.PageSize(pageSize(bufferSize - n))
+
+		result, err := call.Do()
+		if err != nil {
+			return n, nextToken, nextStartToken, fmt.Errorf("failed to retrieve change list: %v", err)
+		}
+
+		// Make sure we didn't get back a bigger page than we asked for,
+		// because that would leave us with a nextToken that skips records
+		if len(result.Changes) > bufferSize-n {
+			return n, nextToken, nextStartToken, fmt.Errorf("drive changes API call returned a larger page than requested")
+		}
+
+		for i, change := range result.Changes {
+			if change.ChangeType == "drive" {
+				// Shared drive changes are not part of the My Drive.
+				continue
+			}
+			// A file outside of the My Drive is reported as removed, which
+			// records its departure if it was part of the My Drive. The
+			// stream drops the removal if it wasn't.
+			if !change.Removed && change.File != nil {
+				member, err := members.contains(ctx, change.File)
+				if err != nil {
+					return n, nextToken, nextStartToken, fmt.Errorf("change list parsing failed: record %d: %v", i, err)
+				}
+				if !member {
+					change.Removed = true
+					change.File = nil
+				}
+			}
+			record, err := MarshalChange(change)
+			if err != nil {
+				return n, nextToken, nextStartToken, fmt.Errorf("change list parsing failed: record %d: %v", i, err)
+			}
+			p[n] = record
+			n++
+		}
+
+		nextToken = result.NextPageToken
+		nextStartToken = result.NewStartPageToken
+		switch {
+		case nextToken == "" && nextStartToken == "":
+			return n, nextToken, nextStartToken, fmt.Errorf("failed to receive next page token")
+		case nextToken == "":
+			return n, nextToken, nextStartToken, nil
+		}
+	}
+
+	return n, nextToken, nextStartToken, nil
+}
+
+// membership returns a new membership cache for the collector's drive.
+func (c *MyDriveCollector) membership() *myDriveMembership {
+	return &myDriveMembership{
+		service: c.service,
+		root:    c.root,
+		folders: make(map[string]bool),
+	}
+}
+
+// myDriveMembership determines whether files are part of a My Drive.
+//
+// A file is part of the My Drive if the user owns it, or if one of its
+// parents is part of the My Drive. The membership of each folder that is
+// examined is cached, so a membership value should only be used for a
+// single listing.
+type myDriveMembership struct {
+	service *drive.Service
+	root    string
+	folders map[string]bool
+}
+
+// contains returns true if file is part of the My Drive.
+func (m *myDriveMembership) contains(ctx context.Context, file *drive.File) (bool, error) {
+	if file.OwnedByMe {
+		return true, nil
+	}
+	for _, parent := range file.Parents {
+		member, err := m.folder(ctx, parent)
+		if err != nil || member {
+			return member, err
+		}
+	}
+	return false, nil
+}
+
+// folder returns true if the folder with the given ID is part of the My
+// Drive.
+func (m *myDriveMembership) folder(ctx context.Context, id string) (bool, error) {
+	if id == m.root {
+		return true, nil
+	}
+	if member, ok := m.folders[id]; ok {
+		return member, nil
+	}
+
+	// Guard against cycles while the folder is examined.
+	m.folders[id] = false
+
+	call := m.service.Files.Get(id)
+	call.Context(ctx)
+	call.Fields("id,parents,ownedByMe")
+
+	folder, err := call.Do()
+	if err != nil {
+		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == http.StatusNotFound {
+			// The user can't see the folder, so it can't be part of the
+			// user's My Drive.
+			return false, nil
+		}
+		delete(m.folders, id)
+		return false, fmt.Errorf("folder get call failed: %v", err)
+	}
+
+	member, err := m.contains(ctx, folder)
+	if err != nil {
+		delete(m.folders, id)
+		return false, err
+	}
+	m.folders[id] = member
+	return member, nil
+}
+
+// marshalMyDrive marshals the root folder of a user as a drive resource.
+func marshalMyDrive(root *drive.File, user *drive.User) (resource.Drive, error) {
+	created, err := parseRFC3339(root.CreatedTime)
+	if err != nil {
+		return resource.Drive{}, fmt.Errorf("invalid creation time: %v", err)
+	}
+
+	name := root.Name
+	if user != nil && user.EmailAddress != "" {
+		name = fmt.Sprintf("%s (%s)", root.Name, user.EmailAddress)
+	}
+
+	return resource.Drive{
+		ID: MyDriveID(root.Id),
+		DriveData: resource.DriveData{
+			Name:    name,
+			Created: created,
+		},
+	}, nil
+}
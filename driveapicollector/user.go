@@ -0,0 +1,19 @@
+package driveapicollector
+
+import (
+	"github.com/scjalliance/drivestream/resource"
+	drive "google.golang.org/api/drive/v3"
+)
+
+// MarshalUser marshals the given user as a resource. It returns nil if
+// user is nil.
+func MarshalUser(user *drive.User) *resource.UserData {
+	if user == nil {
+		return nil
+	}
+	return &resource.UserData{
+		DisplayName:  user.DisplayName,
+		PermissionID: user.PermissionId,
+		EmailAddress: user.EmailAddress,
+	}
+}
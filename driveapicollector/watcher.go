@@ -0,0 +1,88 @@
+package driveapicollector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scjalliance/drivestream/changewatch"
+	drive "google.golang.org/api/drive/v3"
+)
+
+// A Watcher registers notification channels for shared drives and My
+// Drives with a drive service.
+//
+// Watchers should be created by calling NewWatcher.
+type Watcher struct {
+	service *drive.Service
+}
+
+var _ changewatch.Registrar = (*Watcher)(nil)
+
+// NewWatcher returns a new watcher for the drive service.
+func NewWatcher(s *drive.Service) *Watcher {
+	return &Watcher{service: s}
+}
+
+// Watch registers ch for changes within its shared drive, or within the
+// My Drive of the user if its drive has a synthetic My Drive ID, starting
+// with the current change token.
+func (w *Watcher) Watch(ctx context.Context, ch changewatch.Channel) (changewatch.Channel, error) {
+	_, myDrive := MyDriveRoot(ch.Drive)
+
+	tokenCall := w.service.Changes.GetStartPageToken()
+	tokenCall.Context(ctx)
+	if !myDrive {
+		tokenCall.SupportsAllDrives(true)
+		tokenCall.DriveId(string(ch.Drive))
+	}
+
+	token, err := tokenCall.Do()
+	if err != nil {
+		return changewatch.Channel{}, fmt.Errorf("failed to get starting token for change list: %v", err)
+	}
+
+	request := &drive.Channel{
+		Id:      ch.ID,
+		Type:    "web_hook",
+		Address: ch.Address,
+		Token:   ch.Token,
+	}
+	if !ch.Expiration.IsZero() {
+		request.Expiration = ch.Expiration.UnixNano() / int64(time.Millisecond)
+	}
+
+	call := w.service.Changes.Watch(token.StartPageToken, request)
+	call.Context(ctx)
+	if myDrive {
+		call.RestrictToMyDrive(true)
+	} else {
+		call.SupportsAllDrives(true)
+		call.IncludeItemsFromAllDrives(true)
+		call.DriveId(string(ch.Drive))
+	}
+
+	result, err := call.Do()
+	if err != nil {
+		return changewatch.Channel{}, fmt.Errorf("changes watch call failed: %v", err)
+	}
+
+	ch.ResourceID = result.ResourceId
+	if result.Expiration > 0 {
+		ch.Expiration = time.Unix(0, result.Expiration*int64(time.Millisecond))
+	}
+	return ch, nil
+}
+
+// Stop stops notifications for ch.
+func (w *Watcher) Stop(ctx context.Context, ch changewatch.Channel) error {
+	call := w.service.Channels.Stop(&drive.Channel{
+		Id:         ch.ID,
+		ResourceId: ch.ResourceID,
+	})
+	call.Context(ctx)
+	if err := call.Do(); err != nil {
+		return fmt.Errorf("channel stop call failed: %v", err)
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package drivestream
 import (
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
 	"github.com/scjalliance/drivestream/driveversion"
 	"github.com/scjalliance/drivestream/driveview"
 	"github.com/scjalliance/drivestream/resource"
@@ -40,6 +42,14 @@ type DriveReference interface {
 	// At returns a version reference of the drive at a particular commit.
 	At(seqNum commit.SeqNum) (driveversion.Reference, error)
 
+	// Actors returns the map of actors that have made changes within the
+	// drive.
+	Actors() driveactor.Map
+
+	// Consumers returns the map of named consumers that process the
+	// commits of the drive.
+	Consumers() consumer.Map
+
 	// Tree returns the tree map for the drive.
 	//Tree() drivetree.Map
 
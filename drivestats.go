@@ -12,8 +12,14 @@ type DriveStats struct {
 	TotalBytes      int64
 	Collections     int64
 	CollectionBytes int64
+	PrunedPages     int64
+	ReclaimedBytes  int64
 	Commits         int64
 	CommitBytes     int64
+	Reconciliations int64
+	DriftAdded      int64
+	DriftChanged    int64
+	DriftRemoved    int64
 	Versions        int64
 	VersionBytes    int64
 	ViewCommits     int64
@@ -26,7 +32,13 @@ func (ds DriveStats) Summary() []string {
 	var output []string
 	output = append(output, fmt.Sprintf("Drives: %d (%s)", ds.Count, bytefmt.ByteSize(uint64(ds.TotalBytes))))
 	output = append(output, fmt.Sprintf("  Collections: %d (%s)", ds.Collections, bytefmt.ByteSize(uint64(ds.CollectionBytes))))
+	if ds.PrunedPages > 0 {
+		output = append(output, fmt.Sprintf("  Pruned Pages: %d (%s reclaimed)", ds.PrunedPages, bytefmt.ByteSize(uint64(ds.ReclaimedBytes))))
+	}
 	output = append(output, fmt.Sprintf("  Commits: %d (%s)", ds.Commits, bytefmt.ByteSize(uint64(ds.CommitBytes))))
+	if ds.Reconciliations > 0 {
+		output = append(output, fmt.Sprintf("  Reconciliations: %d (drift: %d added, %d changed, %d removed)", ds.Reconciliations, ds.DriftAdded, ds.DriftChanged, ds.DriftRemoved))
+	}
 	output = append(output, fmt.Sprintf("  Drive Versions: %d (%s)", ds.Versions, bytefmt.ByteSize(uint64(ds.VersionBytes))))
 	output = append(output, fmt.Sprintf("  Drive View Commits: %d (%s)", ds.ViewCommits, bytefmt.ByteSize(uint64(ds.ViewBytes))))
 	output = append(output, fmt.Sprintf("Files: %d (%s)", ds.Files.Count, bytefmt.ByteSize(uint64(ds.Files.TotalBytes))))
@@ -0,0 +1,12 @@
+package driveview
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Entry records the version of a drive that is in view as of a commit.
+type Entry struct {
+	Commit  commit.SeqNum    `json:"commit"`
+	Version resource.Version `json:"version"`
+}
@@ -16,4 +16,7 @@ type Reference interface {
 
 	// Add adds version as a view of the file at the commit sequence number.
 	Add(seqNum commit.SeqNum, version resource.Version) error
+
+	// Entries returns every view of the drive in commit order.
+	Entries() ([]Entry, error)
 }
@@ -7,6 +7,9 @@ import (
 
 // FileMap is a map of drivestream files.
 type FileMap interface {
+	// List returns the list of files contained within the repository.
+	List() ([]resource.ID, error)
+
 	// Ref returns a file reference.
 	Ref(fileID resource.ID) FileReference
 
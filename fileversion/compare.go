@@ -0,0 +1,19 @@
+package fileversion
+
+import "github.com/scjalliance/drivestream/resource"
+
+// SameVersion returns true if a and b agree on the properties of a file
+// that belong to the version itself: its name, MIME type, checksum, size
+// and modification time.
+//
+// Other properties can legitimately differ between copies of the same
+// version. The last modifying user and the parents of a file are
+// reported as they are seen by the user that collected them, so they
+// differ when a file is collected through more than one drive.
+func SameVersion(a, b resource.FileData) bool {
+	return a.Name == b.Name &&
+		a.MimeType == b.MimeType &&
+		a.MD5Checksum == b.MD5Checksum &&
+		a.Size == b.Size &&
+		a.Modified.Equal(b.Modified)
+}
@@ -42,3 +42,17 @@ type InvalidData struct {
 func (e InvalidData) Error() string {
 	return fmt.Sprintf("drivestream: file %s: version %d contains invalid data", e.File, e.Version)
 }
+
+// Conflict reports that a file version was collected from a drive with
+// data that differs from the stored copy of the version in properties of
+// the version itself. See SameVersion.
+type Conflict struct {
+	File    resource.ID
+	Version resource.Version
+	Drive   resource.ID
+}
+
+// Error returns a string representation of the error.
+func (e Conflict) Error() string {
+	return fmt.Sprintf("drivestream: file %s: version %d was collected from drive %s with data that conflicts with the stored version", e.File, e.Version, e.Drive)
+}
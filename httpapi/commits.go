@@ -0,0 +1,188 @@
+package httpapi
+
+import (
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// commits returns a page of the drive's commits. The page starts at the
+// commit given by the from query parameter, or at the first commit at or
+// after the time given by the since query parameter.
+//
+// Commits are located by time with a binary search, which relies on
+// commit times increasing along with their sequence numbers.
+func (h *Handler) commits(driveID resource.ID, query url.Values) (list CommitList, err error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return CommitList{}, err
+	}
+	limit, err := parseLimit(query)
+	if err != nil {
+		return CommitList{}, err
+	}
+	next, err := drv.Commits().Next()
+	if err != nil {
+		return CommitList{}, err
+	}
+
+	var from commit.SeqNum
+	switch {
+	case query.Get("from") != "":
+		if from, err = parseSeqNum("from", query.Get("from")); err != nil {
+			return CommitList{}, err
+		}
+	case query.Get("since") != "":
+		value := query.Get("since")
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return CommitList{}, InvalidParameter{Name: "since", Value: value}
+		}
+		if from, err = searchCommits(drv, next, since); err != nil {
+			return CommitList{}, err
+		}
+	}
+
+	list.Commits = []Commit{}
+	if from >= next {
+		return list, nil
+	}
+	n := next - from
+	if n > commit.SeqNum(limit) {
+		n = commit.SeqNum(limit)
+	}
+	data := make([]commit.Data, n)
+	read, err := drv.Commits().Read(from, data)
+	if err != nil {
+		return CommitList{}, err
+	}
+	for i := range data[:read] {
+		c, err := makeCommit(drv.Commit(from+commit.SeqNum(i)), data[i])
+		if err != nil {
+			return CommitList{}, err
+		}
+		list.Commits = append(list.Commits, c)
+	}
+	if end := from + commit.SeqNum(read); end < next {
+		list.Next = &end
+	}
+	return list, nil
+}
+
+// commit returns a commit.
+func (h *Handler) commit(driveID resource.ID, seqNum commit.SeqNum) (Commit, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return Commit{}, err
+	}
+	ref := drv.Commit(seqNum)
+	data, err := ref.Data()
+	if err != nil {
+		return Commit{}, err
+	}
+	return makeCommit(ref, data)
+}
+
+// commitFiles returns the file changes of a commit, ordered by file ID.
+func (h *Handler) commitFiles(driveID resource.ID, seqNum commit.SeqNum) (FileChangeList, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return FileChangeList{}, err
+	}
+	changes, err := drv.Commit(seqNum).Files().Read()
+	if err != nil {
+		return FileChangeList{}, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+
+	list := FileChangeList{Commit: seqNum, Files: make([]FileChange, 0, len(changes))}
+	for _, change := range changes {
+		list.Files = append(list.Files, FileChange{
+			File:    change.File,
+			Version: change.Version,
+			Removed: change.Version < 0,
+		})
+	}
+	return list, nil
+}
+
+// commitTree returns the tree changes of a commit, ordered by parent and
+// child.
+func (h *Handler) commitTree(driveID resource.ID, seqNum commit.SeqNum) (TreeChangeList, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return TreeChangeList{}, err
+	}
+	ref := drv.Commit(seqNum)
+	if _, err := ref.Data(); err != nil {
+		return TreeChangeList{}, err
+	}
+	parents, err := ref.Tree().Parents()
+	if err != nil {
+		return TreeChangeList{}, err
+	}
+
+	list := TreeChangeList{Commit: seqNum, Tree: []TreeChange{}}
+	for _, parent := range parents {
+		changes, err := ref.Tree().Group(parent).Changes()
+		if err != nil {
+			return TreeChangeList{}, err
+		}
+		for _, change := range changes {
+			list.Tree = append(list.Tree, TreeChange{
+				Parent:  change.Parent,
+				Child:   change.Child,
+				Removed: change.Removed,
+			})
+		}
+	}
+	sort.Slice(list.Tree, func(i, j int) bool {
+		a, b := list.Tree[i], list.Tree[j]
+		if a.Parent != b.Parent {
+			return a.Parent < b.Parent
+		}
+		return a.Child < b.Child
+	})
+	return list, nil
+}
+
+// makeCommit returns a description of the commit with the given data.
+func makeCommit(ref commit.Reference, data commit.Data) (Commit, error) {
+	c := Commit{
+		SeqNum: ref.SeqNum(),
+		Time:   data.Time,
+		Source: data.Source,
+	}
+	r, err := commit.NewReader(ref)
+	if err != nil {
+		return Commit{}, err
+	}
+	if r.NextState() > 0 {
+		state, err := r.LastState()
+		if err != nil {
+			return Commit{}, err
+		}
+		c.Phase = state.Phase.String()
+	}
+	return c, nil
+}
+
+// searchCommits returns the sequence number of the first of the next
+// commits of drv at or after t. If there are no such commits next is
+// returned.
+func searchCommits(drv drivestream.DriveReference, next commit.SeqNum, t time.Time) (commit.SeqNum, error) {
+	var err error
+	i := sort.Search(int(next), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var data commit.Data
+		data, err = drv.Commit(commit.SeqNum(i)).Data()
+		return err != nil || !data.Time.Before(t)
+	})
+	return commit.SeqNum(i), err
+}
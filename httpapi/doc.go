@@ -0,0 +1,25 @@
+// Package httpapi provides a read-only HTTP/JSON API for a drivestream
+// repository.
+//
+// The API is versioned by the first element of each path. Version 1
+// offers the following resources, all of which respond to GET requests
+// with JSON documents:
+//
+//	/v1/drives
+//	/v1/drives/{drive}[?commit={seq}]
+//	/v1/drives/{drive}/commits[?from={seq}|since={time}][&limit={n}]
+//	/v1/drives/{drive}/commits/{seq}
+//	/v1/drives/{drive}/commits/{seq}/files
+//	/v1/drives/{drive}/commits/{seq}/tree
+//	/v1/drives/{drive}/commits/{seq}/folders/{folder}
+//	/v1/drives/{drive}/files/{file}/history[?from={seq}][&limit={n}]
+//	/v1/files/{file}/versions
+//	/v1/files/{file}/versions/{version}
+//
+// Lists that are paged include the position of the next page in the
+// "next" member of the response. Errors are reported with an appropriate
+// status code and a document with an "error" member.
+//
+// The API only reads from the repository. It has no access to Google
+// services.
+package httpapi
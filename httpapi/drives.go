@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// drives returns the list of drives within the repository.
+func (h *Handler) drives() (list DriveList, err error) {
+	ids, err := h.repo.Drives().List()
+	if err != nil {
+		return DriveList{}, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	list.Drives = make([]DriveSummary, 0, len(ids))
+	for _, id := range ids {
+		drv := h.repo.Drive(id)
+		next, err := drv.Commits().Next()
+		if err != nil {
+			return DriveList{}, err
+		}
+		summary := DriveSummary{ID: id, Commits: int64(next)}
+		if next > 0 {
+			d, err := h.driveAt(drv, next-1)
+			if err != nil {
+				return DriveList{}, err
+			}
+			summary.Name = d.Name
+		}
+		list.Drives = append(list.Drives, summary)
+	}
+	return list, nil
+}
+
+// drive returns a drive at the commit given by the commit query
+// parameter, or at its most recent commit.
+func (h *Handler) drive(driveID resource.ID, query url.Values) (Drive, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return Drive{}, err
+	}
+	var seqNum commit.SeqNum
+	if value := query.Get("commit"); value != "" {
+		if seqNum, err = parseSeqNum("commit", value); err != nil {
+			return Drive{}, err
+		}
+	} else {
+		next, err := drv.Commits().Next()
+		if err != nil {
+			return Drive{}, err
+		}
+		if next == 0 {
+			return Drive{}, commit.NotFound{Drive: driveID, Commit: 0}
+		}
+		seqNum = next - 1
+	}
+	return h.driveAt(drv, seqNum)
+}
+
+// driveRef returns a reference to the drive, or an error of type
+// DriveNotFound if it doesn't exist.
+func (h *Handler) driveRef(driveID resource.ID) (drivestream.DriveReference, error) {
+	drv := h.repo.Drive(driveID)
+	exists, err := drv.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, DriveNotFound{Drive: driveID}
+	}
+	return drv, nil
+}
+
+// driveAt returns the drive at the given commit, as recorded by the drive
+// version in view at the commit.
+//
+// Repositories that were collected before drive versions were recorded
+// have no view of the drive. For those the drive data collected by the
+// most recent full collection at or before the commit's source is
+// returned instead. If that data has been pruned an error of type
+// DriveDataNotFound is returned.
+func (h *Handler) driveAt(drv drivestream.DriveReference, seqNum commit.SeqNum) (Drive, error) {
+	data, err := drv.Commit(seqNum).Data()
+	if err != nil {
+		return Drive{}, err
+	}
+	d := Drive{ID: drv.DriveID(), Commit: seqNum}
+
+	ref, err := drv.At(seqNum)
+	switch err.(type) {
+	case nil:
+		if d.DriveData, err = ref.Data(); err != nil {
+			return Drive{}, err
+		}
+		version := ref.Version()
+		d.Version = &version
+		return d, nil
+	case driveview.NotFound:
+	default:
+		return Drive{}, err
+	}
+
+	for seq := data.Source.Collection; seq >= 0; seq-- {
+		col := drv.Collection(seq)
+		colData, err := col.Data()
+		if err != nil {
+			return Drive{}, err
+		}
+		if colData.Type != collection.Full {
+			continue
+		}
+		pg, err := col.Page(0).Data()
+		if err != nil {
+			return Drive{}, err
+		}
+		if pg.Type != page.DriveList || len(pg.Changes) == 0 {
+			continue // Pruned
+		}
+		d.DriveData = pg.Changes[len(pg.Changes)-1].DriveData
+		return d, nil
+	}
+	return Drive{}, DriveDataNotFound{Drive: drv.DriveID(), Commit: seqNum}
+}
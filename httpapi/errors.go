@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"fmt"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// DriveNotFound reports that a drive could not be found within the
+// repository.
+type DriveNotFound struct {
+	Drive resource.ID
+}
+
+// Error returns a string representation of the error.
+func (e DriveNotFound) Error() string {
+	return fmt.Sprintf("drivestream: drive %s could not be found", e.Drive)
+}
+
+// DriveDataNotFound reports that the repository holds no data for a drive
+// as of a commit. This happens when the drive has no recorded versions and
+// the collections that collected its data have been pruned.
+type DriveDataNotFound struct {
+	Drive  resource.ID
+	Commit commit.SeqNum
+}
+
+// Error returns a string representation of the error.
+func (e DriveDataNotFound) Error() string {
+	return fmt.Sprintf("drivestream: drive %s: no drive data is available as of commit %d", e.Drive, e.Commit)
+}
+
+// InvalidParameter reports that a request included a path element or
+// query parameter with an invalid value.
+type InvalidParameter struct {
+	Name  string
+	Value string
+}
+
+// Error returns a string representation of the error.
+func (e InvalidParameter) Error() string {
+	return fmt.Sprintf("drivestream: invalid %s \"%s\"", e.Name, e.Value)
+}
+
+// FileNotFound reports that a file could not be found within the
+// repository.
+type FileNotFound struct {
+	File resource.ID
+}
+
+// Error returns a string representation of the error.
+func (e FileNotFound) Error() string {
+	return fmt.Sprintf("drivestream: file %s could not be found", e.File)
+}
@@ -0,0 +1,190 @@
+package httpapi
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// folder returns the contents of a folder within a drive at a commit,
+// ordered by name.
+//
+// Candidates are found by replaying the tree changes of the folder up to
+// the commit. Only the commits that changed the folder are replayed, as
+// recorded by the drive's tree index.
+//
+// A file that moves to another folder doesn't record its removal from the
+// original one, so each candidate is then confirmed by the version of the
+// file in view at the commit. Every change to a file gives it a new
+// version and records it as a child of each of its parents, so a file
+// that is still in the folder has the same version in view as it had when
+// it was last recorded as a child of the folder. The parents within the
+// file's data aren't consulted, because they can differ from the parents
+// recorded in tree changes, as they do for the root folder of a My Drive.
+func (h *Handler) folder(driveID resource.ID, seqNum commit.SeqNum, folderID resource.ID) (Folder, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return Folder{}, err
+	}
+	if _, err := drv.Commit(seqNum).Data(); err != nil {
+		return Folder{}, err
+	}
+
+	commits, err := h.treeIndex(driveID).commits(drv, seqNum, folderID)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	// candidates holds the commit that last added each file to the folder.
+	candidates := make(map[resource.ID]commit.SeqNum)
+	for _, seq := range commits {
+		changes, err := drv.Commit(seq).Tree().Group(folderID).Changes()
+		switch err.(type) {
+		case nil:
+		case commit.TreeGroupNotFound:
+			continue
+		default:
+			return Folder{}, err
+		}
+		for _, change := range changes {
+			if change.Removed {
+				delete(candidates, change.Child)
+			} else {
+				candidates[change.Child] = seq
+			}
+		}
+	}
+
+	folder := Folder{ID: folderID, Commit: seqNum, Children: []File{}}
+	for child, added := range candidates {
+		view := h.repo.File(child).View(driveID)
+		ref, err := view.At(seqNum)
+		switch err.(type) {
+		case nil:
+		case fileview.NotFound:
+			continue
+		default:
+			return Folder{}, err
+		}
+		if ref.Version() < 0 {
+			continue
+		}
+		if added != seqNum {
+			addedRef, err := view.At(added)
+			switch err.(type) {
+			case nil:
+			case fileview.NotFound:
+				continue
+			default:
+				return Folder{}, err
+			}
+			if addedRef.Version() != ref.Version() {
+				continue
+			}
+		}
+		data, err := ref.Data()
+		if err != nil {
+			return Folder{}, err
+		}
+		folder.Children = append(folder.Children, File{ID: child, Version: ref.Version(), FileData: data})
+	}
+	sort.Slice(folder.Children, func(i, j int) bool {
+		a, b := folder.Children[i], folder.Children[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return folder, nil
+}
+
+// fileHistory returns the changes made to a file within a drive. The
+// history is found by examining the file changes of each commit, starting
+// with the commit given by the from query parameter. Each request examines
+// up to limit commits.
+func (h *Handler) fileHistory(driveID, fileID resource.ID, query url.Values) (FileHistory, error) {
+	drv, err := h.driveRef(driveID)
+	if err != nil {
+		return FileHistory{}, err
+	}
+	limit, err := parseLimit(query)
+	if err != nil {
+		return FileHistory{}, err
+	}
+	var from commit.SeqNum
+	if value := query.Get("from"); value != "" {
+		if from, err = parseSeqNum("from", value); err != nil {
+			return FileHistory{}, err
+		}
+	}
+	next, err := drv.Commits().Next()
+	if err != nil {
+		return FileHistory{}, err
+	}
+
+	history := FileHistory{ID: fileID, Drive: driveID, History: []FileEvent{}}
+	end := from + commit.SeqNum(limit)
+	if end > next {
+		end = next
+	}
+	for seq := from; seq < end; seq++ {
+		ref := drv.Commit(seq)
+		changes, err := ref.Files().Read()
+		if err != nil {
+			return FileHistory{}, err
+		}
+		for _, change := range changes {
+			if change.File != fileID {
+				continue
+			}
+			data, err := ref.Data()
+			if err != nil {
+				return FileHistory{}, err
+			}
+			history.History = append(history.History, FileEvent{
+				Commit:  seq,
+				Time:    data.Time,
+				Version: change.Version,
+				Removed: change.Version < 0,
+			})
+			break
+		}
+	}
+	if end < next {
+		history.Next = &end
+	}
+	return history, nil
+}
+
+// fileVersions returns the versions of a file in ascending order.
+func (h *Handler) fileVersions(fileID resource.ID) (FileVersionList, error) {
+	file := h.repo.File(fileID)
+	exists, err := file.Exists()
+	if err != nil {
+		return FileVersionList{}, err
+	}
+	if !exists {
+		return FileVersionList{}, FileNotFound{File: fileID}
+	}
+	versions, err := file.Versions().List()
+	if err != nil {
+		return FileVersionList{}, err
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	if versions == nil {
+		versions = []resource.Version{}
+	}
+	return FileVersionList{ID: fileID, Versions: versions}, nil
+}
+
+// fileVersion returns a version of a file.
+func (h *Handler) fileVersion(fileID resource.ID, version resource.Version) (File, error) {
+	data, err := h.repo.File(fileID).Version(version).Data()
+	if err != nil {
+		return File{}, err
+	}
+	return File{ID: fileID, Version: version, FileData: data}, nil
+}
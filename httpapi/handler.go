@@ -0,0 +1,180 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// Version is the version of the API served by a Handler. It is the first
+// element of the path of every resource.
+const Version = "v1"
+
+// Page sizes of paged lists, in commits.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Handler serves the HTTP/JSON API for a drivestream repository. It should
+// be created by calling New.
+type Handler struct {
+	repo drivestream.Repository
+
+	mutex sync.Mutex
+	trees map[resource.ID]*treeIndex
+}
+
+// New returns an HTTP handler that serves the API for repo.
+func New(repo drivestream.Repository) *Handler {
+	return &Handler{
+		repo:  repo,
+		trees: make(map[resource.ID]*treeIndex),
+	}
+}
+
+// ServeHTTP responds to an HTTP request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, Error{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != Version {
+		writeJSON(w, http.StatusNotFound, Error{Error: http.StatusText(http.StatusNotFound)})
+		return
+	}
+
+	v, ok, err := h.route(path[1:], r.URL.Query())
+	switch {
+	case !ok:
+		writeJSON(w, http.StatusNotFound, Error{Error: http.StatusText(http.StatusNotFound)})
+	case err != nil:
+		writeJSON(w, errorStatus(err), Error{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, v)
+	}
+}
+
+// route dispatches a request for the resource at path, which excludes the
+// API version. It returns false if the path doesn't identify a resource.
+func (h *Handler) route(path []string, query url.Values) (v interface{}, ok bool, err error) {
+	switch path[0] {
+	case "drives":
+		switch {
+		case len(path) == 1:
+			v, err = h.drives()
+		case len(path) == 2:
+			v, err = h.drive(resource.ID(path[1]), query)
+		case len(path) == 3 && path[2] == "commits":
+			v, err = h.commits(resource.ID(path[1]), query)
+		case len(path) >= 4 && len(path) <= 6 && path[2] == "commits":
+			driveID := resource.ID(path[1])
+			seqNum, err := parseSeqNum("commit", path[3])
+			if err != nil {
+				return nil, true, err
+			}
+			switch {
+			case len(path) == 4:
+				v, err = h.commit(driveID, seqNum)
+			case len(path) == 5 && path[4] == "files":
+				v, err = h.commitFiles(driveID, seqNum)
+			case len(path) == 5 && path[4] == "tree":
+				v, err = h.commitTree(driveID, seqNum)
+			case len(path) == 6 && path[4] == "folders":
+				v, err = h.folder(driveID, seqNum, resource.ID(path[5]))
+			default:
+				return nil, false, nil
+			}
+			return v, true, err
+		case len(path) == 5 && path[2] == "files" && path[4] == "history":
+			v, err = h.fileHistory(resource.ID(path[1]), resource.ID(path[3]), query)
+		default:
+			return nil, false, nil
+		}
+	case "files":
+		switch {
+		case len(path) == 3 && path[2] == "versions":
+			v, err = h.fileVersions(resource.ID(path[1]))
+		case len(path) == 4 && path[2] == "versions":
+			version, err := parseVersion(path[3])
+			if err != nil {
+				return nil, true, err
+			}
+			v, err = h.fileVersion(resource.ID(path[1]), version)
+			return v, true, err
+		default:
+			return nil, false, nil
+		}
+	default:
+		return nil, false, nil
+	}
+	return v, true, err
+}
+
+// errorStatus returns the HTTP status code for err.
+func errorStatus(err error) int {
+	switch err.(type) {
+	case InvalidParameter:
+		return http.StatusBadRequest
+	case DriveNotFound, DriveDataNotFound, FileNotFound, commit.NotFound, driveversion.NotFound, driveview.NotFound, fileversion.NotFound, fileview.NotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON writes v to w as a JSON document with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// parseSeqNum parses value as a commit sequence number.
+func parseSeqNum(name, value string) (commit.SeqNum, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, InvalidParameter{Name: name, Value: value}
+	}
+	return commit.SeqNum(n), nil
+}
+
+// parseVersion parses value as a file version.
+func parseVersion(value string) (resource.Version, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, InvalidParameter{Name: "version", Value: value}
+	}
+	return resource.Version(n), nil
+}
+
+// parseLimit parses the limit query parameter, which defaults to
+// DefaultLimit and can't exceed MaxLimit.
+func parseLimit(query url.Values) (int, error) {
+	value := query.Get("limit")
+	if value == "" {
+		return DefaultLimit, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, InvalidParameter{Name: "limit", Value: value}
+	}
+	if n > MaxLimit {
+		n = MaxLimit
+	}
+	return n, nil
+}
@@ -0,0 +1,307 @@
+package httpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/httpapi"
+	"github.com/scjalliance/drivestream/memrepo"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+const testDrive = resource.ID("drive")
+
+// collector is a drivestream collector that returns a fixed set of files
+// and batches of changes. The change token is the index of the next batch.
+type collector struct {
+	drive   resource.Drive
+	files   []resource.Change
+	changes [][]resource.Change
+}
+
+func (c *collector) ChangeToken(ctx context.Context) (string, error) {
+	return "0", nil
+}
+
+func (c *collector) Drive(ctx context.Context) (resource.Change, error) {
+	return resource.Change{Type: resource.TypeDrive, Drive: c.drive}, nil
+}
+
+func (c *collector) Files(ctx context.Context, token string, p []resource.Change) (int, string, error) {
+	return copy(p, c.files), "", nil
+}
+
+func (c *collector) Changes(ctx context.Context, token string, p []resource.Change) (int, string, string, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if i >= len(c.changes) {
+		return 0, "", token, nil
+	}
+	return copy(p, c.changes[i]), "", strconv.Itoa(i + 1), nil
+}
+
+// at returns a time a number of minutes after a fixed epoch.
+func at(minutes int) time.Time {
+	return time.Date(2020, 1, 1, 0, minutes, 0, 0, time.UTC)
+}
+
+// fileChange returns a change for a version of a file within parent.
+func fileChange(id resource.ID, version resource.Version, name string, parent resource.ID, minutes int) resource.Change {
+	return resource.Change{
+		Type: resource.TypeFile,
+		Time: at(minutes),
+		File: resource.File{
+			ID:      id,
+			Version: version,
+			FileData: resource.FileData{
+				Name:     name,
+				Parents:  []string{string(parent)},
+				Created:  at(0),
+				Modified: at(minutes),
+			},
+		},
+	}
+}
+
+// removal returns a change that removes a file from parent.
+func removal(id resource.ID, parent resource.ID, minutes int) resource.Change {
+	return resource.Change{
+		Type:    resource.TypeFile,
+		Time:    at(minutes),
+		Removed: true,
+		File: resource.File{
+			ID:       id,
+			FileData: resource.FileData{Parents: []string{string(parent)}},
+		},
+	}
+}
+
+// driveChange returns a change for the data of the test drive.
+func driveChange(name string) resource.Change {
+	return resource.Change{
+		Type:  resource.TypeDrive,
+		Drive: resource.Drive{ID: testDrive, DriveData: resource.DriveData{Name: name}},
+	}
+}
+
+// newServer returns a test server for a repository holding four commits
+// of the test drive:
+//
+//	0: a full collection that adds a.txt to the root of the drive
+//	1: a change that adds b.txt to the root of the drive
+//	2: a change that renames the drive
+//	3: a change that removes a.txt from the root of the drive
+func newServer(t *testing.T) (*memrepo.Repository, *httptest.Server) {
+	t.Helper()
+	repo := memrepo.New()
+	c := &collector{
+		drive: resource.Drive{ID: testDrive, DriveData: resource.DriveData{Name: "Team"}},
+		files: []resource.Change{fileChange("a", 1, "a.txt", testDrive, 1)},
+		changes: [][]resource.Change{
+			{},
+			{fileChange("b", 1, "b.txt", testDrive, 2)},
+			{driveChange("Renamed"), removal("a", testDrive, 3)},
+		},
+	}
+	s := drivestream.New(repo, testDrive)
+	for i := 0; i < 3; i++ {
+		if err := s.Update(context.Background(), c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo, httptest.NewServer(httpapi.New(repo))
+}
+
+// get requests path from srv and decodes the response into v. It fails the
+// test if the response doesn't have the given status.
+func get(t *testing.T, srv *httptest.Server, path string, status int, v interface{}) {
+	t.Helper()
+	resp, err := srv.Client().Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		t.Fatalf("GET %s: expected status %d, got %d", path, status, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("GET %s: unexpected content type %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+}
+
+func TestDrives(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	var list httpapi.DriveList
+	get(t, srv, "/v1/drives", http.StatusOK, &list)
+	if len(list.Drives) != 1 {
+		t.Fatalf("expected 1 drive, got %d", len(list.Drives))
+	}
+	if d := list.Drives[0]; d.ID != testDrive || d.Name != "Renamed" || d.Commits != 4 {
+		t.Fatalf("unexpected drive summary: %+v", d)
+	}
+}
+
+func TestDriveAtCommit(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		commit int
+		name   string
+	}{
+		{0, "Team"},
+		{1, "Team"},
+		{2, "Renamed"},
+		{3, "Renamed"},
+	} {
+		var drive httpapi.Drive
+		get(t, srv, "/v1/drives/drive?commit="+strconv.Itoa(tt.commit), http.StatusOK, &drive)
+		if drive.Name != tt.name || int(drive.Commit) != tt.commit {
+			t.Errorf("commit %d: expected drive %q, got %+v", tt.commit, tt.name, drive)
+		}
+		if drive.Version == nil {
+			t.Errorf("commit %d: drive version is missing", tt.commit)
+		}
+	}
+}
+
+func TestCommits(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	var first httpapi.CommitList
+	get(t, srv, "/v1/drives/drive/commits?limit=3", http.StatusOK, &first)
+	if len(first.Commits) != 3 || first.Next == nil || *first.Next != 3 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	var last httpapi.CommitList
+	get(t, srv, "/v1/drives/drive/commits?from=3", http.StatusOK, &last)
+	if len(last.Commits) != 1 || last.Commits[0].SeqNum != 3 || last.Next != nil {
+		t.Fatalf("unexpected last page: %+v", last)
+	}
+	var since httpapi.CommitList
+	get(t, srv, "/v1/drives/drive/commits?since=2020-01-01T00:02:30Z", http.StatusOK, &since)
+	if len(since.Commits) != 1 || since.Commits[0].SeqNum != 3 {
+		t.Fatalf("unexpected commits since 00:02:30: %+v", since)
+	}
+
+	var files httpapi.FileChangeList
+	get(t, srv, "/v1/drives/drive/commits/1/files", http.StatusOK, &files)
+	if len(files.Files) != 1 || files.Files[0].File != "b" || files.Files[0].Removed {
+		t.Fatalf("unexpected file changes: %+v", files)
+	}
+
+	var tree httpapi.TreeChangeList
+	get(t, srv, "/v1/drives/drive/commits/3/tree", http.StatusOK, &tree)
+	var removed bool
+	for _, change := range tree.Tree {
+		if change.Parent == testDrive && change.Child == "a" && change.Removed {
+			removed = true
+		}
+	}
+	if !removed {
+		t.Fatalf("expected a.txt to be removed from the drive: %+v", tree)
+	}
+}
+
+func TestFolder(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		commit   int
+		children []resource.ID
+	}{
+		{0, []resource.ID{"a"}},
+		{1, []resource.ID{"a", "b"}},
+		{2, []resource.ID{"a", "b"}},
+		{3, []resource.ID{"b"}},
+		{1, []resource.ID{"a", "b"}}, // Served from the tree index
+	} {
+		var folder httpapi.Folder
+		get(t, srv, "/v1/drives/drive/commits/"+strconv.Itoa(tt.commit)+"/folders/drive", http.StatusOK, &folder)
+		var children []resource.ID
+		for _, child := range folder.Children {
+			children = append(children, child.ID)
+		}
+		if len(children) != len(tt.children) {
+			t.Fatalf("commit %d: expected children %v, got %v", tt.commit, tt.children, children)
+		}
+		for i := range children {
+			if children[i] != tt.children[i] {
+				t.Fatalf("commit %d: expected children %v, got %v", tt.commit, tt.children, children)
+			}
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	var history httpapi.FileHistory
+	get(t, srv, "/v1/drives/drive/files/a/history", http.StatusOK, &history)
+	if len(history.History) != 2 || history.History[0].Commit != 0 || history.History[1].Commit != 3 || !history.History[1].Removed {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	var versions httpapi.FileVersionList
+	get(t, srv, "/v1/files/a/versions", http.StatusOK, &versions)
+	if len(versions.Versions) != 1 || versions.Versions[0] != 1 {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+
+	var file httpapi.File
+	get(t, srv, "/v1/files/a/versions/1", http.StatusOK, &file)
+	if file.Name != "a.txt" || file.Version != 1 {
+		t.Fatalf("unexpected file version: %+v", file)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, srv := newServer(t)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{"/v2/drives", http.StatusNotFound},
+		{"/v1/unknown", http.StatusNotFound},
+		{"/v1/drives/missing", http.StatusNotFound},
+		{"/v1/drives/drive?commit=9", http.StatusNotFound},
+		{"/v1/drives/drive/commits/9", http.StatusNotFound},
+		{"/v1/drives/drive/commits/x", http.StatusBadRequest},
+		{"/v1/drives/drive/commits?limit=0", http.StatusBadRequest},
+		{"/v1/files/a/versions/9", http.StatusNotFound},
+		{"/v1/files/a/versions/-1", http.StatusBadRequest},
+	} {
+		var e httpapi.Error
+		get(t, srv, tt.path, tt.status, &e)
+		if e.Error == "" {
+			t.Errorf("GET %s: error message is missing", tt.path)
+		}
+	}
+
+	resp, err := srv.Client().Post(srv.URL+"/v1/drives", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, HEAD" {
+		t.Fatalf("POST: unexpected status %d", resp.StatusCode)
+	}
+}
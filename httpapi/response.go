@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// DriveList is the response to a request for the list of drives.
+type DriveList struct {
+	Drives []DriveSummary `json:"drives"`
+}
+
+// DriveSummary identifies a drive within a drive list.
+type DriveSummary struct {
+	ID      resource.ID `json:"id"`
+	Name    string      `json:"name,omitempty"`
+	Commits int64       `json:"commits"`
+}
+
+// Drive is the response to a request for a drive at a commit. The version
+// is omitted when the drive's data was taken from its collections because
+// no drive version was recorded for the commit.
+type Drive struct {
+	ID      resource.ID       `json:"id"`
+	Commit  commit.SeqNum     `json:"commit"`
+	Version *resource.Version `json:"version,omitempty"`
+	resource.DriveData
+}
+
+// CommitList is the response to a request for a page of commits.
+type CommitList struct {
+	Commits []Commit       `json:"commits"`
+	Next    *commit.SeqNum `json:"next,omitempty"`
+}
+
+// Commit describes a commit.
+type Commit struct {
+	SeqNum commit.SeqNum `json:"seq"`
+	Time   time.Time     `json:"time"`
+	Source commit.Source `json:"source"`
+	Phase  string        `json:"phase,omitempty"`
+}
+
+// FileChangeList is the response to a request for the file changes of a
+// commit.
+type FileChangeList struct {
+	Commit commit.SeqNum `json:"commit"`
+	Files  []FileChange  `json:"files"`
+}
+
+// FileChange describes a file change within a commit.
+type FileChange struct {
+	File    resource.ID      `json:"file"`
+	Version resource.Version `json:"version"`
+	Removed bool             `json:"removed,omitempty"`
+}
+
+// TreeChangeList is the response to a request for the tree changes of a
+// commit.
+type TreeChangeList struct {
+	Commit commit.SeqNum `json:"commit"`
+	Tree   []TreeChange  `json:"tree"`
+}
+
+// TreeChange describes a tree change within a commit.
+type TreeChange struct {
+	Parent  resource.ID `json:"parent"`
+	Child   resource.ID `json:"child"`
+	Removed bool        `json:"removed,omitempty"`
+}
+
+// Folder is the response to a request for the contents of a folder at a
+// commit.
+type Folder struct {
+	ID       resource.ID   `json:"id"`
+	Commit   commit.SeqNum `json:"commit"`
+	Children []File        `json:"children"`
+}
+
+// File describes a version of a file.
+type File struct {
+	ID      resource.ID      `json:"id"`
+	Version resource.Version `json:"version"`
+	resource.FileData
+}
+
+// FileHistory is the response to a request for the history of a file
+// within a drive.
+type FileHistory struct {
+	ID      resource.ID    `json:"id"`
+	Drive   resource.ID    `json:"drive"`
+	History []FileEvent    `json:"history"`
+	Next    *commit.SeqNum `json:"next,omitempty"`
+}
+
+// FileEvent describes a change to a file within a commit.
+type FileEvent struct {
+	Commit  commit.SeqNum    `json:"commit"`
+	Time    time.Time        `json:"time"`
+	Version resource.Version `json:"version"`
+	Removed bool             `json:"removed,omitempty"`
+}
+
+// FileVersionList is the response to a request for the versions of a
+// file.
+type FileVersionList struct {
+	ID       resource.ID        `json:"id"`
+	Versions []resource.Version `json:"versions"`
+}
+
+// Error is the response to a request that failed.
+type Error struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"sync"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// treeIndex records the commits of a drive that changed the children of
+// each folder, so that folder requests don't examine every commit.
+//
+// Commits are only indexed once they have been finalized, after which
+// their tree changes never change. Each commit is indexed once, by the
+// first request that needs it.
+type treeIndex struct {
+	mutex   sync.Mutex
+	next    commit.SeqNum                   // The first commit that hasn't been indexed
+	folders map[resource.ID][]commit.SeqNum // Commits that changed each folder, in order
+}
+
+// treeIndex returns the tree index of a drive.
+func (h *Handler) treeIndex(driveID resource.ID) *treeIndex {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	index, ok := h.trees[driveID]
+	if !ok {
+		index = &treeIndex{folders: make(map[resource.ID][]commit.SeqNum)}
+		h.trees[driveID] = index
+	}
+	return index
+}
+
+// commits returns the commits at or before seqNum that may have changed
+// the children of folder, in order. Commits that haven't been finalized
+// can't be indexed yet, so they are always returned.
+func (index *treeIndex) commits(drv drivestream.DriveReference, seqNum commit.SeqNum, folder resource.ID) ([]commit.SeqNum, error) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	for index.next <= seqNum {
+		com := drv.Commit(index.next)
+		finalized, err := isFinalized(com)
+		if err != nil {
+			return nil, err
+		}
+		if !finalized {
+			break
+		}
+		parents, err := com.Tree().Parents()
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			index.folders[parent] = append(index.folders[parent], index.next)
+		}
+		index.next++
+	}
+
+	indexed := index.folders[folder]
+	commits := make([]commit.SeqNum, 0, len(indexed))
+	for _, seq := range indexed {
+		if seq > seqNum {
+			break
+		}
+		commits = append(commits, seq)
+	}
+	for seq := index.next; seq <= seqNum; seq++ {
+		commits = append(commits, seq)
+	}
+	return commits, nil
+}
+
+// isFinalized returns true if the last state of com is finalized.
+func isFinalized(com commit.Reference) (bool, error) {
+	next, err := com.States().Next()
+	if err != nil || next == 0 {
+		return false, err
+	}
+	state, err := com.State(next - 1).Data()
+	if err != nil {
+		return false, err
+	}
+	return state.Phase == commit.PhaseFinalized, nil
+}
@@ -0,0 +1,39 @@
+package memrepo
+
+import "github.com/scjalliance/drivestream"
+
+// Batch calls fn with a transaction that writes directly to the
+// repository. The repository is locked for the duration of the batch, so
+// batches are serialized with each other and with every other read and
+// write. fn must only access the repository through the transaction.
+//
+// Each write made within the batch is recorded in an undo log, which is
+// used to revert the writes if fn returns an error or panics.
+func (repo *Repository) Batch(fn func(tx drivestream.RepositoryTx) error) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	var log undoLog
+	tx := &Repository{
+		drives: repo.drives,
+		files:  repo.files,
+		undo:   &log,
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			log.rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	committed = true
+
+	// When repo is itself the transaction of a batch, the enclosing batch
+	// reverts this one if it fails.
+	repo.record(log.rollback)
+	return nil
+}
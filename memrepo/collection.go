@@ -1,6 +1,9 @@
 package memrepo
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/page"
 	"github.com/scjalliance/drivestream/resource"
@@ -28,6 +31,9 @@ func (ref Collection) SeqNum() collection.SeqNum {
 
 // Exists returns true if the collection exists.
 func (ref Collection) Exists() (exists bool, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return false, nil
@@ -39,6 +45,9 @@ func (ref Collection) Exists() (exists bool, err error) {
 // If a collection already exists with the sequence number an error will be
 // returned.
 func (ref Collection) Create(data collection.Data) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		drv = newDriveEntry()
@@ -47,6 +56,7 @@ func (ref Collection) Create(data collection.Data) error {
 	if ref.collection != expected {
 		return collection.OutOfOrder{Drive: ref.drive, Collection: ref.collection, Expected: expected}
 	}
+	ref.repo.saveDrive(ref.drive)
 	drv.Collections = append(drv.Collections, newCollectionEntry(data))
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -54,6 +64,9 @@ func (ref Collection) Create(data collection.Data) error {
 
 // Data returns information about the collection.
 func (ref Collection) Data() (data collection.Data, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return collection.Data{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -91,3 +104,73 @@ func (ref Collection) Pages() page.Sequence {
 func (ref Collection) Page(pageNum page.SeqNum) page.Reference {
 	return ref.Pages().Ref(pageNum)
 }
+
+// Prune discards the changes held by the pages of the collection and
+// records a summary of what was discarded. The page tokens of each page
+// are retained. If the collection has already been pruned its existing
+// summary is returned.
+//
+// The reclaimed bytes are measured by the size of each page's JSON
+// encoding.
+func (ref Collection) Prune() (data collection.PruneData, err error) {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return collection.PruneData{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+	}
+	if ref.collection >= collection.SeqNum(len(drv.Collections)) {
+		return collection.PruneData{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+	}
+	entry := &drv.Collections[ref.collection]
+	if !entry.Pruned.IsZero() {
+		return entry.Pruned, nil
+	}
+
+	// Pages are shared with callers and with the undo log of a batch, so
+	// they are copied rather than modified in place.
+	pages := append(entry.Pages[:0:0], entry.Pages...)
+	data.Time = time.Now().UTC()
+	for i := range pages {
+		pg := &pages[i]
+		if len(pg.Changes) == 0 {
+			continue
+		}
+		before, err := json.Marshal(pg)
+		if err != nil {
+			return collection.PruneData{}, err
+		}
+		changes := len(pg.Changes)
+		pg.Changes = nil
+		after, err := json.Marshal(pg)
+		if err != nil {
+			return collection.PruneData{}, err
+		}
+		data.Pages++
+		data.Changes += int64(changes)
+		data.Bytes += int64(len(before) - len(after))
+	}
+	ref.repo.saveCollection(ref.drive, ref.collection)
+	entry.Pages = pages
+	entry.Pruned = data
+	ref.repo.drives[ref.drive] = drv
+	return data, nil
+}
+
+// Pruned returns a summary of the changes that were discarded when the
+// collection was pruned. If the collection has not been pruned a zero
+// value is returned.
+func (ref Collection) Pruned() (data collection.PruneData, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return collection.PruneData{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+	}
+	if ref.collection >= collection.SeqNum(len(drv.Collections)) {
+		return collection.PruneData{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
+	}
+	return drv.Collections[ref.collection].Pruned, nil
+}
@@ -10,6 +10,7 @@ type CollectionEntry struct {
 	Data   collection.Data
 	States []collection.State
 	Pages  []page.Data
+	Pruned collection.PruneData
 }
 
 func newCollectionEntry(data collection.Data) CollectionEntry {
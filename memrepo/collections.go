@@ -15,6 +15,9 @@ type Collections struct {
 
 // Next returns the sequence number to use for the next collection.
 func (ref Collections) Next() (n collection.SeqNum, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -26,6 +29,9 @@ func (ref Collections) Next() (n collection.SeqNum, err error) {
 // starting at the given sequence number. Up to len(p) entries will
 // be returned in p. The number of entries is returned as n.
 func (ref Collections) Read(start collection.SeqNum, p []collection.Data) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, collection.NotFound{Drive: ref.drive, Collection: start}
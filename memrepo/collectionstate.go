@@ -23,6 +23,9 @@ func (ref CollectionState) StateNum() collection.StateNum {
 // Create creates the collection state with the given data. If a state already
 // exists with the state number an error will be returned.
 func (ref CollectionState) Create(data collection.State) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -34,6 +37,7 @@ func (ref CollectionState) Create(data collection.State) error {
 	if ref.state != expected {
 		return collection.StateOutOfOrder{Drive: ref.drive, Collection: ref.collection, State: ref.state, Expected: expected}
 	}
+	ref.repo.saveCollection(ref.drive, ref.collection)
 	drv.Collections[ref.collection].States = append(drv.Collections[ref.collection].States, data)
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -41,6 +45,9 @@ func (ref CollectionState) Create(data collection.State) error {
 
 // Data returns the collection state data.
 func (ref CollectionState) Data() (data collection.State, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return collection.State{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -17,6 +17,9 @@ type CollectionStates struct {
 
 // Next returns the state number to use for the next state.
 func (ref CollectionStates) Next() (n collection.StateNum, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -31,6 +34,9 @@ func (ref CollectionStates) Next() (n collection.StateNum, err error) {
 // Up to len(p) states will be returned in p. The number of states
 // returned is provided as n.
 func (ref CollectionStates) Read(start collection.StateNum, p []collection.State) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
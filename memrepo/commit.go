@@ -26,6 +26,9 @@ func (ref Commit) SeqNum() commit.SeqNum {
 
 // Exists returns true if the commit exists.
 func (ref Commit) Exists() (exists bool, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return false, nil
@@ -37,6 +40,9 @@ func (ref Commit) Exists() (exists bool, err error) {
 // If a commit already exists with the sequence number an error will be
 // returned.
 func (ref Commit) Create(data commit.Data) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		drv = newDriveEntry()
@@ -45,6 +51,7 @@ func (ref Commit) Create(data commit.Data) error {
 	if ref.commit != expected {
 		return commit.OutOfOrder{Drive: ref.drive, Commit: ref.commit, Expected: expected}
 	}
+	ref.repo.saveDrive(ref.drive)
 	drv.Commits = append(drv.Commits, newCommitEntry(data))
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -52,6 +59,9 @@ func (ref Commit) Create(data commit.Data) error {
 
 // Data returns information about the commit.
 func (ref Commit) Data() (data commit.Data, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return commit.Data{}, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -93,3 +103,37 @@ func (ref Commit) Tree() commit.TreeMap {
 		commit: ref.commit,
 	}
 }
+
+// SetDrift records the drift that was corrected by the commit when it
+// reconciled a full collection.
+func (ref Commit) SetDrift(data commit.DriftData) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+	}
+	if ref.commit >= commit.SeqNum(len(drv.Commits)) {
+		return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+	}
+	ref.repo.saveCommit(ref.drive, ref.commit)
+	drv.Commits[ref.commit].Drift = data
+	return nil
+}
+
+// Drift returns the drift that was corrected by the commit. If the commit
+// did not reconcile a full collection the zero value is returned.
+func (ref Commit) Drift() (data commit.DriftData, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return commit.DriftData{}, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+	}
+	if ref.commit >= commit.SeqNum(len(drv.Commits)) {
+		return commit.DriftData{}, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
+	}
+	return drv.Commits[ref.commit].Drift, nil
+}
@@ -11,6 +11,7 @@ type CommitEntry struct {
 	States []commit.State
 	Files  map[resource.ID]resource.Version
 	Tree   map[resource.ID]map[resource.ID]bool
+	Drift  commit.DriftData
 }
 
 func newCommitEntry(data commit.Data) CommitEntry {
@@ -17,6 +17,9 @@ type CommitFiles struct {
 // Read returns the set of file changes for the commit, in unspecified
 // order.
 func (ref CommitFiles) Read() (changes []commit.FileChange, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return nil, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -41,6 +44,9 @@ func (ref CommitFiles) Read() (changes []commit.FileChange, err error) {
 // If two or more changes conflict, the last change added takes
 // precedence.
 func (ref CommitFiles) Add(changes ...commit.FileChange) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -50,10 +56,12 @@ func (ref CommitFiles) Add(changes ...commit.FileChange) error {
 	}
 	files := drv.Commits[ref.commit].Files
 	if files == nil {
+		ref.repo.saveCommit(ref.drive, ref.commit)
 		files = make(map[resource.ID]resource.Version)
 		drv.Commits[ref.commit].Files = files
 	}
 	for _, change := range changes {
+		ref.repo.saveCommitFile(ref.drive, ref.commit, change.File)
 		files[change.File] = change.Version
 	}
 	return nil
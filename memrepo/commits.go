@@ -15,6 +15,9 @@ type Commits struct {
 
 // Next returns the sequence number to use for the next commit.
 func (ref Commits) Next() (n commit.SeqNum, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -26,6 +29,9 @@ func (ref Commits) Next() (n commit.SeqNum, err error) {
 // starting at the given sequence number. Up to len(p) entries will
 // be returned in p. The number of entries is returned as n.
 func (ref Commits) Read(start commit.SeqNum, p []commit.Data) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, commit.NotFound{Drive: ref.drive, Commit: start}
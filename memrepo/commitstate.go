@@ -23,6 +23,9 @@ func (ref CommitState) StateNum() commit.StateNum {
 // Create creates the commit state with the given data. If a state already
 // exists with the state number an error will be returned.
 func (ref CommitState) Create(data commit.State) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -34,6 +37,7 @@ func (ref CommitState) Create(data commit.State) error {
 	if ref.state != expected {
 		return commit.StateOutOfOrder{Drive: ref.drive, Commit: ref.commit, State: ref.state, Expected: expected}
 	}
+	ref.repo.saveCommit(ref.drive, ref.commit)
 	drv.Commits[ref.commit].States = append(drv.Commits[ref.commit].States, data)
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -41,6 +45,9 @@ func (ref CommitState) Create(data commit.State) error {
 
 // Data returns the commit state data.
 func (ref CommitState) Data() (data commit.State, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return commit.State{}, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
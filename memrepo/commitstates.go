@@ -17,6 +17,9 @@ type CommitStates struct {
 
 // Next returns the state number to use for the next state.
 func (ref CommitStates) Next() (n commit.StateNum, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -31,6 +34,9 @@ func (ref CommitStates) Next() (n commit.StateNum, err error) {
 // Up to len(p) states will be returned in p. The number of states
 // returned is provided as n.
 func (ref CommitStates) Read(start commit.StateNum, p []commit.State) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
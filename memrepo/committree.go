@@ -16,6 +16,9 @@ type CommitTree struct {
 
 // Parents returns a list of parent IDs contained within the map.
 func (ref CommitTree) Parents() (parents []resource.ID, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return nil, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -47,6 +50,9 @@ func (ref CommitTree) Group(parent resource.ID) commit.TreeGroup {
 // If two or more changes conflict, the last change added takes
 // precedence.
 func (ref CommitTree) Add(changes ...commit.TreeChange) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -56,10 +62,12 @@ func (ref CommitTree) Add(changes ...commit.TreeChange) error {
 	}
 	tree := drv.Commits[ref.commit].Tree
 	if tree == nil {
+		ref.repo.saveCommit(ref.drive, ref.commit)
 		tree = make(map[resource.ID]map[resource.ID]bool)
 		drv.Commits[ref.commit].Tree = tree
 	}
 	for _, change := range changes {
+		ref.repo.saveCommitTree(ref.drive, ref.commit, change.Parent, change.Child)
 		group := tree[change.Parent]
 		if group == nil {
 			group = make(map[resource.ID]bool)
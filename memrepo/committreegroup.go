@@ -23,6 +23,9 @@ func (ref CommitTreeGroup) Parent() resource.ID {
 
 // Changes returns the set of changes contained in the group.
 func (ref CommitTreeGroup) Changes() (changes []commit.TreeChange, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return nil, commit.NotFound{Drive: ref.drive, Commit: ref.commit}
@@ -0,0 +1,82 @@
+package memrepo
+
+import (
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ consumer.Reference = (*Consumer)(nil)
+
+// Consumer is a drivestream consumer reference for an in-memory
+// repository.
+type Consumer struct {
+	repo  *Repository
+	drive resource.ID
+	name  string
+}
+
+// Drive returns the ID of the drive.
+func (ref Consumer) Drive() resource.ID {
+	return ref.drive
+}
+
+// Name returns the name of the consumer.
+func (ref Consumer) Name() string {
+	return ref.name
+}
+
+// Exists returns true if the consumer has acknowledged a commit.
+func (ref Consumer) Exists() (exists bool, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	_, exists = ref.repo.drives[ref.drive].Consumers[ref.name]
+	return exists, nil
+}
+
+// Data returns the progress of the consumer. If the consumer hasn't
+// acknowledged a commit a NotFound error is returned.
+func (ref Consumer) Data() (data consumer.Data, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	data, ok := ref.repo.drives[ref.drive].Consumers[ref.name]
+	if !ok {
+		return consumer.Data{}, consumer.NotFound{Drive: ref.drive, Name: ref.name}
+	}
+	return data, nil
+}
+
+// Set records the progress of the consumer, replacing any progress
+// recorded before.
+func (ref Consumer) Set(data consumer.Data) error {
+	if ref.name == "" {
+		return consumer.InvalidName{Drive: ref.drive}
+	}
+
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		drv = newDriveEntry()
+	}
+	ref.repo.saveDrive(ref.drive)
+	ref.repo.saveConsumer(ref.drive, ref.name)
+	drv.Consumers[ref.name] = data
+	ref.repo.drives[ref.drive] = drv
+	return nil
+}
+
+// Delete removes the consumer, so that it starts again with the first
+// commit.
+func (ref Consumer) Delete() error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	if drv, ok := ref.repo.drives[ref.drive]; ok {
+		ref.repo.saveConsumer(ref.drive, ref.name)
+		delete(drv.Consumers, ref.name)
+	}
+	return nil
+}
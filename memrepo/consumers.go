@@ -0,0 +1,41 @@
+package memrepo
+
+import (
+	"sort"
+
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ consumer.Map = (*Consumers)(nil)
+
+// Consumers accesses a map of drive consumers in an in-memory repository.
+type Consumers struct {
+	repo  *Repository
+	drive resource.ID
+}
+
+// List returns the names of all consumers within the map.
+func (ref Consumers) List() (names []string, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return nil, nil
+	}
+	for name := range drv.Consumers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Ref returns a reference to the named consumer.
+func (ref Consumers) Ref(name string) consumer.Reference {
+	return Consumer{
+		repo:  ref.repo,
+		drive: ref.drive,
+		name:  name,
+	}
+}
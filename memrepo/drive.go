@@ -4,6 +4,8 @@ import (
 	"github.com/scjalliance/drivestream"
 	"github.com/scjalliance/drivestream/collection"
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
 	"github.com/scjalliance/drivestream/driveversion"
 	"github.com/scjalliance/drivestream/driveview"
 	"github.com/scjalliance/drivestream/resource"
@@ -24,6 +26,9 @@ func (ref Drive) DriveID() resource.ID {
 
 // Exists returns true if the drive exists.
 func (ref Drive) Exists() (exists bool, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	_, exists = ref.repo.drives[ref.drive]
 	return
 }
@@ -92,15 +97,60 @@ func (ref Drive) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
 	return ref.View().At(seqNum)
 }
 
+// Actors returns the map of actors that have made changes within the
+// drive.
+func (ref Drive) Actors() driveactor.Map {
+	return DriveActors{
+		repo:  ref.repo,
+		drive: ref.drive,
+	}
+}
+
+// Consumers returns the map of named consumers that process the commits
+// of the drive.
+func (ref Drive) Consumers() consumer.Map {
+	return Consumers{
+		repo:  ref.repo,
+		drive: ref.drive,
+	}
+}
+
 // Stats returns statistics about the drive.
 func (ref Drive) Stats() (stats drivestream.DriveStats, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return stats, nil
 	}
+	stats.Count = 1
 	stats.Collections = int64(len(drv.Collections))
+	for _, col := range drv.Collections {
+		stats.PrunedPages += col.Pruned.Pages
+		stats.ReclaimedBytes += col.Pruned.Bytes
+	}
 	stats.Commits = int64(len(drv.Commits))
+	for _, com := range drv.Commits {
+		if com.Drift.IsZero() {
+			continue
+		}
+		stats.Reconciliations++
+		stats.DriftAdded += com.Drift.Added
+		stats.DriftChanged += com.Drift.Changed
+		stats.DriftRemoved += com.Drift.Removed
+	}
 	stats.Versions = int64(len(drv.Versions))
 	stats.ViewCommits = int64(len(drv.View))
+	for _, file := range ref.repo.files {
+		view, ok := file.Views[ref.drive]
+		if !ok {
+			continue
+		}
+		stats.Files.Count++
+		stats.Files.Views++
+		stats.Files.ViewCommits += int64(len(view))
+		stats.Files.Versions += int64(len(file.Versions))
+	}
 	return stats, nil
 }
@@ -0,0 +1,61 @@
+package memrepo
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveactor.Reference = (*DriveActor)(nil)
+
+// DriveActor is a drivestream drive actor reference for an in-memory
+// repository.
+type DriveActor struct {
+	repo  *Repository
+	drive resource.ID
+	email string
+}
+
+// Drive returns the ID of the drive.
+func (ref DriveActor) Drive() resource.ID {
+	return ref.drive
+}
+
+// Email returns the email address of the actor.
+func (ref DriveActor) Email() string {
+	return ref.email
+}
+
+// Read returns the entries for changes made by the actor at or after
+// since and before until, in chronological order. A zero value for
+// either time leaves that end of the range unbounded.
+func (ref DriveActor) Read(since, until time.Time) (entries []driveactor.Entry, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return nil, nil
+	}
+	for _, entry := range drv.Actors[ref.email] {
+		if !since.IsZero() && entry.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !entry.Time.Before(until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.Before(entries[j].Time)
+		}
+		if entries[i].Commit != entries[j].Commit {
+			return entries[i].Commit < entries[j].Commit
+		}
+		return entries[i].File < entries[j].File
+	})
+	return entries, nil
+}
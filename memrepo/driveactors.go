@@ -0,0 +1,70 @@
+package memrepo
+
+import (
+	"sort"
+
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveactor.Map = (*DriveActors)(nil)
+
+// DriveActors accesses a map of drive actors in an in-memory repository.
+type DriveActors struct {
+	repo  *Repository
+	drive resource.ID
+}
+
+// List returns the email addresses of all actors within the map.
+func (ref DriveActors) List() (actors []string, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return nil, nil
+	}
+	for email := range drv.Actors {
+		actors = append(actors, email)
+	}
+	sort.Strings(actors)
+	return actors, nil
+}
+
+// Ref returns a reference to the changes made by an actor.
+func (ref DriveActors) Ref(email string) driveactor.Reference {
+	return DriveActor{
+		repo:  ref.repo,
+		drive: ref.drive,
+		email: email,
+	}
+}
+
+// Add adds the given entries to the map in bulk, indexed by the email
+// address of each entry's actor.
+//
+// Each actor holds a single entry for a given time, commit and file. An
+// entry that matches an existing one replaces it, so adding the same
+// entries again has no effect.
+func (ref DriveActors) Add(entries ...driveactor.Entry) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		drv = newDriveEntry()
+	}
+	ref.repo.saveDrive(ref.drive)
+	for _, entry := range entries {
+		email := entry.Actor.EmailAddress
+		ref.repo.saveActor(ref.drive, email, actorKey(entry))
+		actor, ok := drv.Actors[email]
+		if !ok {
+			actor = make(map[ActorKey]driveactor.Entry)
+			drv.Actors[email] = actor
+		}
+		actor[actorKey(entry)] = entry
+	}
+	ref.repo.drives[ref.drive] = drv
+	return nil
+}
@@ -2,6 +2,8 @@ package memrepo
 
 import (
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
 	"github.com/scjalliance/drivestream/resource"
 )
 
@@ -11,10 +13,31 @@ type DriveEntry struct {
 	Commits     []CommitEntry
 	Versions    []resource.DriveData
 	View        map[commit.SeqNum]resource.Version
+	Actors      map[string]map[ActorKey]driveactor.Entry
+	Consumers   map[string]consumer.Data
+}
+
+// ActorKey identifies an actor entry. Like the keys of actor entries in a
+// bolt repository, it is unique for a given time, commit and file.
+type ActorKey struct {
+	Time   int64
+	Commit commit.SeqNum
+	File   resource.ID
+}
+
+// actorKey returns the key of entry.
+func actorKey(entry driveactor.Entry) ActorKey {
+	return ActorKey{
+		Time:   entry.Time.UnixNano(),
+		Commit: entry.Commit,
+		File:   entry.File,
+	}
 }
 
 func newDriveEntry() DriveEntry {
 	return DriveEntry{
-		View: make(map[commit.SeqNum]resource.Version),
+		View:      make(map[commit.SeqNum]resource.Version),
+		Actors:    make(map[string]map[ActorKey]driveactor.Entry),
+		Consumers: make(map[string]consumer.Data),
 	}
 }
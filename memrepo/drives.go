@@ -16,6 +16,9 @@ type Drives struct {
 
 // List returns the list of drives contained within the repository.
 func (ref Drives) List() (ids []resource.ID, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	for id := range ref.repo.drives {
 		ids = append(ids, id)
 	}
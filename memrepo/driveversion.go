@@ -29,6 +29,9 @@ func (ref DriveVersion) Version() resource.Version {
 // and data. If a version already exists with the version number an
 // error will be returned.
 func (ref DriveVersion) Create(data resource.DriveData) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		drv = newDriveEntry()
@@ -37,6 +40,7 @@ func (ref DriveVersion) Create(data resource.DriveData) error {
 	if ref.version != expected {
 		return driveversion.OutOfOrder{Drive: ref.drive, Version: ref.version, Expected: expected}
 	}
+	ref.repo.saveDrive(ref.drive)
 	drv.Versions = append(drv.Versions, data)
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -44,6 +48,9 @@ func (ref DriveVersion) Create(data resource.DriveData) error {
 
 // Data returns the data of the drive version.
 func (ref DriveVersion) Data() (data resource.DriveData, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return resource.DriveData{}, driveversion.NotFound{Drive: ref.drive, Version: ref.version}
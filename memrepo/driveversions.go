@@ -15,6 +15,9 @@ type DriveVersions struct {
 
 // Next returns the next version number in the sequence.
 func (ref DriveVersions) Next() (n resource.Version, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -26,6 +29,9 @@ func (ref DriveVersions) Next() (n resource.Version, err error) {
 // given version number. Up to len(p) entries will be returned in p.
 // The number of entries is returned as n.
 func (ref DriveVersions) Read(start resource.Version, p []resource.DriveData) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, driveversion.NotFound{Drive: ref.drive, Version: start}
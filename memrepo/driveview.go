@@ -1,6 +1,8 @@
 package memrepo
 
 import (
+	"sort"
+
 	"github.com/scjalliance/drivestream/commit"
 	"github.com/scjalliance/drivestream/driveversion"
 	"github.com/scjalliance/drivestream/driveview"
@@ -25,6 +27,9 @@ func (ref DriveView) Drive() resource.ID {
 //
 // TODO: Consider returning the closest commit number as well as the version.
 func (ref DriveView) At(seqNum commit.SeqNum) (r driveversion.Reference, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return nil, driveview.NotFound{Drive: ref.drive, Commit: seqNum}
@@ -34,10 +39,13 @@ func (ref DriveView) At(seqNum commit.SeqNum) (r driveversion.Reference, err err
 		maxVersion resource.Version
 		found      bool
 	)
-	for seqNum, version := range drv.View {
-		if !found || seqNum > maxCommit {
+	for viewed, version := range drv.View {
+		if viewed > seqNum {
+			continue
+		}
+		if !found || viewed > maxCommit {
 			found = true
-			maxCommit = seqNum
+			maxCommit = viewed
 			maxVersion = version
 		}
 	}
@@ -53,11 +61,34 @@ func (ref DriveView) At(seqNum commit.SeqNum) (r driveversion.Reference, err err
 
 // Add adds version as a view of the drive at the commit sequence number.
 func (ref DriveView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		drv = newDriveEntry()
 	}
+	ref.repo.saveDrive(ref.drive)
+	ref.repo.saveDriveView(ref.drive, seqNum)
 	drv.View[seqNum] = version
 	ref.repo.drives[ref.drive] = drv
 	return nil
 }
+
+// Entries returns every view of the drive in commit order.
+func (ref DriveView) Entries() (entries []driveview.Entry, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
+	drv, ok := ref.repo.drives[ref.drive]
+	if !ok {
+		return nil, nil
+	}
+	for seqNum, version := range drv.View {
+		entries = append(entries, driveview.Entry{Commit: seqNum, Version: version})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Commit < entries[j].Commit
+	})
+	return entries, nil
+}
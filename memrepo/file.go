@@ -22,6 +22,9 @@ func (ref File) FileID() resource.ID {
 
 // Exists returns true if the file exists.
 func (ref File) Exists() (exists bool, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	_, exists = ref.repo.files[ref.file]
 	return exists, nil
 }
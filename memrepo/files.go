@@ -16,6 +16,9 @@ type Files struct {
 
 // List returns the list of files contained within the repository.
 func (ref Files) List() (ids []resource.ID, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	for id := range ref.repo.files {
 		ids = append(ids, id)
 	}
@@ -31,21 +34,48 @@ func (ref Files) Ref(id resource.ID) drivestream.FileReference {
 }
 
 // AddVersions adds file versions to the file map in bulk.
+//
+// Versions that already exist are skipped, and keep the data they were
+// first stored with. The same version can be collected with different
+// data through different drives.
 func (ref Files) AddVersions(fileVersions ...resource.File) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	type versionKey struct {
+		file    resource.ID
+		version resource.Version
+	}
+	added := make(map[versionKey]resource.FileData, len(fileVersions))
 	for _, fileVersion := range fileVersions {
-		file, ok := ref.repo.files[fileVersion.ID]
+		key := versionKey{file: fileVersion.ID, version: fileVersion.Version}
+		if _, ok := added[key]; ok {
+			continue
+		}
+		if _, ok := ref.repo.files[fileVersion.ID].Versions[fileVersion.Version]; ok {
+			continue
+		}
+		added[key] = fileVersion.FileData
+	}
+	for key, data := range added {
+		ref.repo.saveFileVersion(key.file, key.version)
+		file, ok := ref.repo.files[key.file]
 		if !ok {
 			file = newFileEntry()
 		}
-		file.Versions[fileVersion.Version] = fileVersion.FileData
-		ref.repo.files[fileVersion.ID] = file
+		file.Versions[key.version] = data
+		ref.repo.files[key.file] = file
 	}
 	return nil
 }
 
 // AddViewData adds view data to the file map in bulk.
 func (ref Files) AddViewData(entries ...fileview.Data) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	for _, entry := range entries {
+		ref.repo.saveFileView(entry.File, entry.Drive, entry.Commit)
 		file, ok := ref.repo.files[entry.File]
 		if !ok {
 			file = newFileEntry()
@@ -29,6 +29,10 @@ func (ref FileVersion) Version() resource.Version {
 // and data. If a version already exists with the version number an
 // error will be returned.
 func (ref FileVersion) Create(data resource.FileData) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	ref.repo.saveFileVersion(ref.file, ref.version)
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		file = newFileEntry()
@@ -40,6 +44,9 @@ func (ref FileVersion) Create(data resource.FileData) error {
 
 // Data returns the data of the file version.
 func (ref FileVersion) Data() (data resource.FileData, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		return resource.FileData{}, fileversion.NotFound{File: ref.file, Version: ref.version}
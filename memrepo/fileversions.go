@@ -15,6 +15,9 @@ type FileVersions struct {
 
 // List returns a list of version numbers for the file.
 func (ref FileVersions) List() (v []resource.Version, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		return nil, nil
@@ -31,6 +31,9 @@ func (ref FileView) Drive() resource.ID {
 //
 // TODO: Consider returning the closest commit number as well as the version.
 func (ref FileView) At(seqNum commit.SeqNum) (r fileversion.Reference, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		return nil, fileview.NotFound{File: ref.file, Drive: ref.drive, Commit: seqNum}
@@ -44,10 +47,13 @@ func (ref FileView) At(seqNum commit.SeqNum) (r fileversion.Reference, err error
 		maxVersion resource.Version
 		found      bool
 	)
-	for seqNum, version := range view {
-		if !found || seqNum > maxCommit {
+	for viewSeqNum, version := range view {
+		if viewSeqNum > seqNum {
+			continue
+		}
+		if !found || viewSeqNum > maxCommit {
 			found = true
-			maxCommit = seqNum
+			maxCommit = viewSeqNum
 			maxVersion = version
 		}
 	}
@@ -66,6 +72,10 @@ func (ref FileView) At(seqNum commit.SeqNum) (r fileversion.Reference, err error
 
 // Add adds version as a view of the file at the commit sequence number.
 func (ref FileView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
+	ref.repo.saveFileView(ref.file, ref.drive, seqNum)
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		file = newFileEntry()
@@ -17,6 +17,9 @@ type FileViews struct {
 
 // List returns a list of drives with a view of the file.
 func (ref FileViews) List() (drives []resource.ID, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	file, ok := ref.repo.files[ref.file]
 	if !ok {
 		return nil, nil
@@ -23,6 +23,9 @@ func (ref Page) SeqNum() page.SeqNum {
 
 // Create creates the page with the given data.
 func (ref Page) Create(data page.Data) error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -34,6 +37,7 @@ func (ref Page) Create(data page.Data) error {
 	if ref.page != expected {
 		return collection.PageOutOfOrder{Drive: ref.drive, Collection: ref.collection, Page: ref.page, Expected: expected}
 	}
+	ref.repo.saveCollection(ref.drive, ref.collection)
 	drv.Collections[ref.collection].Pages = append(drv.Collections[ref.collection].Pages, data)
 	ref.repo.drives[ref.drive] = drv
 	return nil
@@ -41,6 +45,9 @@ func (ref Page) Create(data page.Data) error {
 
 // Data returns the page data.
 func (ref Page) Data() (data page.Data, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return page.Data{}, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
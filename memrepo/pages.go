@@ -18,6 +18,9 @@ type Pages struct {
 // Next returns the sequence number to use for the next page of the
 // collection.
 func (ref Pages) Next() (n page.SeqNum, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, nil
@@ -30,6 +33,9 @@ func (ref Pages) Next() (n page.SeqNum, err error) {
 
 // Read reads the requested pages from a collection.
 func (ref Pages) Read(start page.SeqNum, p []page.Data) (n int, err error) {
+	ref.repo.mutex.RLock()
+	defer ref.repo.mutex.RUnlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return 0, collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -60,6 +66,9 @@ func (ref Pages) Ref(pageNum page.SeqNum) page.Reference {
 
 // Clear removes all pages affiliated with a collection.
 func (ref Pages) Clear() error {
+	ref.repo.mutex.Lock()
+	defer ref.repo.mutex.Unlock()
+
 	drv, ok := ref.repo.drives[ref.drive]
 	if !ok {
 		return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
@@ -67,6 +76,7 @@ func (ref Pages) Clear() error {
 	if ref.collection >= collection.SeqNum(len(drv.Collections)) {
 		return collection.NotFound{Drive: ref.drive, Collection: ref.collection}
 	}
+	ref.repo.saveCollection(ref.drive, ref.collection)
 	drv.Collections[ref.collection].Pages = nil
 	return nil
 }
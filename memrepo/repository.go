@@ -1,6 +1,8 @@
 package memrepo
 
 import (
+	"sync"
+
 	"github.com/scjalliance/drivestream"
 	"github.com/scjalliance/drivestream/resource"
 )
@@ -9,9 +11,13 @@ var _ drivestream.Repository = (*Repository)(nil)
 
 // Repository is an in-memory implementation of a drive stream repository.
 // It should be created by calling New.
+//
+// Repository is safe for concurrent use.
 type Repository struct {
+	mutex  sync.RWMutex
 	drives map[resource.ID]DriveEntry
 	files  map[resource.ID]FileEntry
+	undo   *undoLog // Non-nil for the transaction of a batch
 	//files       map[resource.ID]File
 	//trees       map[resource.ID]Tree
 	//content     map[filetree.Hash]filetree.Content
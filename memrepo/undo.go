@@ -0,0 +1,211 @@
+package memrepo
+
+import (
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// undoLog holds functions that revert the writes made within a batch, in
+// the order the writes were made.
+type undoLog []func()
+
+// rollback reverts the writes recorded in log, most recent first.
+func (log undoLog) rollback() {
+	for i := len(log) - 1; i >= 0; i-- {
+		log[i]()
+	}
+}
+
+// record adds fn to the undo log of the batch that repo belongs to. It
+// does nothing if repo doesn't belong to a batch.
+//
+// Each write records how to revert itself before it is made, while the
+// batch holds the repository's lock, so that reverting the writes in
+// reverse order restores the repository to its state before the batch.
+func (repo *Repository) record(fn func()) {
+	if repo.undo != nil {
+		*repo.undo = append(*repo.undo, fn)
+	}
+}
+
+// saveDrive records the drive entry itself, which restores the length of
+// its slices and removes it if it didn't exist. Elements and maps that are
+// modified in place must be recorded separately.
+func (repo *Repository) saveDrive(driveID resource.ID) {
+	if repo.undo == nil {
+		return
+	}
+	drv, ok := repo.drives[driveID]
+	repo.record(func() {
+		if ok {
+			repo.drives[driveID] = drv
+		} else {
+			delete(repo.drives, driveID)
+		}
+	})
+}
+
+// saveCollection records a collection entry, which restores its state and
+// page slices and its prune data. Pages must never be modified in place.
+func (repo *Repository) saveCollection(driveID resource.ID, seqNum collection.SeqNum) {
+	if repo.undo == nil {
+		return
+	}
+	entry := repo.drives[driveID].Collections[seqNum]
+	repo.record(func() {
+		repo.drives[driveID].Collections[seqNum] = entry
+	})
+}
+
+// saveCommit records a commit entry, which restores its state slice and
+// drift data, and removes its file and tree maps if they didn't exist.
+func (repo *Repository) saveCommit(driveID resource.ID, seqNum commit.SeqNum) {
+	if repo.undo == nil {
+		return
+	}
+	entry := repo.drives[driveID].Commits[seqNum]
+	repo.record(func() {
+		repo.drives[driveID].Commits[seqNum] = entry
+	})
+}
+
+// saveCommitFile records the version of a file within a commit.
+func (repo *Repository) saveCommitFile(driveID resource.ID, seqNum commit.SeqNum, fileID resource.ID) {
+	if repo.undo == nil {
+		return
+	}
+	version, ok := repo.drives[driveID].Commits[seqNum].Files[fileID]
+	repo.record(func() {
+		files := repo.drives[driveID].Commits[seqNum].Files
+		if ok {
+			files[fileID] = version
+		} else {
+			delete(files, fileID)
+		}
+	})
+}
+
+// saveCommitTree records a tree change within a commit.
+func (repo *Repository) saveCommitTree(driveID resource.ID, seqNum commit.SeqNum, parent, child resource.ID) {
+	if repo.undo == nil {
+		return
+	}
+	group, groupOK := repo.drives[driveID].Commits[seqNum].Tree[parent]
+	removed, ok := group[child]
+	repo.record(func() {
+		tree := repo.drives[driveID].Commits[seqNum].Tree
+		switch {
+		case !groupOK:
+			delete(tree, parent)
+		case ok:
+			tree[parent][child] = removed
+		default:
+			delete(tree[parent], child)
+		}
+	})
+}
+
+// saveDriveView records the drive version viewed by a commit.
+func (repo *Repository) saveDriveView(driveID resource.ID, seqNum commit.SeqNum) {
+	if repo.undo == nil {
+		return
+	}
+	version, ok := repo.drives[driveID].View[seqNum]
+	repo.record(func() {
+		view := repo.drives[driveID].View
+		if ok {
+			view[seqNum] = version
+		} else {
+			delete(view, seqNum)
+		}
+	})
+}
+
+// saveActor records an actor entry.
+func (repo *Repository) saveActor(driveID resource.ID, email string, key ActorKey) {
+	if repo.undo == nil {
+		return
+	}
+	actor, actorOK := repo.drives[driveID].Actors[email]
+	entry, ok := actor[key]
+	repo.record(func() {
+		actors := repo.drives[driveID].Actors
+		switch {
+		case !actorOK:
+			delete(actors, email)
+		case ok:
+			actors[email][key] = entry
+		default:
+			delete(actors[email], key)
+		}
+	})
+}
+
+// saveConsumer records a consumer's data.
+func (repo *Repository) saveConsumer(driveID resource.ID, name string) {
+	if repo.undo == nil {
+		return
+	}
+	var (
+		data consumer.Data
+		ok   bool
+	)
+	if drv, exists := repo.drives[driveID]; exists {
+		data, ok = drv.Consumers[name]
+	}
+	repo.record(func() {
+		drv, exists := repo.drives[driveID]
+		switch {
+		case !exists:
+		case ok:
+			drv.Consumers[name] = data
+		default:
+			delete(drv.Consumers, name)
+		}
+	})
+}
+
+// saveFileVersion records a file version, and removes the file entry if
+// it didn't exist.
+func (repo *Repository) saveFileVersion(fileID resource.ID, version resource.Version) {
+	if repo.undo == nil {
+		return
+	}
+	file, fileOK := repo.files[fileID]
+	data, ok := file.Versions[version]
+	repo.record(func() {
+		switch {
+		case !fileOK:
+			delete(repo.files, fileID)
+		case ok:
+			repo.files[fileID].Versions[version] = data
+		default:
+			delete(repo.files[fileID].Versions, version)
+		}
+	})
+}
+
+// saveFileView records the file version viewed by a commit within a drive,
+// and removes the file entry if it didn't exist.
+func (repo *Repository) saveFileView(fileID, driveID resource.ID, seqNum commit.SeqNum) {
+	if repo.undo == nil {
+		return
+	}
+	file, fileOK := repo.files[fileID]
+	view, viewOK := file.Views[driveID]
+	version, ok := view[seqNum]
+	repo.record(func() {
+		switch {
+		case !fileOK:
+			delete(repo.files, fileID)
+		case !viewOK:
+			delete(repo.files[fileID].Views, driveID)
+		case ok:
+			repo.files[fileID].Views[driveID][seqNum] = version
+		default:
+			delete(repo.files[fileID].Views[driveID], seqNum)
+		}
+	})
+}
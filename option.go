@@ -1,6 +1,11 @@
 package drivestream
 
-import "io"
+import (
+	"io"
+	"time"
+
+	"github.com/scjalliance/drivestream/resource"
+)
 
 // Option is a configuration option for a stream.
 type Option func(*Stream)
@@ -11,3 +16,46 @@ func WithLogger(w io.Writer) Option {
 		s.stdout = w
 	}
 }
+
+// WithPageRetention causes the stream to prune the pages of finalized
+// collections once they are older than d and every change within them has
+// been committed. Pruned pages keep their page tokens but discard their
+// changes. A retention of zero disables pruning.
+func WithPageRetention(d time.Duration) Option {
+	return func(s *Stream) {
+		s.retention = d
+	}
+}
+
+// WithFullCollectionInterval causes the stream to perform a full
+// collection of the drive once the most recent full collection is older
+// than d. When a full collection is committed, the files it observed are
+// reconciled against the state of the drive at the previous commit and any
+// drift is corrected. An interval of zero disables periodic full
+// collections.
+func WithFullCollectionInterval(d time.Duration) Option {
+	return func(s *Stream) {
+		s.fullInterval = d
+	}
+}
+
+// WithRootFolder causes the stream to record files within the folder with
+// the given ID as children of the drive itself in the tree changes of its
+// commits. It is needed for drives whose root is an ordinary folder, such
+// as the My Drive of a user. The data of each file version keeps the
+// parents reported by the collector.
+func WithRootFolder(folderID resource.ID) Option {
+	return func(s *Stream) {
+		s.rootFolder = folderID
+	}
+}
+
+// WithPollInterval causes the subscriptions of the stream to poll the
+// repository for commits finalized by other streams every d. Commits
+// finalized by the stream itself are always delivered immediately. An
+// interval of zero disables polling.
+func WithPollInterval(d time.Duration) Option {
+	return func(s *Stream) {
+		s.pollInterval = d
+	}
+}
@@ -0,0 +1,16 @@
+// Package publish emits the finalized commits of drivestream drives as
+// JSON events.
+//
+// A Publisher reads each finalized commit of a drive that it hasn't yet
+// published and hands an Event describing it to each of its sinks. The
+// Webhook sink posts events to an HTTP endpoint and signs them with an
+// HMAC. The Spool sink appends events to rotating NDJSON files for log
+// shippers to collect.
+//
+// The progress of a publisher is recorded within the repository as a
+// consumer checkpoint, which is only advanced once every sink has accepted
+// an event. Delivery is therefore at-least-once: an event can be delivered
+// more than once when a sink fails or the process is interrupted, but is
+// never skipped. Receivers can use the ID of each event to discard
+// duplicates.
+package publish
@@ -0,0 +1,20 @@
+package publish
+
+import "fmt"
+
+// DeliveryFailed reports that a webhook did not accept an event.
+type DeliveryFailed struct {
+	URL      string
+	Event    string
+	Attempts int
+	Status   int   // The last HTTP status returned, or zero
+	Err      error // The last transport error, or nil
+}
+
+// Error returns a string representation of the error.
+func (e DeliveryFailed) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("drivestream: event %s could not be delivered to %s after %d attempt(s): %v", e.Event, e.URL, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("drivestream: event %s could not be delivered to %s after %d attempt(s): status %d", e.Event, e.URL, e.Attempts, e.Status)
+}
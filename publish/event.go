@@ -0,0 +1,46 @@
+package publish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// EventType is the type of every event emitted for a commit.
+const EventType = "commit"
+
+// Event describes a finalized commit.
+type Event struct {
+	ID     string            `json:"id"`
+	Type   string            `json:"type"`
+	Drive  resource.ID       `json:"drive"`
+	Commit commit.SeqNum     `json:"commit"`
+	Time   time.Time         `json:"time"`
+	Source commit.Source     `json:"source"`
+	Drift  *commit.DriftData `json:"drift,omitempty"`
+	Files  []FileChange      `json:"files"`
+	Tree   []TreeChange      `json:"tree"`
+}
+
+// FileChange describes a file change within a commit. The data of the
+// file version is included unless the file was removed.
+type FileChange struct {
+	File    resource.ID        `json:"file"`
+	Version resource.Version   `json:"version"`
+	Removed bool               `json:"removed,omitempty"`
+	Data    *resource.FileData `json:"data,omitempty"`
+}
+
+// TreeChange describes a tree change within a commit.
+type TreeChange struct {
+	Parent  resource.ID `json:"parent"`
+	Child   resource.ID `json:"child"`
+	Removed bool        `json:"removed,omitempty"`
+}
+
+// EventID returns the ID of the event for a commit within a drive.
+func EventID(driveID resource.ID, seqNum commit.SeqNum) string {
+	return fmt.Sprintf("%s:%d", driveID, seqNum)
+}
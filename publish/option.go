@@ -0,0 +1,90 @@
+package publish
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option is a configuration option for a publisher.
+type Option func(*Publisher)
+
+// WithSink adds a sink to the publisher. Every event is given to each sink
+// in the order the sinks were added.
+func WithSink(sink Sink) Option {
+	return func(p *Publisher) {
+		p.sinks = append(p.sinks, sink)
+	}
+}
+
+// WithConsumer sets the name of the consumer checkpoint that records the
+// progress of the publisher. Publishers with different consumer names
+// progress independently.
+func WithConsumer(name string) Option {
+	return func(p *Publisher) {
+		p.consumer = name
+	}
+}
+
+// WebhookOption is a configuration option for a webhook.
+type WebhookOption func(*Webhook)
+
+// WithSecret causes the webhook to sign each request with secret.
+func WithSecret(secret []byte) WebhookOption {
+	return func(w *Webhook) {
+		w.secret = secret
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to post events.
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.client = client
+	}
+}
+
+// WithRetries sets the number of attempts made to deliver each event and
+// the delay before the first retry. The delay doubles after each attempt,
+// up to a minute.
+func WithRetries(attempts int, backoff time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		w.attempts = attempts
+		w.backoff = backoff
+		if w.maxBackoff < backoff {
+			w.maxBackoff = backoff
+		}
+	}
+}
+
+// SpoolOption is a configuration option for a spool.
+type SpoolOption func(*Spool)
+
+// WithPrefix sets the prefix of the names of spool files.
+func WithPrefix(prefix string) SpoolOption {
+	return func(s *Spool) {
+		s.prefix = prefix
+	}
+}
+
+// WithMaxSize causes the spool to start a new file once the current one
+// would grow beyond size bytes. A size of zero disables rotation by size.
+func WithMaxSize(size int64) SpoolOption {
+	return func(s *Spool) {
+		s.maxSize = size
+	}
+}
+
+// WithMaxAge causes the spool to start a new file once the current one is
+// older than d. An age of zero disables rotation by age.
+func WithMaxAge(d time.Duration) SpoolOption {
+	return func(s *Spool) {
+		s.maxAge = d
+	}
+}
+
+// WithMaxFiles causes the spool to remove its oldest files so that no more
+// than n remain. A limit of zero keeps every file.
+func WithMaxFiles(n int) SpoolOption {
+	return func(s *Spool) {
+		s.maxFiles = n
+	}
+}
@@ -0,0 +1,386 @@
+package publish_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/memrepo"
+	"github.com/scjalliance/drivestream/publish"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+const testDrive = resource.ID("drive")
+
+var testSecret = []byte("s3cret")
+
+// newRepo returns a repository holding n finalized commits of testDrive,
+// each of which adds a version of a file.
+func newRepo(t *testing.T, n int) *memrepo.Repository {
+	t.Helper()
+	repo := memrepo.New()
+	drv := repo.Drive(testDrive)
+	for i := 0; i < n; i++ {
+		seqNum := commit.SeqNum(i)
+		version := resource.Version(i + 1)
+		if err := repo.File("file").Version(version).Create(resource.FileData{Name: "file", Parents: []string{string(testDrive)}}); err != nil {
+			t.Fatal(err)
+		}
+		ref := drv.Commit(seqNum)
+		if err := ref.Create(commit.Data{Time: time.Unix(int64(i), 0).UTC()}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ref.Files().Add(commit.FileChange{File: "file", Version: version}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ref.State(0).Create(commit.State{StateData: commit.StateData{Phase: commit.PhaseFinalized}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// next returns the next commit that the default publisher will publish.
+func next(t *testing.T, repo *memrepo.Repository) commit.SeqNum {
+	t.Helper()
+	seqNum, err := consumer.Next(repo.Drive(testDrive).Consumers().Ref(publish.DefaultConsumer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seqNum
+}
+
+// receiver records the events posted to a webhook. It fails the first
+// requests with the given statuses.
+type receiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	events   []publish.Event
+	times    []time.Time
+	invalid  int
+}
+
+func (rcv *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rcv.mutex.Lock()
+	defer rcv.mutex.Unlock()
+
+	rcv.times = append(rcv.times, time.Now())
+	if !publish.Verify(testSecret, body, r.Header.Get(publish.SignatureHeader)) {
+		rcv.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if len(rcv.statuses) > 0 {
+		status := rcv.statuses[0]
+		rcv.statuses = rcv.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	var event publish.Event
+	if err := json.Unmarshal(body, &event); err != nil || r.Header.Get(publish.DeliveryHeader) != event.ID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rcv.events = append(rcv.events, event)
+}
+
+func TestWebhookRetry(t *testing.T) {
+	rcv := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	backoff := 20 * time.Millisecond
+	hook := publish.NewWebhook(srv.URL, publish.WithSecret(testSecret), publish.WithRetries(3, backoff))
+	if err := hook.Publish(context.Background(), publish.Event{ID: "drive:0", Type: publish.EventType}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rcv.times) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(rcv.times))
+	}
+	if len(rcv.events) != 1 || rcv.events[0].ID != "drive:0" {
+		t.Fatalf("unexpected events: %v", rcv.events)
+	}
+	if d := rcv.times[1].Sub(rcv.times[0]); d < backoff {
+		t.Errorf("first retry came after %v, expected at least %v", d, backoff)
+	}
+	if d := rcv.times[2].Sub(rcv.times[1]); d < 2*backoff {
+		t.Errorf("second retry came after %v, expected at least %v", d, 2*backoff)
+	}
+}
+
+func TestWebhookGiveUp(t *testing.T) {
+	rcv := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	hook := publish.NewWebhook(srv.URL, publish.WithSecret(testSecret), publish.WithRetries(2, time.Millisecond))
+	err := hook.Publish(context.Background(), publish.Event{ID: "drive:0"})
+	failure, ok := err.(publish.DeliveryFailed)
+	if !ok {
+		t.Fatalf("expected DeliveryFailed, got %T: %v", err, err)
+	}
+	if failure.Attempts != 2 || failure.Status != http.StatusInternalServerError {
+		t.Fatalf("unexpected failure: %+v", failure)
+	}
+}
+
+func TestWebhookNoRetry(t *testing.T) {
+	rcv := &receiver{statuses: []int{http.StatusBadRequest}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	hook := publish.NewWebhook(srv.URL, publish.WithSecret(testSecret), publish.WithRetries(5, time.Millisecond))
+	err := hook.Publish(context.Background(), publish.Event{ID: "drive:0"})
+	if failure, ok := err.(publish.DeliveryFailed); !ok || failure.Attempts != 1 {
+		t.Fatalf("expected a single failed attempt, got %T: %v", err, err)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	rcv := &receiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	hook := publish.NewWebhook(srv.URL, publish.WithSecret([]byte("wrong")), publish.WithRetries(1, time.Millisecond))
+	err := hook.Publish(context.Background(), publish.Event{ID: "drive:0"})
+	if failure, ok := err.(publish.DeliveryFailed); !ok || failure.Status != http.StatusUnauthorized {
+		t.Fatalf("expected a rejected delivery, got %T: %v", err, err)
+	}
+	if rcv.invalid != 1 {
+		t.Fatalf("expected 1 invalid signature, got %d", rcv.invalid)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"drive:0"}`)
+	signature := publish.Sign(testSecret, body)
+	if !publish.Verify(testSecret, body, signature) {
+		t.Error("valid signature was rejected")
+	}
+	if publish.Verify([]byte("wrong"), body, signature) {
+		t.Error("signature with the wrong secret was accepted")
+	}
+	if publish.Verify(testSecret, []byte(`{"id":"drive:1"}`), signature) {
+		t.Error("signature of a different body was accepted")
+	}
+	if publish.Verify(testSecret, body, signature[len("sha256="):]) {
+		t.Error("signature without a prefix was accepted")
+	}
+	if publish.Verify(testSecret, body, "sha256=zz") {
+		t.Error("malformed signature was accepted")
+	}
+}
+
+// failingSink accepts events until it has accepted limit events, and then
+// fails.
+type failingSink struct {
+	limit  int
+	events []publish.Event
+}
+
+var errSinkFull = errors.New("sink is full")
+
+func (s *failingSink) Publish(ctx context.Context, event publish.Event) error {
+	if len(s.events) >= s.limit {
+		return errSinkFull
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestPublishPartialFailure(t *testing.T) {
+	repo := newRepo(t, 3)
+	rcv := &receiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	hook := publish.NewWebhook(srv.URL, publish.WithSecret(testSecret))
+	sink := &failingSink{limit: 1}
+	p := publish.New(repo, publish.WithSink(hook), publish.WithSink(sink))
+
+	// The second commit reaches the webhook but not the second sink, so
+	// the checkpoint must only cover the first commit.
+	published, err := p.Publish(context.Background(), testDrive)
+	if err != errSinkFull {
+		t.Fatalf("expected the sink's error, got %v", err)
+	}
+	if published != 1 {
+		t.Fatalf("expected 1 published commit, got %d", published)
+	}
+	if seqNum := next(t, repo); seqNum != 1 {
+		t.Fatalf("expected the checkpoint to advance to 1, got %d", seqNum)
+	}
+
+	// Once the sink recovers, publishing resumes with the commit that
+	// failed, which the webhook receives a second time.
+	sink.limit = 3
+	published, err = p.Publish(context.Background(), testDrive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 2 {
+		t.Fatalf("expected 2 published commits, got %d", published)
+	}
+	if seqNum := next(t, repo); seqNum != 3 {
+		t.Fatalf("expected the checkpoint to advance to 3, got %d", seqNum)
+	}
+
+	var ids []string
+	for _, event := range rcv.events {
+		ids = append(ids, event.ID)
+	}
+	want := []string{"drive:0", "drive:1", "drive:1", "drive:2"}
+	if len(ids) != len(want) {
+		t.Fatalf("webhook received %v, expected %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("webhook received %v, expected %v", ids, want)
+		}
+	}
+	for i, event := range sink.events {
+		if event.Commit != commit.SeqNum(i) {
+			t.Fatalf("sink received commit %d at position %d", event.Commit, i)
+		}
+		if len(event.Files) != 1 || event.Files[0].Data == nil || event.Files[0].Version != resource.Version(i+1) {
+			t.Fatalf("unexpected files in event %s: %+v", event.ID, event.Files)
+		}
+	}
+}
+
+func TestPublishUnfinalized(t *testing.T) {
+	repo := newRepo(t, 2)
+	ref := repo.Drive(testDrive).Commit(2)
+	if err := ref.Create(commit.Data{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ref.State(0).Create(commit.State{StateData: commit.StateData{Phase: commit.PhaseTreeProcessing}}); err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &failingSink{limit: 10}
+	published, err := publish.New(repo, publish.WithSink(sink)).Publish(context.Background(), testDrive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 2 || next(t, repo) != 2 {
+		t.Fatalf("expected publishing to stop before the unfinalized commit, published %d", published)
+	}
+}
+
+func TestSpoolRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Each event is larger than the maximum size, so each one starts a
+	// new file.
+	spool, err := publish.NewSpool(dir, publish.WithMaxSize(1), publish.WithMaxFiles(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		event := publish.Event{ID: publish.EventID(testDrive, commit.SeqNum(i)), Commit: commit.SeqNum(i)}
+		if err := spool.Publish(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+		// File names have nanosecond resolution, but make sure they differ.
+		time.Sleep(time.Millisecond)
+	}
+	if err := spool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, publish.DefaultSpoolPrefix+"*.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	if len(names) != 3 {
+		t.Fatalf("expected 3 spool files, got %d", len(names))
+	}
+
+	// The oldest files were removed, so the remaining ones hold the last
+	// three events in order.
+	for i, name := range names {
+		events := readSpool(t, name)
+		if len(events) != 1 || events[0].Commit != commit.SeqNum(i+2) {
+			t.Fatalf("unexpected events in %s: %v", name, events)
+		}
+	}
+}
+
+func TestSpoolAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spool, err := publish.NewSpool(dir, publish.WithPrefix("events-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := spool.Publish(context.Background(), publish.Event{Commit: commit.SeqNum(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := spool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "events-*.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 {
+		t.Fatalf("expected 1 spool file, got %d", len(names))
+	}
+	if events := readSpool(t, names[0]); len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+}
+
+// readSpool returns the events within a spool file.
+func readSpool(t *testing.T, name string) (events []publish.Event) {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event publish.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
@@ -0,0 +1,136 @@
+package publish
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// DefaultConsumer is the default name of the consumer checkpoint that
+// records the progress of a publisher.
+const DefaultConsumer = "publisher"
+
+// Publisher emits the finalized commits of drives to its sinks. It should
+// be created by calling New.
+type Publisher struct {
+	repo     drivestream.Repository
+	consumer string
+	sinks    []Sink
+}
+
+// New returns a publisher for the drives within repo.
+func New(repo drivestream.Repository, options ...Option) *Publisher {
+	p := &Publisher{
+		repo:     repo,
+		consumer: DefaultConsumer,
+	}
+	for _, opt := range options {
+		opt(p)
+	}
+	return p
+}
+
+// Publish emits every finalized commit of a drive that the publisher
+// hasn't yet published, in order. It returns the number of commits that
+// were published.
+//
+// The publisher's checkpoint is advanced after each commit has been
+// accepted by every sink. If a sink fails, Publish returns its error and
+// the commit will be published again by the next call.
+func (p *Publisher) Publish(ctx context.Context, driveID resource.ID) (published int, err error) {
+	ref := p.repo.Drive(driveID).Consumers().Ref(p.consumer)
+	seqNum, err := consumer.Next(ref)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return published, err
+		}
+
+		com, ok, err := drivestream.ReadCommitEvent(p.repo, driveID, seqNum)
+		if err != nil || !ok {
+			return published, err
+		}
+
+		event, err := p.event(driveID, com)
+		if err != nil {
+			return published, err
+		}
+
+		for _, sink := range p.sinks {
+			if err := sink.Publish(ctx, event); err != nil {
+				return published, err
+			}
+		}
+
+		if err := ref.Set(consumer.Data{Commit: seqNum, Time: time.Now()}); err != nil {
+			return published, err
+		}
+
+		published++
+		seqNum++
+	}
+}
+
+// Close closes each sink of the publisher that implements io.Closer.
+func (p *Publisher) Close() error {
+	var first error
+	for _, sink := range p.sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// event returns the event for a commit.
+func (p *Publisher) event(driveID resource.ID, com drivestream.CommitEvent) (Event, error) {
+	event := Event{
+		ID:     EventID(driveID, com.SeqNum),
+		Type:   EventType,
+		Drive:  driveID,
+		Commit: com.SeqNum,
+		Time:   com.Time,
+		Source: com.Source,
+		Files:  make([]FileChange, 0, len(com.Files)),
+		Tree:   make([]TreeChange, 0, len(com.Tree)),
+	}
+	if !com.Drift.IsZero() {
+		drift := com.Drift
+		event.Drift = &drift
+	}
+
+	for _, change := range com.Files {
+		fc := FileChange{
+			File:    change.File,
+			Version: change.Version,
+			Removed: change.Version < 0,
+		}
+		if !fc.Removed {
+			data, err := p.repo.File(change.File).Version(change.Version).Data()
+			if err != nil {
+				return Event{}, err
+			}
+			fc.Data = &data
+		}
+		event.Files = append(event.Files, fc)
+	}
+
+	for _, change := range com.Tree {
+		event.Tree = append(event.Tree, TreeChange{
+			Parent:  change.Parent,
+			Child:   change.Child,
+			Removed: change.Removed,
+		})
+	}
+
+	return event, nil
+}
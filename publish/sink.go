@@ -0,0 +1,12 @@
+package publish
+
+import "context"
+
+// Sink receives the events emitted by a publisher.
+//
+// Publish must return nil only once the sink has durably accepted the
+// event. A sink that returns an error will be given the same event again
+// the next time the publisher runs.
+type Sink interface {
+	Publish(ctx context.Context, event Event) error
+}
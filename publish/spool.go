@@ -0,0 +1,172 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default rotation behavior of a spool.
+const (
+	DefaultSpoolPrefix  = "drivestream-"
+	DefaultSpoolMaxSize = 64 * 1024 * 1024
+)
+
+// spoolSuffix is the file name extension of spool files.
+const spoolSuffix = ".ndjson"
+
+// spoolTimeFormat is the layout of the time within spool file names, which
+// sorts in chronological order.
+const spoolTimeFormat = "20060102T150405.000000000Z"
+
+var _ Sink = (*Spool)(nil)
+
+// Spool is a sink that appends each event to a file as a line of JSON. It
+// should be created by calling NewSpool.
+//
+// Events are written to a file within the spool's directory until the file
+// reaches the spool's maximum size or age, at which point a new file is
+// started. Files are named with the time they were started, so log
+// shippers can collect them in order. Each event is synced to disk before
+// it is considered published.
+type Spool struct {
+	dir      string
+	prefix   string
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+
+	mutex   sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+}
+
+// NewSpool returns a spool sink that writes files to dir. The directory is
+// created if it doesn't exist.
+func NewSpool(dir string, options ...SpoolOption) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &Spool{
+		dir:     dir,
+		prefix:  DefaultSpoolPrefix,
+		maxSize: DefaultSpoolMaxSize,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Publish appends event to the current spool file.
+func (s *Spool) Publish(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil || s.due(int64(len(line))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the current spool file.
+func (s *Spool) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// due returns true if writing n more bytes to the current file would
+// exceed the spool's limits. A file always receives at least one event.
+func (s *Spool) due(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.maxSize > 0 && s.size+n > s.maxSize {
+		return true
+	}
+	if s.maxAge > 0 && time.Since(s.started) >= s.maxAge {
+		return true
+	}
+	return false
+}
+
+// rotate closes the current file, starts a new one and removes the oldest
+// files beyond the spool's limit.
+func (s *Spool) rotate() error {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return err
+		}
+		s.file = nil
+	}
+
+	now := time.Now().UTC()
+	name := filepath.Join(s.dir, s.prefix+now.Format(spoolTimeFormat)+spoolSuffix)
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.size, s.started = file, info.Size(), now
+
+	if s.maxFiles > 0 {
+		return s.trim(filepath.Base(name))
+	}
+	return nil
+}
+
+// trim removes the oldest spool files so that no more than maxFiles
+// remain. The current file is never removed.
+func (s *Spool) trim(current string) error {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Mode().IsRegular() && strings.HasPrefix(name, s.prefix) && strings.HasSuffix(name, spoolSuffix) && name != current {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for len(names) >= s.maxFiles {
+		if err := os.Remove(filepath.Join(s.dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Webhook header names.
+const (
+	EventHeader     = "X-Drivestream-Event"
+	DeliveryHeader  = "X-Drivestream-Delivery"
+	SignatureHeader = "X-Drivestream-Signature"
+)
+
+// signaturePrefix identifies the algorithm used to compute a signature.
+const signaturePrefix = "sha256="
+
+// Default retry behavior of a webhook.
+const (
+	DefaultAttempts   = 5
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = time.Minute
+)
+
+var _ Sink = (*Webhook)(nil)
+
+// Webhook is a sink that posts each event to an HTTP endpoint as a JSON
+// document. It should be created by calling NewWebhook.
+//
+// When a secret is provided, each request carries an HMAC-SHA256
+// signature of its body in the X-Drivestream-Signature header.
+//
+// Requests that fail with a transport error, a server error, or a 408 or
+// 429 status are retried with exponential backoff. Any other status
+// outside of the 2xx range fails the delivery immediately.
+type Webhook struct {
+	url        string
+	secret     []byte
+	client     *http.Client
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// NewWebhook returns a webhook sink that posts events to url.
+func NewWebhook(url string, options ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url:        url,
+		client:     http.DefaultClient,
+		attempts:   DefaultAttempts,
+		backoff:    DefaultBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range options {
+		opt(w)
+	}
+	return w
+}
+
+// Publish posts event to the webhook's endpoint.
+func (w *Webhook) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	failure := DeliveryFailed{URL: w.url, Event: event.ID}
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		failure.Attempts = attempt
+		failure.Status, failure.Err = w.post(ctx, event, body)
+		if failure.Err == nil && failure.Status >= 200 && failure.Status < 300 {
+			return nil
+		}
+		if failure.Err == nil && !retryable(failure.Status) {
+			return failure
+		}
+		if attempt >= w.attempts {
+			return failure
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > w.maxBackoff {
+			backoff = w.maxBackoff
+		}
+	}
+}
+
+// post sends a single request and returns its status.
+func (w *Webhook) post(ctx context.Context, event Event, body []byte) (status int, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	if len(w.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Drain the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// retryable returns true if a request that failed with status should be
+// attempted again.
+func retryable(status int) bool {
+	switch {
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return true
+	case status >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sign returns the signature of body for the given secret, as it appears
+// in the X-Drivestream-Signature header.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if signature is a valid signature of body for the
+// given secret. Receivers should call it with the unmodified request body.
+func Verify(secret, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(given, mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ collection.Reference = (*Collection)(nil)
+
+// Collection is a read-only collection reference.
+type Collection struct {
+	ref collection.Reference
+}
+
+// Drive returns the drive ID of the collection.
+func (ref Collection) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// SeqNum returns the sequence number of the collection.
+func (ref Collection) SeqNum() collection.SeqNum {
+	return ref.ref.SeqNum()
+}
+
+// Exists returns true if the collection exists.
+func (ref Collection) Exists() (bool, error) {
+	return ref.ref.Exists()
+}
+
+// Create returns ErrReadOnly.
+func (ref Collection) Create(data collection.Data) error {
+	return ErrReadOnly{Op: "create collection"}
+}
+
+// Data returns information about the collection.
+func (ref Collection) Data() (collection.Data, error) {
+	return ref.ref.Data()
+}
+
+// States returns the state sequence for the collection.
+func (ref Collection) States() collection.StateSequence {
+	return CollectionStates{seq: ref.ref.States()}
+}
+
+// State returns a state reference.
+func (ref Collection) State(stateNum collection.StateNum) collection.StateReference {
+	return CollectionState{ref: ref.ref.State(stateNum)}
+}
+
+// Pages returns the page sequence for the collection.
+func (ref Collection) Pages() page.Sequence {
+	return Pages{seq: ref.ref.Pages()}
+}
+
+// Page returns a page reference.
+func (ref Collection) Page(seqNum page.SeqNum) page.Reference {
+	return Page{ref: ref.ref.Page(seqNum)}
+}
+
+// Prune returns ErrReadOnly.
+func (ref Collection) Prune() (collection.PruneData, error) {
+	return collection.PruneData{}, ErrReadOnly{Op: "prune collection"}
+}
+
+// Pruned returns a summary of the changes that were discarded when the
+// collection was pruned.
+func (ref Collection) Pruned() (collection.PruneData, error) {
+	return ref.ref.Pruned()
+}
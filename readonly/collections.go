@@ -0,0 +1,27 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/collection"
+
+var _ collection.Sequence = (*Collections)(nil)
+
+// Collections is a read-only collection sequence.
+type Collections struct {
+	seq collection.Sequence
+}
+
+// Next returns the sequence number to use for the next collection.
+func (ref Collections) Next() (n collection.SeqNum, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads collection data for a range of collections starting at the
+// given sequence number. Up to len(p) entries will be returned in p.
+// The number of entries is returned as n.
+func (ref Collections) Read(start collection.SeqNum, p []collection.Data) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a collection reference for the sequence number.
+func (ref Collections) Ref(seqNum collection.SeqNum) collection.Reference {
+	return Collection{ref: ref.seq.Ref(seqNum)}
+}
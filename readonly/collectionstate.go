@@ -0,0 +1,25 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/collection"
+
+var _ collection.StateReference = (*CollectionState)(nil)
+
+// CollectionState is a read-only collection state reference.
+type CollectionState struct {
+	ref collection.StateReference
+}
+
+// StateNum returns the state number of the reference.
+func (ref CollectionState) StateNum() collection.StateNum {
+	return ref.ref.StateNum()
+}
+
+// Create returns ErrReadOnly.
+func (ref CollectionState) Create(state collection.State) error {
+	return ErrReadOnly{Op: "create collection state"}
+}
+
+// Data returns the collection state.
+func (ref CollectionState) Data() (collection.State, error) {
+	return ref.ref.Data()
+}
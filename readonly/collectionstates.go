@@ -0,0 +1,27 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/collection"
+
+var _ collection.StateSequence = (*CollectionStates)(nil)
+
+// CollectionStates is a read-only collection state sequence.
+type CollectionStates struct {
+	seq collection.StateSequence
+}
+
+// Next returns the state number to use for the next state.
+func (ref CollectionStates) Next() (n collection.StateNum, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads collection states starting at the given state number. Up to
+// len(p) entries will be returned in p. The number of entries is returned
+// as n.
+func (ref CollectionStates) Read(start collection.StateNum, p []collection.State) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a collection state reference for the state number.
+func (ref CollectionStates) Ref(stateNum collection.StateNum) collection.StateReference {
+	return CollectionState{ref: ref.seq.Ref(stateNum)}
+}
@@ -0,0 +1,68 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ commit.Reference = (*Commit)(nil)
+
+// Commit is a read-only commit reference.
+type Commit struct {
+	ref commit.Reference
+}
+
+// Drive returns the drive ID of the commit.
+func (ref Commit) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// SeqNum returns the sequence number of the commit.
+func (ref Commit) SeqNum() commit.SeqNum {
+	return ref.ref.SeqNum()
+}
+
+// Exists returns true if the commit exists.
+func (ref Commit) Exists() (bool, error) {
+	return ref.ref.Exists()
+}
+
+// Create returns ErrReadOnly.
+func (ref Commit) Create(data commit.Data) error {
+	return ErrReadOnly{Op: "create commit"}
+}
+
+// Data returns information about the commit.
+func (ref Commit) Data() (commit.Data, error) {
+	return ref.ref.Data()
+}
+
+// States returns the state sequence for the commit.
+func (ref Commit) States() commit.StateSequence {
+	return CommitStates{seq: ref.ref.States()}
+}
+
+// State returns a state reference.
+func (ref Commit) State(stateNum commit.StateNum) commit.StateReference {
+	return CommitState{ref: ref.ref.State(stateNum)}
+}
+
+// Files returns the map of file changes for the commit.
+func (ref Commit) Files() commit.FileMap {
+	return CommitFiles{files: ref.ref.Files()}
+}
+
+// Tree returns the map of tree changes for the commit.
+func (ref Commit) Tree() commit.TreeMap {
+	return CommitTree{tree: ref.ref.Tree()}
+}
+
+// SetDrift returns ErrReadOnly.
+func (ref Commit) SetDrift(data commit.DriftData) error {
+	return ErrReadOnly{Op: "set commit drift"}
+}
+
+// Drift returns the drift that was corrected by the commit.
+func (ref Commit) Drift() (commit.DriftData, error) {
+	return ref.ref.Drift()
+}
@@ -0,0 +1,20 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/commit"
+
+var _ commit.FileMap = (*CommitFiles)(nil)
+
+// CommitFiles is a read-only map of commit file changes.
+type CommitFiles struct {
+	files commit.FileMap
+}
+
+// Read returns the file changes for the commit.
+func (ref CommitFiles) Read() (changes []commit.FileChange, err error) {
+	return ref.files.Read()
+}
+
+// Add returns ErrReadOnly.
+func (ref CommitFiles) Add(changes ...commit.FileChange) error {
+	return ErrReadOnly{Op: "add commit file changes"}
+}
@@ -0,0 +1,27 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/commit"
+
+var _ commit.Sequence = (*Commits)(nil)
+
+// Commits is a read-only commit sequence.
+type Commits struct {
+	seq commit.Sequence
+}
+
+// Next returns the sequence number to use for the next commit.
+func (ref Commits) Next() (n commit.SeqNum, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads commit data for a range of commits starting at the given
+// sequence number. Up to len(p) entries will be returned in p. The number
+// of entries is returned as n.
+func (ref Commits) Read(start commit.SeqNum, p []commit.Data) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a commit reference for the sequence number.
+func (ref Commits) Ref(seqNum commit.SeqNum) commit.Reference {
+	return Commit{ref: ref.seq.Ref(seqNum)}
+}
@@ -0,0 +1,25 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/commit"
+
+var _ commit.StateReference = (*CommitState)(nil)
+
+// CommitState is a read-only commit state reference.
+type CommitState struct {
+	ref commit.StateReference
+}
+
+// StateNum returns the state number of the reference.
+func (ref CommitState) StateNum() commit.StateNum {
+	return ref.ref.StateNum()
+}
+
+// Create returns ErrReadOnly.
+func (ref CommitState) Create(data commit.State) error {
+	return ErrReadOnly{Op: "create commit state"}
+}
+
+// Data returns the commit state.
+func (ref CommitState) Data() (commit.State, error) {
+	return ref.ref.Data()
+}
@@ -0,0 +1,27 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/commit"
+
+var _ commit.StateSequence = (*CommitStates)(nil)
+
+// CommitStates is a read-only commit state sequence.
+type CommitStates struct {
+	seq commit.StateSequence
+}
+
+// Next returns the state number to use for the next state.
+func (ref CommitStates) Next() (n commit.StateNum, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads commit states starting at the given state number. Up to
+// len(p) entries will be returned in p. The number of entries is returned
+// as n.
+func (ref CommitStates) Read(start commit.StateNum, p []commit.State) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a commit state reference for the state number.
+func (ref CommitStates) Ref(stateNum commit.StateNum) commit.StateReference {
+	return CommitState{ref: ref.seq.Ref(stateNum)}
+}
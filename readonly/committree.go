@@ -0,0 +1,28 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ commit.TreeMap = (*CommitTree)(nil)
+
+// CommitTree is a read-only map of commit tree changes.
+type CommitTree struct {
+	tree commit.TreeMap
+}
+
+// Parents returns the parents affected by the commit.
+func (ref CommitTree) Parents() (parents []resource.ID, err error) {
+	return ref.tree.Parents()
+}
+
+// Group returns the tree changes for a parent. Tree groups are read-only.
+func (ref CommitTree) Group(parent resource.ID) commit.TreeGroup {
+	return ref.tree.Group(parent)
+}
+
+// Add returns ErrReadOnly.
+func (ref CommitTree) Add(changes ...commit.TreeChange) error {
+	return ErrReadOnly{Op: "add commit tree changes"}
+}
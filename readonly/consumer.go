@@ -0,0 +1,43 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ consumer.Reference = (*Consumer)(nil)
+
+// Consumer is a read-only consumer reference.
+type Consumer struct {
+	ref consumer.Reference
+}
+
+// Drive returns the ID of the drive.
+func (ref Consumer) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// Name returns the name of the consumer.
+func (ref Consumer) Name() string {
+	return ref.ref.Name()
+}
+
+// Exists returns true if the consumer has acknowledged a commit.
+func (ref Consumer) Exists() (bool, error) {
+	return ref.ref.Exists()
+}
+
+// Data returns the progress of the consumer.
+func (ref Consumer) Data() (consumer.Data, error) {
+	return ref.ref.Data()
+}
+
+// Set returns ErrReadOnly.
+func (ref Consumer) Set(data consumer.Data) error {
+	return ErrReadOnly{Op: "set consumer"}
+}
+
+// Delete returns ErrReadOnly.
+func (ref Consumer) Delete() error {
+	return ErrReadOnly{Op: "delete consumer"}
+}
@@ -0,0 +1,20 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/consumer"
+
+var _ consumer.Map = (*Consumers)(nil)
+
+// Consumers is a read-only map of drive consumers.
+type Consumers struct {
+	consumers consumer.Map
+}
+
+// List returns the names of all consumers within the map.
+func (ref Consumers) List() (names []string, err error) {
+	return ref.consumers.List()
+}
+
+// Ref returns a read-only reference to the named consumer.
+func (ref Consumers) Ref(name string) consumer.Reference {
+	return Consumer{ref: ref.consumers.Ref(name)}
+}
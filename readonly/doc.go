@@ -0,0 +1,7 @@
+// Package readonly provides a drivestream repository wrapper that prevents
+// modification of the repository it wraps.
+//
+// Every method that would modify the repository returns an ErrReadOnly
+// error without calling the wrapped repository. Methods that only read
+// data are passed through unchanged.
+package readonly
@@ -0,0 +1,86 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.DriveReference = (*Drive)(nil)
+
+// Drive is a read-only drive reference.
+type Drive struct {
+	ref drivestream.DriveReference
+}
+
+// DriveID returns the resource ID of the drive.
+func (ref Drive) DriveID() resource.ID {
+	return ref.ref.DriveID()
+}
+
+// Exists returns true if the drive exists.
+func (ref Drive) Exists() (bool, error) {
+	return ref.ref.Exists()
+}
+
+// Collections returns the collection sequence for the drive.
+func (ref Drive) Collections() collection.Sequence {
+	return Collections{seq: ref.ref.Collections()}
+}
+
+// Collection returns a collection reference.
+func (ref Drive) Collection(seqNum collection.SeqNum) collection.Reference {
+	return Collection{ref: ref.ref.Collection(seqNum)}
+}
+
+// Commits returns the commit sequence for the drive.
+func (ref Drive) Commits() commit.Sequence {
+	return Commits{seq: ref.ref.Commits()}
+}
+
+// Commit returns a commit reference.
+func (ref Drive) Commit(seqNum commit.SeqNum) commit.Reference {
+	return Commit{ref: ref.ref.Commit(seqNum)}
+}
+
+// Versions returns the version sequence for the drive.
+func (ref Drive) Versions() driveversion.Sequence {
+	return DriveVersions{seq: ref.ref.Versions()}
+}
+
+// Version returns a drive version reference.
+func (ref Drive) Version(v resource.Version) driveversion.Reference {
+	return DriveVersion{ref: ref.ref.Version(v)}
+}
+
+// View returns a view of the drive.
+func (ref Drive) View() driveview.Reference {
+	return DriveView{ref: ref.ref.View()}
+}
+
+// At returns a version reference of the drive at a particular commit.
+func (ref Drive) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	return ref.View().At(seqNum)
+}
+
+// Actors returns the map of actors that have made changes within the
+// drive.
+func (ref Drive) Actors() driveactor.Map {
+	return DriveActors{actors: ref.ref.Actors()}
+}
+
+// Consumers returns the read-only map of named consumers that process the
+// commits of the drive.
+func (ref Drive) Consumers() consumer.Map {
+	return Consumers{consumers: ref.ref.Consumers()}
+}
+
+// Stats returns statistics about the drive.
+func (ref Drive) Stats() (drivestream.DriveStats, error) {
+	return ref.ref.Stats()
+}
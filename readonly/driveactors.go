@@ -0,0 +1,26 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/driveactor"
+
+var _ driveactor.Map = (*DriveActors)(nil)
+
+// DriveActors is a read-only map of drive actors.
+type DriveActors struct {
+	actors driveactor.Map
+}
+
+// List returns the email addresses of all actors within the drive.
+func (ref DriveActors) List() (actors []string, err error) {
+	return ref.actors.List()
+}
+
+// Ref returns an actor reference for the email address. Actor references
+// are read-only.
+func (ref DriveActors) Ref(email string) driveactor.Reference {
+	return ref.actors.Ref(email)
+}
+
+// Add returns ErrReadOnly.
+func (ref DriveActors) Add(entries ...driveactor.Entry) error {
+	return ErrReadOnly{Op: "add drive actors"}
+}
@@ -0,0 +1,23 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.DriveMap = (*Drives)(nil)
+
+// Drives is a read-only map of drives.
+type Drives struct {
+	drives drivestream.DriveMap
+}
+
+// List returns a list of all drives within the map.
+func (ref Drives) List() (ids []resource.ID, err error) {
+	return ref.drives.List()
+}
+
+// Ref returns a drive reference.
+func (ref Drives) Ref(driveID resource.ID) drivestream.DriveReference {
+	return Drive{ref: ref.drives.Ref(driveID)}
+}
@@ -0,0 +1,33 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveversion.Reference = (*DriveVersion)(nil)
+
+// DriveVersion is a read-only drive version reference.
+type DriveVersion struct {
+	ref driveversion.Reference
+}
+
+// Drive returns the ID of the drive.
+func (ref DriveVersion) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// Version returns the version number of the drive.
+func (ref DriveVersion) Version() resource.Version {
+	return ref.ref.Version()
+}
+
+// Create returns ErrReadOnly.
+func (ref DriveVersion) Create(data resource.DriveData) error {
+	return ErrReadOnly{Op: "create drive version"}
+}
+
+// Data returns the data of the drive version.
+func (ref DriveVersion) Data() (resource.DriveData, error) {
+	return ref.ref.Data()
+}
@@ -0,0 +1,30 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveversion.Sequence = (*DriveVersions)(nil)
+
+// DriveVersions is a read-only drive version sequence.
+type DriveVersions struct {
+	seq driveversion.Sequence
+}
+
+// Next returns the version number to use for the next drive version.
+func (ref DriveVersions) Next() (n resource.Version, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads drive data for a range of versions starting at the given
+// version number. Up to len(p) entries will be returned in p. The number
+// of entries is returned as n.
+func (ref DriveVersions) Read(start resource.Version, p []resource.DriveData) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a drive version reference for the version number.
+func (ref DriveVersions) Ref(v resource.Version) driveversion.Reference {
+	return DriveVersion{ref: ref.seq.Ref(v)}
+}
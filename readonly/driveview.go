@@ -0,0 +1,39 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ driveview.Reference = (*DriveView)(nil)
+
+// DriveView is a read-only drive view reference.
+type DriveView struct {
+	ref driveview.Reference
+}
+
+// Drive returns the ID of the drive being viewed.
+func (ref DriveView) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// At returns the version reference of the drive at a particular commit.
+func (ref DriveView) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	r, err := ref.ref.At(seqNum)
+	if err != nil {
+		return nil, err
+	}
+	return DriveVersion{ref: r}, nil
+}
+
+// Add returns ErrReadOnly.
+func (ref DriveView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	return ErrReadOnly{Op: "add drive view"}
+}
+
+// Entries returns every view of the drive in commit order.
+func (ref DriveView) Entries() ([]driveview.Entry, error) {
+	return ref.ref.Entries()
+}
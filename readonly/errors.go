@@ -0,0 +1,14 @@
+package readonly
+
+import "fmt"
+
+// ErrReadOnly reports that an operation could not be performed because it
+// would modify a read-only repository.
+type ErrReadOnly struct {
+	Op string
+}
+
+// Error returns a string representation of the error.
+func (e ErrReadOnly) Error() string {
+	return fmt.Sprintf("drivestream: %s: the repository is read-only", e.Op)
+}
@@ -0,0 +1,45 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.FileReference = (*File)(nil)
+
+// File is a read-only file reference.
+type File struct {
+	ref drivestream.FileReference
+}
+
+// FileID returns the resource ID of the file.
+func (ref File) FileID() resource.ID {
+	return ref.ref.FileID()
+}
+
+// Exists returns true if the file exists.
+func (ref File) Exists() (bool, error) {
+	return ref.ref.Exists()
+}
+
+// Versions returns the version map for the file.
+func (ref File) Versions() fileversion.Map {
+	return FileVersions{versions: ref.ref.Versions()}
+}
+
+// Version returns a file version reference.
+func (ref File) Version(v resource.Version) fileversion.Reference {
+	return FileVersion{ref: ref.ref.Version(v)}
+}
+
+// Views returns the view map for the file.
+func (ref File) Views() fileview.Map {
+	return FileViews{views: ref.ref.Views()}
+}
+
+// View returns a view of the file for a particular drive.
+func (ref File) View(driveID resource.ID) fileview.Reference {
+	return FileView{ref: ref.ref.View(driveID)}
+}
@@ -0,0 +1,34 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.FileMap = (*Files)(nil)
+
+// Files is a read-only map of files.
+type Files struct {
+	files drivestream.FileMap
+}
+
+// List returns the list of files contained within the repository.
+func (ref Files) List() ([]resource.ID, error) {
+	return ref.files.List()
+}
+
+// Ref returns a file reference.
+func (ref Files) Ref(fileID resource.ID) drivestream.FileReference {
+	return File{ref: ref.files.Ref(fileID)}
+}
+
+// AddVersions returns ErrReadOnly.
+func (ref Files) AddVersions(files ...resource.File) error {
+	return ErrReadOnly{Op: "add file versions"}
+}
+
+// AddViewData returns ErrReadOnly.
+func (ref Files) AddViewData(entries ...fileview.Data) error {
+	return ErrReadOnly{Op: "add file view data"}
+}
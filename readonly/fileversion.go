@@ -0,0 +1,33 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ fileversion.Reference = (*FileVersion)(nil)
+
+// FileVersion is a read-only file version reference.
+type FileVersion struct {
+	ref fileversion.Reference
+}
+
+// File returns the ID of the file.
+func (ref FileVersion) File() resource.ID {
+	return ref.ref.File()
+}
+
+// Version returns the version number of the file.
+func (ref FileVersion) Version() resource.Version {
+	return ref.ref.Version()
+}
+
+// Create returns ErrReadOnly.
+func (ref FileVersion) Create(data resource.FileData) error {
+	return ErrReadOnly{Op: "create file version"}
+}
+
+// Data returns the data of the file version.
+func (ref FileVersion) Data() (resource.FileData, error) {
+	return ref.ref.Data()
+}
@@ -0,0 +1,23 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ fileversion.Map = (*FileVersions)(nil)
+
+// FileVersions is a read-only map of file versions.
+type FileVersions struct {
+	versions fileversion.Map
+}
+
+// List returns a list of version numbers for the file.
+func (ref FileVersions) List() (v []resource.Version, err error) {
+	return ref.versions.List()
+}
+
+// Ref returns a file version reference for the version number.
+func (ref FileVersions) Ref(v resource.Version) fileversion.Reference {
+	return FileVersion{ref: ref.versions.Ref(v)}
+}
@@ -0,0 +1,39 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ fileview.Reference = (*FileView)(nil)
+
+// FileView is a read-only file view reference.
+type FileView struct {
+	ref fileview.Reference
+}
+
+// File returns the ID of the file.
+func (ref FileView) File() resource.ID {
+	return ref.ref.File()
+}
+
+// Drive returns the ID of the drive being viewed.
+func (ref FileView) Drive() resource.ID {
+	return ref.ref.Drive()
+}
+
+// At returns the version reference of the file at a particular commit.
+func (ref FileView) At(seqNum commit.SeqNum) (fileversion.Reference, error) {
+	r, err := ref.ref.At(seqNum)
+	if err != nil {
+		return nil, err
+	}
+	return FileVersion{ref: r}, nil
+}
+
+// Add returns ErrReadOnly.
+func (ref FileView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	return ErrReadOnly{Op: "add file view"}
+}
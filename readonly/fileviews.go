@@ -0,0 +1,23 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ fileview.Map = (*FileViews)(nil)
+
+// FileViews is a read-only map of file views.
+type FileViews struct {
+	views fileview.Map
+}
+
+// List returns a list of drives with a view of the file.
+func (ref FileViews) List() (drives []resource.ID, err error) {
+	return ref.views.List()
+}
+
+// Ref returns a view of the file for a particular drive.
+func (ref FileViews) Ref(driveID resource.ID) fileview.Reference {
+	return FileView{ref: ref.views.Ref(driveID)}
+}
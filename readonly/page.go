@@ -0,0 +1,25 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/page"
+
+var _ page.Reference = (*Page)(nil)
+
+// Page is a read-only page reference.
+type Page struct {
+	ref page.Reference
+}
+
+// SeqNum returns the sequence number of the page.
+func (ref Page) SeqNum() page.SeqNum {
+	return ref.ref.SeqNum()
+}
+
+// Create returns ErrReadOnly.
+func (ref Page) Create(data page.Data) error {
+	return ErrReadOnly{Op: "create page"}
+}
+
+// Data returns the page data.
+func (ref Page) Data() (page.Data, error) {
+	return ref.ref.Data()
+}
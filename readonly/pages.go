@@ -0,0 +1,32 @@
+package readonly
+
+import "github.com/scjalliance/drivestream/page"
+
+var _ page.Sequence = (*Pages)(nil)
+
+// Pages is a read-only page sequence.
+type Pages struct {
+	seq page.Sequence
+}
+
+// Next returns the sequence number to use for the next page.
+func (ref Pages) Next() (n page.SeqNum, err error) {
+	return ref.seq.Next()
+}
+
+// Read reads a subset of pages from the sequence, starting at start.
+// Up to len(p) pages will be returned in p. The number of pages returned
+// is returned as n.
+func (ref Pages) Read(start page.SeqNum, p []page.Data) (n int, err error) {
+	return ref.seq.Read(start, p)
+}
+
+// Ref returns a page reference for the sequence number.
+func (ref Pages) Ref(seqNum page.SeqNum) page.Reference {
+	return Page{ref: ref.seq.Ref(seqNum)}
+}
+
+// Clear returns ErrReadOnly.
+func (ref Pages) Clear() error {
+	return ErrReadOnly{Op: "clear pages"}
+}
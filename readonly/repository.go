@@ -0,0 +1,55 @@
+package readonly
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.Repository = (*Repository)(nil)
+
+// Repository is a read-only drivestream repository. It should be created
+// by calling New.
+type Repository struct {
+	repo drivestream.Repository
+}
+
+// New returns a read-only view of repo.
+func New(repo drivestream.Repository) Repository {
+	return Repository{repo: repo}
+}
+
+// Type returns a string describing the type of the repository.
+func (repo Repository) Type() string {
+	return repo.repo.Type() + " (read-only)"
+}
+
+// Drives returns a drive map.
+func (repo Repository) Drives() drivestream.DriveMap {
+	return Drives{drives: repo.repo.Drives()}
+}
+
+// Drive returns a drive reference.
+func (repo Repository) Drive(driveID resource.ID) drivestream.DriveReference {
+	return Drive{ref: repo.repo.Drive(driveID)}
+}
+
+// Files returns a file map.
+func (repo Repository) Files() drivestream.FileMap {
+	return Files{files: repo.repo.Files()}
+}
+
+// File returns a file reference.
+func (repo Repository) File(fileID resource.ID) drivestream.FileReference {
+	return File{ref: repo.repo.File(fileID)}
+}
+
+// Unwrap returns the repository wrapped by repo.
+func (repo Repository) Unwrap() drivestream.Repository {
+	return repo.repo
+}
+
+// Batch returns an error of type ErrReadOnly, because a read-only
+// repository can't be written to.
+func (repo Repository) Batch(fn func(tx drivestream.RepositoryTx) error) error {
+	return ErrReadOnly{Op: "batch"}
+}
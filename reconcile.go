@@ -0,0 +1,146 @@
+package drivestream
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// A reconciliation describes the drift between the files observed by a
+// full collection and the state of the drive at the commit before the one
+// being built from it.
+//
+// Files that appear within the change pages of the collection are left
+// to those changes and are never considered drift. Every other file that
+// was listed with a version that differs from the drive's view of it is
+// corrected, and every file within the view that wasn't listed is
+// removed.
+type reconciliation struct {
+	corrections map[resource.ID]bool
+	removals    []resource.Change
+	drift       commit.DriftData
+}
+
+// reconcile compares the pages of the full collection col with the state
+// of the drive as of the commit before seqNum.
+//
+// The comparison only reads from the repository, so it produces the same
+// result when a partially built commit is resumed.
+func (s *Stream) reconcile(col *collection.Reader, seqNum commit.SeqNum, t time.Time) (*reconciliation, error) {
+	var (
+		previous  = seqNum - 1
+		observed  = make(map[resource.ID]bool)
+		explained = make(map[resource.ID]bool)
+		r         = &reconciliation{corrections: make(map[resource.ID]bool)}
+	)
+
+	for pageNum := page.SeqNum(0); pageNum < col.NextPage(); pageNum++ {
+		pg, err := col.Page(pageNum)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range pg.Changes {
+			if change.Type != resource.TypeFile {
+				continue
+			}
+			switch pg.Type {
+			case page.FileList:
+				observed[change.File.ID] = true
+			case page.ChangeList:
+				explained[change.File.ID] = true
+			}
+		}
+	}
+
+	for pageNum := page.SeqNum(0); pageNum < col.NextPage(); pageNum++ {
+		pg, err := col.Page(pageNum)
+		if err != nil {
+			return nil, err
+		}
+		if pg.Type != page.FileList {
+			continue
+		}
+		for _, change := range pg.Changes {
+			if change.Type != resource.TypeFile || explained[change.File.ID] {
+				continue
+			}
+			version, err := s.viewedVersion(s.repo, change.File.ID, previous)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case version < 0:
+				r.drift.Added++
+			case version != change.File.Version:
+				r.drift.Changed++
+			default:
+				continue
+			}
+			r.corrections[change.File.ID] = true
+		}
+	}
+
+	files, err := s.repo.Files().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range files {
+		if observed[id] || explained[id] {
+			continue
+		}
+		version, err := s.viewedVersion(s.repo, id, previous)
+		if err != nil {
+			return nil, err
+		}
+		if version < 0 {
+			continue
+		}
+		data, err := s.repo.Files().Ref(id).Version(version).Data()
+		if err != nil {
+			return nil, err
+		}
+		r.removals = append(r.removals, resource.Change{
+			Type:    resource.TypeFile,
+			Time:    t,
+			Removed: true,
+			File: resource.File{
+				ID:       id,
+				Version:  version,
+				FileData: data,
+			},
+		})
+		r.drift.Removed++
+	}
+
+	return r, nil
+}
+
+// Corrections returns the members of changes that correct drift.
+func (r *reconciliation) Corrections(changes []resource.Change) []resource.Change {
+	var corrections []resource.Change
+	for _, change := range changes {
+		if change.Type == resource.TypeFile && r.corrections[change.File.ID] {
+			corrections = append(corrections, change)
+		}
+	}
+	return corrections
+}
+
+// viewedVersion returns the version of a file within the stream's drive
+// at seqNum, as seen by repo. If the file isn't present at seqNum -1 is
+// returned.
+func (s *Stream) viewedVersion(repo RepositoryTx, file resource.ID, seqNum commit.SeqNum) (resource.Version, error) {
+	ref, err := repo.Files().Ref(file).View(s.drive).At(seqNum)
+	switch err.(type) {
+	case nil:
+		return ref.Version(), nil
+	case fileview.NotFound:
+		return -1, nil
+	default:
+		return 0, err
+	}
+}
@@ -0,0 +1,64 @@
+package remoterepo
+
+import (
+	"encoding/json"
+
+	"github.com/scjalliance/drivestream"
+)
+
+// batchMethods are the methods that a client buffers within a batch. The
+// buffered calls are sent to the server together in a single batch.commit
+// call, which applies them within one transaction of the server's
+// repository. None of these methods return a result.
+var batchMethods = map[string]bool{
+	"collection.create":       true,
+	"collection.state.create": true,
+	"pages.clear":             true,
+	"page.create":             true,
+	"commit.create":           true,
+	"commit.setdrift":         true,
+	"commit.state.create":     true,
+	"commit.files.add":        true,
+	"commit.tree.add":         true,
+	"driveversion.create":     true,
+	"driveview.add":           true,
+	"driveactors.add":         true,
+	"consumer.set":            true,
+	"consumer.delete":         true,
+	"files.addversions":       true,
+	"files.addviewdata":       true,
+	"fileversion.create":      true,
+	"fileview.add":            true,
+}
+
+// unbatchableMethods are the methods that modify a repository but can't be
+// buffered within a batch, because they return a result.
+var unbatchableMethods = map[string]bool{
+	"collection.prune": true,
+}
+
+// commit applies the calls of a batch, which are held in the arguments of
+// c, within a single transaction of the server's repository. The
+// transaction is only held open while the calls are applied, so a client
+// that stalls can't block other writers.
+func (s *Server) commit(c call) error {
+	var calls []call
+	if len(c.Args) > 0 {
+		if err := json.Unmarshal(c.Args, &calls); err != nil {
+			return BadArguments{Method: c.Method}
+		}
+	}
+	for _, bc := range calls {
+		if !batchMethods[bc.Method] {
+			return NotBatchable{Method: bc.Method}
+		}
+	}
+	return s.repo.Batch(func(tx drivestream.RepositoryTx) error {
+		for _, bc := range calls {
+			if _, err := dispatch(tx, bc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
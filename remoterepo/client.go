@@ -0,0 +1,178 @@
+package remoterepo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var _ drivestream.Repository = (*Client)(nil)
+
+// Client is a drivestream repository that sends each call to a remote
+// repository served by a Server. It should be created by calling
+// NewClient.
+type Client struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewClient returns a client for the server at baseURL.
+func NewClient(baseURL string, options ...ClientOption) *Client {
+	c := &Client{
+		url:    strings.TrimSuffix(baseURL, "/") + CallPath,
+		client: http.DefaultClient,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Type returns a string describing the type of the repository.
+func (c *Client) Type() string {
+	var t string
+	if err := c.rpc().call("repository.type", target{}, nil, &t); err != nil {
+		return "remote"
+	}
+	return "remote " + t
+}
+
+// Drives returns a drive map.
+func (c *Client) Drives() drivestream.DriveMap {
+	return Drives{rpc: c.rpc()}
+}
+
+// Drive returns a drive reference.
+func (c *Client) Drive(driveID resource.ID) drivestream.DriveReference {
+	return Drive{rpc: c.rpc(), drive: driveID}
+}
+
+// Files returns a file map.
+func (c *Client) Files() drivestream.FileMap {
+	return Files{rpc: c.rpc()}
+}
+
+// File returns a file reference.
+func (c *Client) File(fileID resource.ID) drivestream.FileReference {
+	return File{rpc: c.rpc(), file: fileID}
+}
+
+// Batch calls fn with a transaction of the remote repository. The writes
+// made within the transaction are buffered by the client. When fn returns
+// nil they are sent to the server in a single call, which applies them
+// within one transaction of the remote repository. When fn returns an
+// error they are discarded.
+//
+// Reads made within the transaction are sent to the server immediately,
+// so they don't observe the transaction's own writes. Errors caused by
+// buffered writes, such as collection.OutOfOrder, are returned by Batch.
+// Collection.Prune can't be called within a batch.
+func (c *Client) Batch(fn func(tx drivestream.RepositoryTx) error) error {
+	var writes []call
+	tx := Tx{rpc: rpc{client: c, writes: &writes}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return c.rpc().call("batch.commit", target{}, writes, nil)
+}
+
+// rpc returns an rpc for calls made outside of a batch.
+func (c *Client) rpc() rpc {
+	return rpc{client: c}
+}
+
+// rpc sends calls to the server of a client. Within a batch, calls that
+// modify the repository are buffered in writes instead.
+type rpc struct {
+	client *Client
+	writes *[]call
+}
+
+// call invokes method on the reference identified by t. The arguments are
+// encoded from args, and the result of the call is decoded into result
+// unless it is nil.
+func (r rpc) call(method string, t target, args, result interface{}) error {
+	req := call{Method: method, Target: t}
+	if args != nil {
+		data, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		req.Args = data
+	}
+	if r.writes != nil {
+		switch {
+		case batchMethods[method]:
+			*r.writes = append(*r.writes, req)
+			return nil
+		case unbatchableMethods[method]:
+			return NotBatchable{Method: method}
+		}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, r.client.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if r.client.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.client.token)
+	}
+
+	resp, err := r.client.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return BadStatus{Method: method, Status: resp.Status}
+	}
+
+	var rep reply
+	if err := json.NewDecoder(resp.Body).Decode(&rep); err != nil {
+		return err
+	}
+	if result != nil && rep.Result != nil {
+		if err := json.Unmarshal(rep.Result, result); err != nil {
+			return err
+		}
+	}
+	return decodeError(rep.Error)
+}
+
+var _ drivestream.RepositoryTx = (*Tx)(nil)
+
+// Tx provides access to a remote repository within a batch.
+type Tx struct {
+	rpc rpc
+}
+
+// Drives returns a drive map.
+func (tx Tx) Drives() drivestream.DriveMap {
+	return Drives{rpc: tx.rpc}
+}
+
+// Drive returns a drive reference.
+func (tx Tx) Drive(driveID resource.ID) drivestream.DriveReference {
+	return Drive{rpc: tx.rpc, drive: driveID}
+}
+
+// Files returns a file map.
+func (tx Tx) Files() drivestream.FileMap {
+	return Files{rpc: tx.rpc}
+}
+
+// File returns a file reference.
+func (tx Tx) File(fileID resource.ID) drivestream.FileReference {
+	return File{rpc: tx.rpc, file: fileID}
+}
@@ -0,0 +1,236 @@
+package remoterepo
+
+import (
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ collection.Sequence       = (*Collections)(nil)
+	_ collection.Reference      = (*Collection)(nil)
+	_ collection.StateSequence  = (*CollectionStates)(nil)
+	_ collection.StateReference = (*CollectionState)(nil)
+	_ page.Sequence             = (*Pages)(nil)
+	_ page.Reference            = (*Page)(nil)
+)
+
+// Collections is a collection sequence within a remote repository.
+type Collections struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// Next returns the sequence number to use for the next collection.
+func (ref Collections) Next() (n collection.SeqNum, err error) {
+	err = ref.rpc.call("collections.next", target{Drive: ref.drive}, nil, &n)
+	return n, err
+}
+
+// Read reads collection data for a range of collections starting at the
+// given sequence number. Up to len(p) entries will be returned in p.
+// The number of entries is returned as n.
+func (ref Collections) Read(start collection.SeqNum, p []collection.Data) (n int, err error) {
+	var data []collection.Data
+	err = ref.rpc.call("collections.read", target{Drive: ref.drive}, readArgs{Start: int64(start), Count: len(p)}, &data)
+	return copy(p, data), err
+}
+
+// Ref returns a collection reference for the sequence number.
+func (ref Collections) Ref(seqNum collection.SeqNum) collection.Reference {
+	return Collection{rpc: ref.rpc, drive: ref.drive, collection: seqNum}
+}
+
+// Collection is a collection reference within a remote repository.
+type Collection struct {
+	rpc        rpc
+	drive      resource.ID
+	collection collection.SeqNum
+}
+
+// Drive returns the drive ID of the collection.
+func (ref Collection) Drive() resource.ID {
+	return ref.drive
+}
+
+// SeqNum returns the sequence number of the collection.
+func (ref Collection) SeqNum() collection.SeqNum {
+	return ref.collection
+}
+
+// Exists returns true if the collection exists.
+func (ref Collection) Exists() (exists bool, err error) {
+	err = ref.rpc.call("collection.exists", ref.target(), nil, &exists)
+	return exists, err
+}
+
+// Create creates a new collection with the given sequence number and data.
+// If a collection already exists with the sequence number an error will
+// be returned.
+func (ref Collection) Create(data collection.Data) error {
+	return ref.rpc.call("collection.create", ref.target(), data, nil)
+}
+
+// Data returns information about the collection.
+func (ref Collection) Data() (data collection.Data, err error) {
+	err = ref.rpc.call("collection.data", ref.target(), nil, &data)
+	return data, err
+}
+
+// States returns the state sequence for the collection.
+func (ref Collection) States() collection.StateSequence {
+	return CollectionStates{rpc: ref.rpc, drive: ref.drive, collection: ref.collection}
+}
+
+// State returns a state reference.
+func (ref Collection) State(stateNum collection.StateNum) collection.StateReference {
+	return CollectionState{rpc: ref.rpc, drive: ref.drive, collection: ref.collection, state: stateNum}
+}
+
+// Pages returns the page sequence for the collection.
+func (ref Collection) Pages() page.Sequence {
+	return Pages{rpc: ref.rpc, drive: ref.drive, collection: ref.collection}
+}
+
+// Page returns a page reference.
+func (ref Collection) Page(seqNum page.SeqNum) page.Reference {
+	return Page{rpc: ref.rpc, drive: ref.drive, collection: ref.collection, page: seqNum}
+}
+
+// Prune discards the changes held by the pages of the collection and
+// returns a summary of what was discarded.
+func (ref Collection) Prune() (data collection.PruneData, err error) {
+	err = ref.rpc.call("collection.prune", ref.target(), nil, &data)
+	return data, err
+}
+
+// Pruned returns a summary of the changes that were discarded when the
+// collection was pruned.
+func (ref Collection) Pruned() (data collection.PruneData, err error) {
+	err = ref.rpc.call("collection.pruned", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref Collection) target() target {
+	return target{Drive: ref.drive, Collection: ref.collection}
+}
+
+// CollectionStates is a collection state sequence within a remote
+// repository.
+type CollectionStates struct {
+	rpc        rpc
+	drive      resource.ID
+	collection collection.SeqNum
+}
+
+// Next returns the state number to use for the next state.
+func (ref CollectionStates) Next() (n collection.StateNum, err error) {
+	err = ref.rpc.call("collection.states.next", target{Drive: ref.drive, Collection: ref.collection}, nil, &n)
+	return n, err
+}
+
+// Read reads collection states starting at the given state number. Up to
+// len(p) entries will be returned in p. The number of entries is returned
+// as n.
+func (ref CollectionStates) Read(start collection.StateNum, p []collection.State) (n int, err error) {
+	var states []collection.State
+	err = ref.rpc.call("collection.states.read", target{Drive: ref.drive, Collection: ref.collection}, readArgs{Start: int64(start), Count: len(p)}, &states)
+	return copy(p, states), err
+}
+
+// Ref returns a collection state reference for the state number.
+func (ref CollectionStates) Ref(stateNum collection.StateNum) collection.StateReference {
+	return CollectionState{rpc: ref.rpc, drive: ref.drive, collection: ref.collection, state: stateNum}
+}
+
+// CollectionState is a collection state reference within a remote
+// repository.
+type CollectionState struct {
+	rpc        rpc
+	drive      resource.ID
+	collection collection.SeqNum
+	state      collection.StateNum
+}
+
+// StateNum returns the state number of the reference.
+func (ref CollectionState) StateNum() collection.StateNum {
+	return ref.state
+}
+
+// Create creates a new collection state with the given state number and
+// data. If a state already exists with the state number an error will be
+// returned.
+func (ref CollectionState) Create(data collection.State) error {
+	return ref.rpc.call("collection.state.create", ref.target(), data, nil)
+}
+
+// Data returns the collection state.
+func (ref CollectionState) Data() (data collection.State, err error) {
+	err = ref.rpc.call("collection.state.data", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref CollectionState) target() target {
+	return target{Drive: ref.drive, Collection: ref.collection, State: int64(ref.state)}
+}
+
+// Pages is a page sequence within a remote repository.
+type Pages struct {
+	rpc        rpc
+	drive      resource.ID
+	collection collection.SeqNum
+}
+
+// Next returns the sequence number to use for the next page.
+func (ref Pages) Next() (n page.SeqNum, err error) {
+	err = ref.rpc.call("pages.next", target{Drive: ref.drive, Collection: ref.collection}, nil, &n)
+	return n, err
+}
+
+// Read reads page data for a range of pages starting at the given
+// sequence number. Up to len(p) entries will be returned in p. The number
+// of entries is returned as n.
+func (ref Pages) Read(start page.SeqNum, p []page.Data) (n int, err error) {
+	var data []page.Data
+	err = ref.rpc.call("pages.read", target{Drive: ref.drive, Collection: ref.collection}, readArgs{Start: int64(start), Count: len(p)}, &data)
+	return copy(p, data), err
+}
+
+// Clear removes all pages from the sequence.
+func (ref Pages) Clear() error {
+	return ref.rpc.call("pages.clear", target{Drive: ref.drive, Collection: ref.collection}, nil, nil)
+}
+
+// Ref returns a page reference for the sequence number.
+func (ref Pages) Ref(seqNum page.SeqNum) page.Reference {
+	return Page{rpc: ref.rpc, drive: ref.drive, collection: ref.collection, page: seqNum}
+}
+
+// Page is a page reference within a remote repository.
+type Page struct {
+	rpc        rpc
+	drive      resource.ID
+	collection collection.SeqNum
+	page       page.SeqNum
+}
+
+// SeqNum returns the sequence number of the page.
+func (ref Page) SeqNum() page.SeqNum {
+	return ref.page
+}
+
+// Create creates a new page with the given sequence number and data. If a
+// page already exists with the sequence number an error will be returned.
+func (ref Page) Create(data page.Data) error {
+	return ref.rpc.call("page.create", ref.target(), data, nil)
+}
+
+// Data returns the page data.
+func (ref Page) Data() (data page.Data, err error) {
+	err = ref.rpc.call("page.data", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref Page) target() target {
+	return target{Drive: ref.drive, Collection: ref.collection, Page: ref.page}
+}
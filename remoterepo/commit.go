@@ -0,0 +1,232 @@
+package remoterepo
+
+import (
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ commit.Sequence       = (*Commits)(nil)
+	_ commit.Reference      = (*Commit)(nil)
+	_ commit.StateSequence  = (*CommitStates)(nil)
+	_ commit.StateReference = (*CommitState)(nil)
+	_ commit.FileMap        = (*CommitFiles)(nil)
+	_ commit.TreeMap        = (*CommitTree)(nil)
+	_ commit.TreeGroup      = (*CommitTreeGroup)(nil)
+)
+
+// Commits is a commit sequence within a remote repository.
+type Commits struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// Next returns the sequence number to use for the next commit.
+func (ref Commits) Next() (n commit.SeqNum, err error) {
+	err = ref.rpc.call("commits.next", target{Drive: ref.drive}, nil, &n)
+	return n, err
+}
+
+// Read reads commit data for a range of commits starting at the given
+// sequence number. Up to len(p) entries will be returned in p. The number
+// of entries is returned as n.
+func (ref Commits) Read(start commit.SeqNum, p []commit.Data) (n int, err error) {
+	var data []commit.Data
+	err = ref.rpc.call("commits.read", target{Drive: ref.drive}, readArgs{Start: int64(start), Count: len(p)}, &data)
+	return copy(p, data), err
+}
+
+// Ref returns a commit reference for the sequence number.
+func (ref Commits) Ref(seqNum commit.SeqNum) commit.Reference {
+	return Commit{rpc: ref.rpc, drive: ref.drive, commit: seqNum}
+}
+
+// Commit is a commit reference within a remote repository.
+type Commit struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+}
+
+// Drive returns the drive ID of the commit.
+func (ref Commit) Drive() resource.ID {
+	return ref.drive
+}
+
+// SeqNum returns the sequence number of the commit.
+func (ref Commit) SeqNum() commit.SeqNum {
+	return ref.commit
+}
+
+// Exists returns true if the commit exists.
+func (ref Commit) Exists() (exists bool, err error) {
+	err = ref.rpc.call("commit.exists", ref.target(), nil, &exists)
+	return exists, err
+}
+
+// Create creates the commit with the given data. If a commit already
+// exists with the sequence number an error will be returned.
+func (ref Commit) Create(data commit.Data) error {
+	return ref.rpc.call("commit.create", ref.target(), data, nil)
+}
+
+// Data returns information about the commit.
+func (ref Commit) Data() (data commit.Data, err error) {
+	err = ref.rpc.call("commit.data", ref.target(), nil, &data)
+	return data, err
+}
+
+// States returns the state sequence for the commit.
+func (ref Commit) States() commit.StateSequence {
+	return CommitStates{rpc: ref.rpc, drive: ref.drive, commit: ref.commit}
+}
+
+// State returns a state reference.
+func (ref Commit) State(stateNum commit.StateNum) commit.StateReference {
+	return CommitState{rpc: ref.rpc, drive: ref.drive, commit: ref.commit, state: stateNum}
+}
+
+// Files returns the map of file changes for the commit.
+func (ref Commit) Files() commit.FileMap {
+	return CommitFiles{rpc: ref.rpc, drive: ref.drive, commit: ref.commit}
+}
+
+// Tree returns the map of tree changes for the commit.
+func (ref Commit) Tree() commit.TreeMap {
+	return CommitTree{rpc: ref.rpc, drive: ref.drive, commit: ref.commit}
+}
+
+// SetDrift records the drift that was corrected by the commit when it
+// reconciled a full collection.
+func (ref Commit) SetDrift(data commit.DriftData) error {
+	return ref.rpc.call("commit.setdrift", ref.target(), data, nil)
+}
+
+// Drift returns the drift that was corrected by the commit.
+func (ref Commit) Drift() (data commit.DriftData, err error) {
+	err = ref.rpc.call("commit.drift", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref Commit) target() target {
+	return target{Drive: ref.drive, Commit: ref.commit}
+}
+
+// CommitStates is a commit state sequence within a remote repository.
+type CommitStates struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+}
+
+// Next returns the state number to use for the next state.
+func (ref CommitStates) Next() (n commit.StateNum, err error) {
+	err = ref.rpc.call("commit.states.next", target{Drive: ref.drive, Commit: ref.commit}, nil, &n)
+	return n, err
+}
+
+// Read reads commit states starting at the given state number. Up to
+// len(p) entries will be returned in p. The number of entries is returned
+// as n.
+func (ref CommitStates) Read(start commit.StateNum, p []commit.State) (n int, err error) {
+	var states []commit.State
+	err = ref.rpc.call("commit.states.read", target{Drive: ref.drive, Commit: ref.commit}, readArgs{Start: int64(start), Count: len(p)}, &states)
+	return copy(p, states), err
+}
+
+// Ref returns a commit state reference for the state number.
+func (ref CommitStates) Ref(stateNum commit.StateNum) commit.StateReference {
+	return CommitState{rpc: ref.rpc, drive: ref.drive, commit: ref.commit, state: stateNum}
+}
+
+// CommitState is a commit state reference within a remote repository.
+type CommitState struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+	state  commit.StateNum
+}
+
+// StateNum returns the state number of the reference.
+func (ref CommitState) StateNum() commit.StateNum {
+	return ref.state
+}
+
+// Create creates a new commit state with the given state number and data.
+// If a state already exists with the state number an error will be
+// returned.
+func (ref CommitState) Create(data commit.State) error {
+	return ref.rpc.call("commit.state.create", ref.target(), data, nil)
+}
+
+// Data returns the commit state.
+func (ref CommitState) Data() (data commit.State, err error) {
+	err = ref.rpc.call("commit.state.data", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref CommitState) target() target {
+	return target{Drive: ref.drive, Commit: ref.commit, State: int64(ref.state)}
+}
+
+// CommitFiles is a commit file map within a remote repository.
+type CommitFiles struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+}
+
+// Read returns the set of file changes for the commit, in unspecified
+// order.
+func (ref CommitFiles) Read() (changes []commit.FileChange, err error) {
+	err = ref.rpc.call("commit.files.read", target{Drive: ref.drive, Commit: ref.commit}, nil, &changes)
+	return changes, err
+}
+
+// Add adds the given file changes to the map.
+func (ref CommitFiles) Add(changes ...commit.FileChange) error {
+	return ref.rpc.call("commit.files.add", target{Drive: ref.drive, Commit: ref.commit}, changes, nil)
+}
+
+// CommitTree is a commit tree map within a remote repository.
+type CommitTree struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+}
+
+// Parents returns a list of parent IDs contained within the map.
+func (ref CommitTree) Parents() (parents []resource.ID, err error) {
+	err = ref.rpc.call("commit.tree.parents", target{Drive: ref.drive, Commit: ref.commit}, nil, &parents)
+	return parents, err
+}
+
+// Group returns a reference to a group of changes sharing parent.
+func (ref CommitTree) Group(parent resource.ID) commit.TreeGroup {
+	return CommitTreeGroup{rpc: ref.rpc, drive: ref.drive, commit: ref.commit, parent: parent}
+}
+
+// Add adds the given tree changes to the map, grouped by parent.
+func (ref CommitTree) Add(changes ...commit.TreeChange) error {
+	return ref.rpc.call("commit.tree.add", target{Drive: ref.drive, Commit: ref.commit}, changes, nil)
+}
+
+// CommitTreeGroup is a group of tree changes sharing a common parent
+// within a remote repository.
+type CommitTreeGroup struct {
+	rpc    rpc
+	drive  resource.ID
+	commit commit.SeqNum
+	parent resource.ID
+}
+
+// Parent returns the parent resource ID of the group.
+func (ref CommitTreeGroup) Parent() resource.ID {
+	return ref.parent
+}
+
+// Changes returns the set of changes contained in the group.
+func (ref CommitTreeGroup) Changes() (changes []commit.TreeChange, err error) {
+	err = ref.rpc.call("commit.treegroup.changes", target{Drive: ref.drive, Commit: ref.commit, Parent: ref.parent}, nil, &changes)
+	return changes, err
+}
@@ -0,0 +1,74 @@
+package remoterepo
+
+import (
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ consumer.Map       = (*Consumers)(nil)
+	_ consumer.Reference = (*Consumer)(nil)
+)
+
+// Consumers is a map of drive consumers within a remote repository.
+type Consumers struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// List returns the names of all consumers within the map.
+func (ref Consumers) List() (names []string, err error) {
+	err = ref.rpc.call("consumers.list", target{Drive: ref.drive}, nil, &names)
+	return names, err
+}
+
+// Ref returns a reference to the named consumer.
+func (ref Consumers) Ref(name string) consumer.Reference {
+	return Consumer{rpc: ref.rpc, drive: ref.drive, name: name}
+}
+
+// Consumer is a consumer reference within a remote repository.
+type Consumer struct {
+	rpc   rpc
+	drive resource.ID
+	name  string
+}
+
+// Drive returns the ID of the drive.
+func (ref Consumer) Drive() resource.ID {
+	return ref.drive
+}
+
+// Name returns the name of the consumer.
+func (ref Consumer) Name() string {
+	return ref.name
+}
+
+// Exists returns true if the consumer has acknowledged a commit.
+func (ref Consumer) Exists() (exists bool, err error) {
+	err = ref.rpc.call("consumer.exists", ref.target(), nil, &exists)
+	return exists, err
+}
+
+// Data returns the progress of the consumer. If the consumer hasn't
+// acknowledged a commit a NotFound error is returned.
+func (ref Consumer) Data() (data consumer.Data, err error) {
+	err = ref.rpc.call("consumer.data", ref.target(), nil, &data)
+	return data, err
+}
+
+// Set records the progress of the consumer, replacing any progress
+// recorded before.
+func (ref Consumer) Set(data consumer.Data) error {
+	return ref.rpc.call("consumer.set", ref.target(), data, nil)
+}
+
+// Delete removes the consumer, so that it starts again with the first
+// commit.
+func (ref Consumer) Delete() error {
+	return ref.rpc.call("consumer.delete", ref.target(), nil, nil)
+}
+
+func (ref Consumer) target() target {
+	return target{Drive: ref.drive, Consumer: ref.name}
+}
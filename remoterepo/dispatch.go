@@ -0,0 +1,272 @@
+package remoterepo
+
+import (
+	"encoding/json"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// dispatch invokes the method of c on the reference it targets within
+// repo, and returns the result.
+func dispatch(repo drivestream.RepositoryTx, c call) (result interface{}, err error) {
+	t := c.Target
+	args := func(v interface{}) error {
+		if len(c.Args) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(c.Args, v); err != nil {
+			return BadArguments{Method: c.Method}
+		}
+		if a, ok := v.(*readArgs); ok && a.Count < 0 {
+			return BadArguments{Method: c.Method}
+		}
+		return nil
+	}
+
+	switch c.Method {
+	// Drives
+	case "drives.list":
+		return repo.Drives().List()
+	case "drive.exists":
+		return repo.Drive(t.Drive).Exists()
+	case "drive.stats":
+		return repo.Drive(t.Drive).Stats()
+
+	// Collections
+	case "collections.next":
+		return repo.Drive(t.Drive).Collections().Next()
+	case "collections.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]collection.Data, a.Count)
+		n, err := repo.Drive(t.Drive).Collections().Read(collection.SeqNum(a.Start), p)
+		return p[:n], err
+	case "collection.exists":
+		return repo.Drive(t.Drive).Collection(t.Collection).Exists()
+	case "collection.create":
+		var data collection.Data
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Collection(t.Collection).Create(data)
+	case "collection.data":
+		return repo.Drive(t.Drive).Collection(t.Collection).Data()
+	case "collection.prune":
+		return repo.Drive(t.Drive).Collection(t.Collection).Prune()
+	case "collection.pruned":
+		return repo.Drive(t.Drive).Collection(t.Collection).Pruned()
+	case "collection.states.next":
+		return repo.Drive(t.Drive).Collection(t.Collection).States().Next()
+	case "collection.states.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]collection.State, a.Count)
+		n, err := repo.Drive(t.Drive).Collection(t.Collection).States().Read(collection.StateNum(a.Start), p)
+		return p[:n], err
+	case "collection.state.create":
+		var state collection.State
+		if err := args(&state); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Collection(t.Collection).State(collection.StateNum(t.State)).Create(state)
+	case "collection.state.data":
+		return repo.Drive(t.Drive).Collection(t.Collection).State(collection.StateNum(t.State)).Data()
+
+	// Pages
+	case "pages.next":
+		return repo.Drive(t.Drive).Collection(t.Collection).Pages().Next()
+	case "pages.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]page.Data, a.Count)
+		n, err := repo.Drive(t.Drive).Collection(t.Collection).Pages().Read(page.SeqNum(a.Start), p)
+		return p[:n], err
+	case "pages.clear":
+		return nil, repo.Drive(t.Drive).Collection(t.Collection).Pages().Clear()
+	case "page.create":
+		var data page.Data
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Collection(t.Collection).Page(t.Page).Create(data)
+	case "page.data":
+		return repo.Drive(t.Drive).Collection(t.Collection).Page(t.Page).Data()
+
+	// Commits
+	case "commits.next":
+		return repo.Drive(t.Drive).Commits().Next()
+	case "commits.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]commit.Data, a.Count)
+		n, err := repo.Drive(t.Drive).Commits().Read(commit.SeqNum(a.Start), p)
+		return p[:n], err
+	case "commit.exists":
+		return repo.Drive(t.Drive).Commit(t.Commit).Exists()
+	case "commit.create":
+		var data commit.Data
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Commit(t.Commit).Create(data)
+	case "commit.data":
+		return repo.Drive(t.Drive).Commit(t.Commit).Data()
+	case "commit.setdrift":
+		var data commit.DriftData
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Commit(t.Commit).SetDrift(data)
+	case "commit.drift":
+		return repo.Drive(t.Drive).Commit(t.Commit).Drift()
+	case "commit.states.next":
+		return repo.Drive(t.Drive).Commit(t.Commit).States().Next()
+	case "commit.states.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]commit.State, a.Count)
+		n, err := repo.Drive(t.Drive).Commit(t.Commit).States().Read(commit.StateNum(a.Start), p)
+		return p[:n], err
+	case "commit.state.create":
+		var state commit.State
+		if err := args(&state); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Commit(t.Commit).State(commit.StateNum(t.State)).Create(state)
+	case "commit.state.data":
+		return repo.Drive(t.Drive).Commit(t.Commit).State(commit.StateNum(t.State)).Data()
+	case "commit.files.read":
+		return repo.Drive(t.Drive).Commit(t.Commit).Files().Read()
+	case "commit.files.add":
+		var changes []commit.FileChange
+		if err := args(&changes); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Commit(t.Commit).Files().Add(changes...)
+	case "commit.tree.parents":
+		return repo.Drive(t.Drive).Commit(t.Commit).Tree().Parents()
+	case "commit.tree.add":
+		var changes []commit.TreeChange
+		if err := args(&changes); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Commit(t.Commit).Tree().Add(changes...)
+	case "commit.treegroup.changes":
+		return repo.Drive(t.Drive).Commit(t.Commit).Tree().Group(t.Parent).Changes()
+
+	// Drive versions, views and actors
+	case "driveversions.next":
+		return repo.Drive(t.Drive).Versions().Next()
+	case "driveversions.read":
+		var a readArgs
+		if err := args(&a); err != nil {
+			return nil, err
+		}
+		p := make([]resource.DriveData, a.Count)
+		n, err := repo.Drive(t.Drive).Versions().Read(resource.Version(a.Start), p)
+		return p[:n], err
+	case "driveversion.create":
+		var data resource.DriveData
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Version(t.Version).Create(data)
+	case "driveversion.data":
+		return repo.Drive(t.Drive).Version(t.Version).Data()
+	case "driveview.at":
+		ref, err := repo.Drive(t.Drive).View().At(t.Commit)
+		if err != nil {
+			return nil, err
+		}
+		return ref.Version(), nil
+	case "driveview.add":
+		return nil, repo.Drive(t.Drive).View().Add(t.Commit, t.Version)
+	case "driveview.entries":
+		return repo.Drive(t.Drive).View().Entries()
+	case "driveactors.list":
+		return repo.Drive(t.Drive).Actors().List()
+	case "driveactors.add":
+		var entries []driveactor.Entry
+		if err := args(&entries); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Actors().Add(entries...)
+	case "driveactor.read":
+		var r timeRange
+		if err := args(&r); err != nil {
+			return nil, err
+		}
+		return repo.Drive(t.Drive).Actors().Ref(t.Email).Read(r.Since, r.Until)
+	case "consumers.list":
+		return repo.Drive(t.Drive).Consumers().List()
+	case "consumer.exists":
+		return repo.Drive(t.Drive).Consumers().Ref(t.Consumer).Exists()
+	case "consumer.data":
+		return repo.Drive(t.Drive).Consumers().Ref(t.Consumer).Data()
+	case "consumer.set":
+		var data consumer.Data
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.Drive(t.Drive).Consumers().Ref(t.Consumer).Set(data)
+	case "consumer.delete":
+		return nil, repo.Drive(t.Drive).Consumers().Ref(t.Consumer).Delete()
+
+	// Files
+	case "files.list":
+		return repo.Files().List()
+	case "files.addversions":
+		var files []resource.File
+		if err := args(&files); err != nil {
+			return nil, err
+		}
+		return nil, repo.Files().AddVersions(files...)
+	case "files.addviewdata":
+		var entries []fileview.Data
+		if err := args(&entries); err != nil {
+			return nil, err
+		}
+		return nil, repo.Files().AddViewData(entries...)
+	case "file.exists":
+		return repo.File(t.File).Exists()
+	case "fileversions.list":
+		return repo.File(t.File).Versions().List()
+	case "fileversion.create":
+		var data resource.FileData
+		if err := args(&data); err != nil {
+			return nil, err
+		}
+		return nil, repo.File(t.File).Version(t.Version).Create(data)
+	case "fileversion.data":
+		return repo.File(t.File).Version(t.Version).Data()
+	case "fileviews.list":
+		return repo.File(t.File).Views().List()
+	case "fileview.at":
+		ref, err := repo.File(t.File).View(t.Drive).At(t.Commit)
+		if err != nil {
+			return nil, err
+		}
+		return ref.Version(), nil
+	case "fileview.add":
+		return nil, repo.File(t.File).View(t.Drive).Add(t.Commit, t.Version)
+	}
+
+	return nil, UnknownMethod{Method: c.Method}
+}
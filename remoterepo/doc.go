@@ -0,0 +1,20 @@
+// Package remoterepo provides access to a drivestream repository on
+// another host.
+//
+// A Server exposes any drivestream.Repository over HTTP, including the
+// calls that modify it. A Client implements drivestream.Repository by
+// sending each call to a server. This allows collectors to run on one
+// host while the database they write to lives on another.
+//
+// Each call is sent as a JSON document in the body of a POST request. The
+// errors returned by the remote repository keep their types across the
+// wire, so that callers can continue to examine them with type switches.
+// The error types of the drivestream packages are registered by default.
+// Other error types can be registered with RegisterError. Errors of
+// unregistered types are returned as a RemoteError.
+//
+// The writes made within a batch are buffered by the client and sent to
+// the server in a single call when the batch succeeds. The server applies
+// them within a single transaction of the remote repository, so it never
+// holds a transaction open while waiting on a client.
+package remoterepo
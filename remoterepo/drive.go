@@ -0,0 +1,254 @@
+package remoterepo
+
+import (
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ drivestream.DriveMap       = (*Drives)(nil)
+	_ drivestream.DriveReference = (*Drive)(nil)
+	_ driveversion.Sequence      = (*DriveVersions)(nil)
+	_ driveversion.Reference     = (*DriveVersion)(nil)
+	_ driveview.Reference        = (*DriveView)(nil)
+	_ driveactor.Map             = (*DriveActors)(nil)
+	_ driveactor.Reference       = (*DriveActor)(nil)
+)
+
+// Drives is a map of drives within a remote repository.
+type Drives struct {
+	rpc rpc
+}
+
+// List returns a list of all drives within the map.
+func (ref Drives) List() (ids []resource.ID, err error) {
+	err = ref.rpc.call("drives.list", target{}, nil, &ids)
+	return ids, err
+}
+
+// Ref returns a drive reference.
+func (ref Drives) Ref(driveID resource.ID) drivestream.DriveReference {
+	return Drive{rpc: ref.rpc, drive: driveID}
+}
+
+// Drive is a drive reference within a remote repository.
+type Drive struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// DriveID returns the resource ID of the drive.
+func (ref Drive) DriveID() resource.ID {
+	return ref.drive
+}
+
+// Exists returns true if the drive exists.
+func (ref Drive) Exists() (exists bool, err error) {
+	err = ref.rpc.call("drive.exists", target{Drive: ref.drive}, nil, &exists)
+	return exists, err
+}
+
+// Collections returns the collection sequence for the drive.
+func (ref Drive) Collections() collection.Sequence {
+	return Collections{rpc: ref.rpc, drive: ref.drive}
+}
+
+// Collection returns a collection reference.
+func (ref Drive) Collection(seqNum collection.SeqNum) collection.Reference {
+	return Collection{rpc: ref.rpc, drive: ref.drive, collection: seqNum}
+}
+
+// Commits returns the commit sequence for the drive.
+func (ref Drive) Commits() commit.Sequence {
+	return Commits{rpc: ref.rpc, drive: ref.drive}
+}
+
+// Commit returns a commit reference.
+func (ref Drive) Commit(seqNum commit.SeqNum) commit.Reference {
+	return Commit{rpc: ref.rpc, drive: ref.drive, commit: seqNum}
+}
+
+// Versions returns the version sequence for the drive.
+func (ref Drive) Versions() driveversion.Sequence {
+	return DriveVersions{rpc: ref.rpc, drive: ref.drive}
+}
+
+// Version returns a drive version reference.
+func (ref Drive) Version(v resource.Version) driveversion.Reference {
+	return DriveVersion{rpc: ref.rpc, drive: ref.drive, version: v}
+}
+
+// View returns a view of the drive.
+func (ref Drive) View() driveview.Reference {
+	return DriveView{rpc: ref.rpc, drive: ref.drive}
+}
+
+// At returns a version reference of the drive at a particular commit.
+func (ref Drive) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	return ref.View().At(seqNum)
+}
+
+// Actors returns the map of actors that have made changes within the
+// drive.
+func (ref Drive) Actors() driveactor.Map {
+	return DriveActors{rpc: ref.rpc, drive: ref.drive}
+}
+
+// Consumers returns the map of named consumers that process the commits
+// of the drive.
+func (ref Drive) Consumers() consumer.Map {
+	return Consumers{rpc: ref.rpc, drive: ref.drive}
+}
+
+// Stats returns statistics about the drive.
+func (ref Drive) Stats() (stats drivestream.DriveStats, err error) {
+	err = ref.rpc.call("drive.stats", target{Drive: ref.drive}, nil, &stats)
+	return stats, err
+}
+
+// DriveVersions is a drive version sequence within a remote repository.
+type DriveVersions struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// Next returns the next version number in the sequence.
+func (ref DriveVersions) Next() (n resource.Version, err error) {
+	err = ref.rpc.call("driveversions.next", target{Drive: ref.drive}, nil, &n)
+	return n, err
+}
+
+// Read reads drive data for a range of drive versions starting at the
+// given version number. Up to len(p) entries will be returned in p.
+// The number of entries is returned as n.
+func (ref DriveVersions) Read(start resource.Version, p []resource.DriveData) (n int, err error) {
+	var data []resource.DriveData
+	err = ref.rpc.call("driveversions.read", target{Drive: ref.drive}, readArgs{Start: int64(start), Count: len(p)}, &data)
+	return copy(p, data), err
+}
+
+// Ref returns a drive version reference for the version number.
+func (ref DriveVersions) Ref(v resource.Version) driveversion.Reference {
+	return DriveVersion{rpc: ref.rpc, drive: ref.drive, version: v}
+}
+
+// DriveVersion is a drive version reference within a remote repository.
+type DriveVersion struct {
+	rpc     rpc
+	drive   resource.ID
+	version resource.Version
+}
+
+// Drive returns the ID of the drive.
+func (ref DriveVersion) Drive() resource.ID {
+	return ref.drive
+}
+
+// Version returns the version number of the drive.
+func (ref DriveVersion) Version() resource.Version {
+	return ref.version
+}
+
+// Create creates a new drive version with the given version number
+// and data. If a version already exists with the version number an
+// error will be returned.
+func (ref DriveVersion) Create(data resource.DriveData) error {
+	return ref.rpc.call("driveversion.create", ref.target(), data, nil)
+}
+
+// Data returns the data of the drive version.
+func (ref DriveVersion) Data() (data resource.DriveData, err error) {
+	err = ref.rpc.call("driveversion.data", ref.target(), nil, &data)
+	return data, err
+}
+
+func (ref DriveVersion) target() target {
+	return target{Drive: ref.drive, Version: ref.version}
+}
+
+// DriveView is a drive view reference within a remote repository.
+type DriveView struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// Drive returns the ID of the drive being viewed.
+func (ref DriveView) Drive() resource.ID {
+	return ref.drive
+}
+
+// At returns the version reference of the drive at a particular commit.
+func (ref DriveView) At(seqNum commit.SeqNum) (driveversion.Reference, error) {
+	var version resource.Version
+	if err := ref.rpc.call("driveview.at", target{Drive: ref.drive, Commit: seqNum}, nil, &version); err != nil {
+		return nil, err
+	}
+	return DriveVersion{rpc: ref.rpc, drive: ref.drive, version: version}, nil
+}
+
+// Add adds version as a view of the drive at the commit sequence number.
+func (ref DriveView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	return ref.rpc.call("driveview.add", target{Drive: ref.drive, Commit: seqNum, Version: version}, nil, nil)
+}
+
+// Entries returns every view of the drive in commit order.
+func (ref DriveView) Entries() (entries []driveview.Entry, err error) {
+	err = ref.rpc.call("driveview.entries", target{Drive: ref.drive}, nil, &entries)
+	return entries, err
+}
+
+// DriveActors is a map of drive actors within a remote repository.
+type DriveActors struct {
+	rpc   rpc
+	drive resource.ID
+}
+
+// List returns the email addresses of all actors within the map.
+func (ref DriveActors) List() (actors []string, err error) {
+	err = ref.rpc.call("driveactors.list", target{Drive: ref.drive}, nil, &actors)
+	return actors, err
+}
+
+// Ref returns a reference to the changes made by an actor.
+func (ref DriveActors) Ref(email string) driveactor.Reference {
+	return DriveActor{rpc: ref.rpc, drive: ref.drive, email: email}
+}
+
+// Add adds the given entries to the map in bulk, indexed by the email
+// address of each entry's actor.
+func (ref DriveActors) Add(entries ...driveactor.Entry) error {
+	return ref.rpc.call("driveactors.add", target{Drive: ref.drive}, entries, nil)
+}
+
+// DriveActor is a drive actor reference within a remote repository.
+type DriveActor struct {
+	rpc   rpc
+	drive resource.ID
+	email string
+}
+
+// Drive returns the ID of the drive.
+func (ref DriveActor) Drive() resource.ID {
+	return ref.drive
+}
+
+// Email returns the email address of the actor.
+func (ref DriveActor) Email() string {
+	return ref.email
+}
+
+// Read returns the entries for changes made by the actor at or after
+// since and before until, in chronological order. A zero value for
+// either time leaves that end of the range unbounded.
+func (ref DriveActor) Read(since, until time.Time) (entries []driveactor.Entry, err error) {
+	err = ref.rpc.call("driveactor.read", target{Drive: ref.drive, Email: ref.email}, timeRange{Since: since, Until: until}, &entries)
+	return entries, err
+}
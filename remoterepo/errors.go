@@ -0,0 +1,60 @@
+package remoterepo
+
+import "fmt"
+
+// RemoteError reports an error returned by a remote repository that is not
+// of a registered type. Its message is the message of the original error.
+type RemoteError struct {
+	Type    string
+	Message string
+}
+
+// Error returns a string representation of the error.
+func (e RemoteError) Error() string {
+	return e.Message
+}
+
+// BadStatus reports that a server responded to a call with an unexpected
+// HTTP status.
+type BadStatus struct {
+	Method string
+	Status string
+}
+
+// Error returns a string representation of the error.
+func (e BadStatus) Error() string {
+	return fmt.Sprintf("drivestream: remote %s: the server responded with status %s", e.Method, e.Status)
+}
+
+// UnknownMethod reports that a server received a call for a method it
+// doesn't recognize.
+type UnknownMethod struct {
+	Method string
+}
+
+// Error returns a string representation of the error.
+func (e UnknownMethod) Error() string {
+	return fmt.Sprintf("drivestream: remote: unknown method \"%s\"", e.Method)
+}
+
+// NotBatchable reports that a method that modifies the repository can't
+// be called within a batch.
+type NotBatchable struct {
+	Method string
+}
+
+// Error returns a string representation of the error.
+func (e NotBatchable) Error() string {
+	return fmt.Sprintf("drivestream: remote: %s can't be called within a batch", e.Method)
+}
+
+// BadArguments reports that a server received a call with arguments that
+// could not be decoded.
+type BadArguments struct {
+	Method string
+}
+
+// Error returns a string representation of the error.
+func (e BadArguments) Error() string {
+	return fmt.Sprintf("drivestream: remote %s: invalid arguments", e.Method)
+}
@@ -0,0 +1,140 @@
+package remoterepo
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/scjalliance/drivestream/codec"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/consumer"
+	"github.com/scjalliance/drivestream/driveactor"
+	"github.com/scjalliance/drivestream/driveversion"
+	"github.com/scjalliance/drivestream/driveview"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/readonly"
+)
+
+var (
+	errorTypesMutex sync.RWMutex
+	errorTypes      = make(map[string]reflect.Type)
+)
+
+func init() {
+	for _, err := range []error{
+		collection.NotFound{},
+		collection.OutOfOrder{},
+		collection.DataInvalid{},
+		collection.StateNotFound{},
+		collection.StateOutOfOrder{},
+		collection.StateInvalid{},
+		collection.StatesTruncated{},
+		collection.PageNotFound{},
+		collection.PageOutOfOrder{},
+		collection.PageDataInvalid{},
+		collection.PagesTruncated{},
+		commit.NotFound{},
+		commit.OutOfOrder{},
+		commit.DataInvalid{},
+		commit.StateNotFound{},
+		commit.StateOutOfOrder{},
+		commit.StateInvalid{},
+		commit.StatesTruncated{},
+		commit.TreeGroupNotFound{},
+		commit.TreeGroupInvalid{},
+		driveactor.InvalidData{},
+		consumer.NotFound{},
+		consumer.InvalidData{},
+		consumer.InvalidName{},
+		driveversion.NotFound{},
+		driveversion.OutOfOrder{},
+		driveversion.InvalidData{},
+		driveview.NotFound{},
+		fileversion.NotFound{},
+		fileversion.OutOfOrder{},
+		fileversion.InvalidData{},
+		fileversion.Conflict{},
+		fileview.NotFound{},
+		codec.UnknownID{},
+		codec.UnknownName{},
+		codec.KeyNotFound{},
+		codec.DecryptionFailed{},
+		readonly.ErrReadOnly{},
+		BadArguments{},
+		UnknownMethod{},
+		NotBatchable{},
+	} {
+		RegisterError(err)
+	}
+}
+
+// RegisterError registers the type of err, so that errors of that type
+// keep their type when they are returned by a remote repository. The type
+// must be a struct that can be encoded as JSON without losing
+// information. It must be registered by both the client and the server.
+func RegisterError(err error) {
+	t := reflect.TypeOf(err)
+	errorTypesMutex.Lock()
+	defer errorTypesMutex.Unlock()
+	errorTypes[errorTypeName(t)] = t
+}
+
+// errorTypeName returns the name that identifies an error type on the
+// wire.
+func errorTypeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// wireError is an error as it is sent across the wire.
+type wireError struct {
+	Type    string          `json:"type,omitempty"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// encodeError prepares err to be sent across the wire.
+func encodeError(err error) *wireError {
+	if err == nil {
+		return nil
+	}
+	name := errorTypeName(reflect.TypeOf(err))
+	e := &wireError{Type: name, Message: err.Error()}
+
+	errorTypesMutex.RLock()
+	_, registered := errorTypes[name]
+	errorTypesMutex.RUnlock()
+
+	if registered {
+		if data, err := json.Marshal(err); err == nil {
+			e.Data = data
+		}
+	}
+	return e
+}
+
+// decodeError returns the error that e describes. If e describes an error
+// of a registered type an error of that type is returned. Otherwise a
+// RemoteError is returned.
+func decodeError(e *wireError) error {
+	if e == nil {
+		return nil
+	}
+	errorTypesMutex.RLock()
+	t, registered := errorTypes[e.Type]
+	errorTypesMutex.RUnlock()
+
+	if registered && e.Data != nil {
+		v := reflect.New(t)
+		if err := json.Unmarshal(e.Data, v.Interface()); err == nil {
+			if err, ok := v.Elem().Interface().(error); ok {
+				return err
+			}
+		}
+	}
+	return RemoteError{Type: e.Type, Message: e.Message}
+}
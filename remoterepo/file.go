@@ -0,0 +1,176 @@
+package remoterepo
+
+import (
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+var (
+	_ drivestream.FileMap       = (*Files)(nil)
+	_ drivestream.FileReference = (*File)(nil)
+	_ fileversion.Map           = (*FileVersions)(nil)
+	_ fileversion.Reference     = (*FileVersion)(nil)
+	_ fileview.Map              = (*FileViews)(nil)
+	_ fileview.Reference        = (*FileView)(nil)
+)
+
+// Files is a map of files within a remote repository.
+type Files struct {
+	rpc rpc
+}
+
+// List returns the list of files contained within the repository.
+func (ref Files) List() (ids []resource.ID, err error) {
+	err = ref.rpc.call("files.list", target{}, nil, &ids)
+	return ids, err
+}
+
+// Ref returns a file reference.
+func (ref Files) Ref(fileID resource.ID) drivestream.FileReference {
+	return File{rpc: ref.rpc, file: fileID}
+}
+
+// AddVersions adds file versions to the file map in bulk.
+func (ref Files) AddVersions(files ...resource.File) error {
+	return ref.rpc.call("files.addversions", target{}, files, nil)
+}
+
+// AddViewData adds view data to the file map in bulk.
+func (ref Files) AddViewData(entries ...fileview.Data) error {
+	return ref.rpc.call("files.addviewdata", target{}, entries, nil)
+}
+
+// File is a file reference within a remote repository.
+type File struct {
+	rpc  rpc
+	file resource.ID
+}
+
+// FileID returns the resource ID of the file.
+func (ref File) FileID() resource.ID {
+	return ref.file
+}
+
+// Exists returns true if the file exists.
+func (ref File) Exists() (exists bool, err error) {
+	err = ref.rpc.call("file.exists", target{File: ref.file}, nil, &exists)
+	return exists, err
+}
+
+// Versions returns the version map for the file.
+func (ref File) Versions() fileversion.Map {
+	return FileVersions{rpc: ref.rpc, file: ref.file}
+}
+
+// Version returns a file version reference.
+func (ref File) Version(v resource.Version) fileversion.Reference {
+	return FileVersion{rpc: ref.rpc, file: ref.file, version: v}
+}
+
+// Views returns the view map for the file.
+func (ref File) Views() fileview.Map {
+	return FileViews{rpc: ref.rpc, file: ref.file}
+}
+
+// View returns a view of the file for a particular drive.
+func (ref File) View(driveID resource.ID) fileview.Reference {
+	return FileView{rpc: ref.rpc, file: ref.file, drive: driveID}
+}
+
+// FileVersions is a map of file versions within a remote repository.
+type FileVersions struct {
+	rpc  rpc
+	file resource.ID
+}
+
+// List returns a list of version numbers for the file.
+func (ref FileVersions) List() (v []resource.Version, err error) {
+	err = ref.rpc.call("fileversions.list", target{File: ref.file}, nil, &v)
+	return v, err
+}
+
+// Ref returns a file version reference for the version number.
+func (ref FileVersions) Ref(v resource.Version) fileversion.Reference {
+	return FileVersion{rpc: ref.rpc, file: ref.file, version: v}
+}
+
+// FileVersion is a file version reference within a remote repository.
+type FileVersion struct {
+	rpc     rpc
+	file    resource.ID
+	version resource.Version
+}
+
+// File returns the ID of the file.
+func (ref FileVersion) File() resource.ID {
+	return ref.file
+}
+
+// Version returns the version number of the file.
+func (ref FileVersion) Version() resource.Version {
+	return ref.version
+}
+
+// Create creates a new file version with the given version number
+// and data. If a version already exists with the version number an
+// error will be returned.
+func (ref FileVersion) Create(data resource.FileData) error {
+	return ref.rpc.call("fileversion.create", target{File: ref.file, Version: ref.version}, data, nil)
+}
+
+// Data returns the data of the file version.
+func (ref FileVersion) Data() (data resource.FileData, err error) {
+	err = ref.rpc.call("fileversion.data", target{File: ref.file, Version: ref.version}, nil, &data)
+	return data, err
+}
+
+// FileViews is a map of file views within a remote repository.
+type FileViews struct {
+	rpc  rpc
+	file resource.ID
+}
+
+// List returns a list of drives with a view of the file.
+func (ref FileViews) List() (drives []resource.ID, err error) {
+	err = ref.rpc.call("fileviews.list", target{File: ref.file}, nil, &drives)
+	return drives, err
+}
+
+// Ref returns a view of the file for a particular drive.
+func (ref FileViews) Ref(driveID resource.ID) fileview.Reference {
+	return FileView{rpc: ref.rpc, file: ref.file, drive: driveID}
+}
+
+// FileView is a file view reference within a remote repository.
+type FileView struct {
+	rpc   rpc
+	file  resource.ID
+	drive resource.ID
+}
+
+// File returns the ID of the file.
+func (ref FileView) File() resource.ID {
+	return ref.file
+}
+
+// Drive returns the ID of the drive being viewed.
+func (ref FileView) Drive() resource.ID {
+	return ref.drive
+}
+
+// At returns the version reference of the file at a particular commit.
+func (ref FileView) At(seqNum commit.SeqNum) (fileversion.Reference, error) {
+	var version resource.Version
+	if err := ref.rpc.call("fileview.at", target{File: ref.file, Drive: ref.drive, Commit: seqNum}, nil, &version); err != nil {
+		return nil, err
+	}
+	return FileVersion{rpc: ref.rpc, file: ref.file, version: version}, nil
+}
+
+// Add adds version as a view of the file at the commit sequence number.
+func (ref FileView) Add(seqNum commit.SeqNum, version resource.Version) error {
+	return ref.rpc.call("fileview.add", target{File: ref.file, Drive: ref.drive, Commit: seqNum, Version: version}, nil, nil)
+}
@@ -0,0 +1,31 @@
+package remoterepo
+
+import "net/http"
+
+// ClientOption is a configuration option for a client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send calls to the server.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithToken sets the bearer token that the client presents to the server.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// ServerOption is a configuration option for a server.
+type ServerOption func(*Server)
+
+// RequireToken causes the server to reject calls that don't present token
+// as a bearer token.
+func RequireToken(token string) ServerOption {
+	return func(s *Server) {
+		s.token = token
+	}
+}
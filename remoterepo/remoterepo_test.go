@@ -0,0 +1,213 @@
+package remoterepo_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/fileview"
+	"github.com/scjalliance/drivestream/memrepo"
+	"github.com/scjalliance/drivestream/remoterepo"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+const (
+	testDrive = resource.ID("drive")
+	testToken = "s3cret"
+)
+
+// newClient returns a client of a test server that exposes repo and
+// requires the test token.
+func newClient(t *testing.T, repo drivestream.Repository) (*remoterepo.Client, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(remoterepo.NewServer(repo, remoterepo.RequireToken(testToken)))
+	return remoterepo.NewClient(srv.URL, remoterepo.WithToken(testToken)), srv
+}
+
+// nextCollection returns the sequence number of the next collection of the
+// test drive within repo.
+func nextCollection(t *testing.T, repo drivestream.Repository) collection.SeqNum {
+	t.Helper()
+	next, err := repo.Drive(testDrive).Collections().Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return next
+}
+
+// brokenRepository is a repository that fails to list its drives with an
+// error of a type that isn't registered with remoterepo.
+type brokenRepository struct {
+	drivestream.Repository
+}
+
+func (repo brokenRepository) Drives() drivestream.DriveMap {
+	return brokenDrives{repo.Repository.Drives()}
+}
+
+type brokenDrives struct {
+	drivestream.DriveMap
+}
+
+func (brokenDrives) List() ([]resource.ID, error) {
+	return nil, errors.New("the disk is on fire")
+}
+
+func TestRoundTrip(t *testing.T) {
+	repo := memrepo.New()
+	client, srv := newClient(t, repo)
+	defer srv.Close()
+
+	if got, want := client.Type(), "remote "+repo.Type(); got != want {
+		t.Fatalf("expected type %q, got %q", want, got)
+	}
+
+	data := collection.Data{Type: collection.Full, StartToken: "1"}
+	if err := client.Drive(testDrive).Collection(0).Create(data); err != nil {
+		t.Fatal(err)
+	}
+	if next := nextCollection(t, repo); next != 1 {
+		t.Fatalf("expected the collection to be created on the server, got next collection %d", next)
+	}
+	got, err := client.Drive(testDrive).Collection(0).Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != data.Type || got.StartToken != data.StartToken {
+		t.Fatalf("expected %+v, got %+v", data, got)
+	}
+	ids, err := client.Drives().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != testDrive {
+		t.Fatalf("expected drive %s, got %v", testDrive, ids)
+	}
+}
+
+func TestRegisteredErrors(t *testing.T) {
+	client, srv := newClient(t, memrepo.New())
+	defer srv.Close()
+
+	err := client.Drive(testDrive).Collection(5).Create(collection.Data{})
+	outOfOrder, ok := err.(collection.OutOfOrder)
+	if !ok {
+		t.Fatalf("expected collection.OutOfOrder, got %T: %v", err, err)
+	}
+	if want := (collection.OutOfOrder{Drive: testDrive, Collection: 5, Expected: 0}); outOfOrder != want {
+		t.Fatalf("expected %+v, got %+v", want, outOfOrder)
+	}
+
+	_, err = client.File("missing").View(testDrive).At(0)
+	var notFound fileview.NotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected fileview.NotFound, got %T: %v", err, err)
+	}
+	if notFound.File != "missing" || notFound.Drive != testDrive {
+		t.Fatalf("unexpected error data: %+v", notFound)
+	}
+}
+
+func TestRemoteError(t *testing.T) {
+	client, srv := newClient(t, brokenRepository{memrepo.New()})
+	defer srv.Close()
+
+	_, err := client.Drives().List()
+	remote, ok := err.(remoterepo.RemoteError)
+	if !ok {
+		t.Fatalf("expected remoterepo.RemoteError, got %T: %v", err, err)
+	}
+	if remote.Message != "the disk is on fire" || remote.Type == "" {
+		t.Fatalf("unexpected remote error: %+v", remote)
+	}
+}
+
+func TestBatchOutOfOrder(t *testing.T) {
+	repo := memrepo.New()
+	client, srv := newClient(t, repo)
+	defer srv.Close()
+
+	// The writes are buffered, so the error is returned when the batch
+	// is committed and none of its writes are applied.
+	err := client.Batch(func(tx drivestream.RepositoryTx) error {
+		if err := tx.Drive(testDrive).Collection(0).Create(collection.Data{}); err != nil {
+			return err
+		}
+		return tx.Drive(testDrive).Collection(5).Create(collection.Data{})
+	})
+	if _, ok := err.(collection.OutOfOrder); !ok {
+		t.Fatalf("expected collection.OutOfOrder, got %T: %v", err, err)
+	}
+	if next := nextCollection(t, repo); next != 0 {
+		t.Fatalf("expected the batch to be discarded, got next collection %d", next)
+	}
+}
+
+func TestBatchReads(t *testing.T) {
+	repo := memrepo.New()
+	client, srv := newClient(t, repo)
+	defer srv.Close()
+
+	err := client.Batch(func(tx drivestream.RepositoryTx) error {
+		if err := tx.Drive(testDrive).Collection(0).Create(collection.Data{}); err != nil {
+			return err
+		}
+		next, err := tx.Drive(testDrive).Collections().Next()
+		if err != nil {
+			return err
+		}
+		if next != 0 {
+			t.Errorf("expected reads within the batch not to see its writes, got next collection %d", next)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := nextCollection(t, repo); next != 1 {
+		t.Fatalf("expected the batch to be applied, got next collection %d", next)
+	}
+}
+
+func TestBatchPrune(t *testing.T) {
+	repo := memrepo.New()
+	if err := repo.Drive(testDrive).Collection(0).Create(collection.Data{}); err != nil {
+		t.Fatal(err)
+	}
+	client, srv := newClient(t, repo)
+	defer srv.Close()
+
+	err := client.Batch(func(tx drivestream.RepositoryTx) error {
+		_, err := tx.Drive(testDrive).Collection(0).Prune()
+		return err
+	})
+	if want := (remoterepo.NotBatchable{Method: "collection.prune"}); err != want {
+		t.Fatalf("expected %v, got %T: %v", want, err, err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	client, srv := newClient(t, memrepo.New())
+	defer srv.Close()
+
+	for _, c := range []*remoterepo.Client{
+		remoterepo.NewClient(srv.URL),
+		remoterepo.NewClient(srv.URL, remoterepo.WithToken("wrong")),
+	} {
+		_, err := c.Drives().List()
+		status, ok := err.(remoterepo.BadStatus)
+		if !ok {
+			t.Fatalf("expected remoterepo.BadStatus, got %T: %v", err, err)
+		}
+		if status.Status != "401 Unauthorized" {
+			t.Fatalf("expected status %d, got %s", http.StatusUnauthorized, status.Status)
+		}
+	}
+
+	if _, err := client.Drives().List(); err != nil {
+		t.Fatalf("a client with the token was rejected: %v", err)
+	}
+}
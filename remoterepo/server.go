@@ -0,0 +1,77 @@
+package remoterepo
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/scjalliance/drivestream"
+)
+
+// Server exposes a drivestream repository to clients over HTTP. It should
+// be created by calling NewServer.
+type Server struct {
+	repo  drivestream.Repository
+	token string
+}
+
+// NewServer returns a server that exposes repo.
+func NewServer(repo drivestream.Repository, options ...ServerOption) *Server {
+	s := &Server{repo: repo}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP responds to a call sent by a client.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != CallPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if s.token != "" {
+		presented := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte("Bearer "+s.token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var c call
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.call(c)
+
+	rep := reply{Error: encodeError(err)}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			rep.Error = encodeError(err)
+		} else {
+			rep.Result = data
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rep)
+}
+
+// call handles c and returns its result.
+func (s *Server) call(c call) (interface{}, error) {
+	switch c.Method {
+	case "repository.type":
+		return s.repo.Type(), nil
+	case "batch.commit":
+		return nil, s.commit(c)
+	}
+	return dispatch(s.repo, c)
+}
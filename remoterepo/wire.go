@@ -0,0 +1,55 @@
+package remoterepo
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/scjalliance/drivestream/collection"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/page"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// CallPath is the path of the endpoint that receives calls, relative to
+// the server's base URL.
+const CallPath = "/v1/call"
+
+// call is a request to invoke a method of the remote repository.
+type call struct {
+	Method string          `json:"method"`
+	Target target          `json:"target"`
+	Args   json.RawMessage `json:"args,omitempty"`
+}
+
+// target identifies the reference that a method is invoked on. Only the
+// members that apply to the reference are set.
+type target struct {
+	Drive      resource.ID       `json:"drive,omitempty"`
+	Collection collection.SeqNum `json:"collection,omitempty"`
+	Commit     commit.SeqNum     `json:"commit,omitempty"`
+	State      int64             `json:"state,omitempty"`
+	Page       page.SeqNum       `json:"page,omitempty"`
+	File       resource.ID       `json:"file,omitempty"`
+	Version    resource.Version  `json:"version,omitempty"`
+	Parent     resource.ID       `json:"parent,omitempty"`
+	Email      string            `json:"email,omitempty"`
+	Consumer   string            `json:"consumer,omitempty"`
+}
+
+// reply is the response to a call.
+type reply struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *wireError      `json:"error,omitempty"`
+}
+
+// readArgs are the arguments of a sequence's Read method.
+type readArgs struct {
+	Start int64 `json:"start"`
+	Count int   `json:"count"`
+}
+
+// timeRange are the arguments of an actor's Read method.
+type timeRange struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
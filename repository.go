@@ -19,4 +19,27 @@ type Repository interface {
 
 	// File returns a file reference.
 	File(fileID resource.ID) FileReference
+
+	// Batch calls fn with a transaction that reads from and writes to the
+	// repository. The changes made by fn are applied atomically when it
+	// returns nil, and are discarded when it returns an error. An error
+	// returned by a write within the transaction must be returned by fn.
+	Batch(fn func(tx RepositoryTx) error) error
+}
+
+// RepositoryTx provides access to drivestream data within a repository
+// transaction. It is only valid until the function it was passed to
+// returns.
+type RepositoryTx interface {
+	// Drives returns a drive map.
+	Drives() DriveMap
+
+	// Drive returns a drive reference.
+	Drive(driveID resource.ID) DriveReference
+
+	// Files returns a file map.
+	Files() FileMap
+
+	// File returns a file reference.
+	File(fileID resource.ID) FileReference
 }
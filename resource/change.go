@@ -11,6 +11,7 @@ type Change struct {
 	Type    Type      `json:"type"`
 	Time    time.Time `json:"time"`
 	Removed bool      `json:"removed,omitempty"`
+	Actor   *UserData `json:"actor,omitempty"`
 	File    `json:"file,omitempty"`
 	Drive   `json:"drive,omitempty"`
 }
@@ -23,11 +24,13 @@ func (c Change) MarshalJSON() ([]byte, error) {
 			Type    Type      `json:"type"`
 			Time    time.Time `json:"time"`
 			Removed bool      `json:"removed,omitempty"`
+			Actor   *UserData `json:"actor,omitempty"`
 			File    `json:"file"`
 		}{
 			Type:    c.Type,
 			Time:    c.Time,
 			Removed: c.Removed,
+			Actor:   c.Actor,
 			File:    c.File,
 		})
 	case TypeDrive:
@@ -35,11 +38,13 @@ func (c Change) MarshalJSON() ([]byte, error) {
 			Type    Type      `json:"type"`
 			Time    time.Time `json:"time"`
 			Removed bool      `json:"removed,omitempty"`
+			Actor   *UserData `json:"actor,omitempty"`
 			Drive   `json:"drive"`
 		}{
 			Type:    c.Type,
 			Time:    c.Time,
 			Removed: c.Removed,
+			Actor:   c.Actor,
 			Drive:   c.Drive,
 		})
 	default:
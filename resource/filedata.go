@@ -14,6 +14,7 @@ type FileData struct {
 	Created      time.Time `json:"createdTime,omitempty"`
 	Modified     time.Time `json:"modifiedTime,omitempty"`
 	Parents      []string  `json:"parents,omitempty"`
+	LastModifier *UserData `json:"lastModifyingUser,omitempty"`
 }
 
 // IsDir returns true if the file data describes a directory.
@@ -18,7 +18,7 @@ const (
 func (t Type) String() string {
 	switch t {
 	case TypeDrive:
-		return "drive#teamDrive"
+		return "drive#drive"
 	case TypeFile:
 		return "drive#file"
 	default:
@@ -26,10 +26,11 @@ func (t Type) String() string {
 	}
 }
 
-// ParseType parses v as a page type.
+// ParseType parses v as a resource type. The "drive#teamDrive" kind of
+// the deprecated Team Drives API is accepted as a drive.
 func ParseType(v string) (Type, error) {
 	switch strings.ToLower(v) {
-	case "drive#teamDrive":
+	case "drive#drive", "drive#teamdrive":
 		return TypeDrive, nil
 	case "drive#file":
 		return TypeFile, nil
@@ -0,0 +1,82 @@
+package drivestream
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/scjalliance/drivestream/collection"
+)
+
+// prune discards the changes held by the pages of collections that have
+// fallen outside of the stream's retention period.
+//
+// A collection is only pruned once it has been finalized for longer than
+// the retention period and a commit has been built from a later
+// collection, so that buildCommits never needs to revisit it. Collections
+// are pruned in order, so the search stops at the first collection that
+// has already been pruned.
+func (s *Stream) prune(ctx context.Context, update taskLogger) error {
+	task := update.Task("PRUNE")
+	drv := s.repo.Drive(s.drive)
+
+	next, err := drv.Commits().Next()
+	if err != nil {
+		task.Log("Retrieving commits from the repository\n")
+		return err
+	}
+	if next == 0 {
+		return nil
+	}
+
+	data, err := drv.Commit(next - 1).Data()
+	if err != nil {
+		task.Log("Reading commit data\n")
+		return err
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+
+	for seqNum := data.Source.Collection - 1; seqNum >= 0; seqNum-- {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		col := drv.Collection(seqNum)
+
+		pruned, err := col.Pruned()
+		if err != nil {
+			task.Log("Examining collection %d\n", seqNum)
+			return err
+		}
+		if !pruned.IsZero() {
+			break
+		}
+
+		r, err := collection.NewReader(col)
+		if err != nil {
+			task.Log("Examining collection %d\n", seqNum)
+			return err
+		}
+		if r.NextState() == 0 {
+			continue
+		}
+		state, err := r.LastState()
+		if err != nil {
+			task.Log("Examining collection %d\n", seqNum)
+			return err
+		}
+		if state.Phase != collection.PhaseFinalized || state.Time.After(cutoff) {
+			continue
+		}
+
+		pruned, err = col.Prune()
+		if err != nil {
+			task.Log("Pruning collection %d\n", seqNum)
+			return err
+		}
+		task.Log("Pruned %d changes from %d pages of collection %d (%s reclaimed)\n", pruned.Changes, pruned.Pages, seqNum, bytefmt.ByteSize(uint64(pruned.Bytes)))
+	}
+
+	return nil
+}
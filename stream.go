@@ -1,7 +1,9 @@
 package drivestream
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/driveactor"
 	"github.com/scjalliance/drivestream/fileview"
 
 	"github.com/scjalliance/drivestream/collection"
@@ -20,20 +23,26 @@ const defaultPageSize = 1000
 
 // Stream provides access to a stream of team drive changes.
 type Stream struct {
-	repo      Repository
-	drive     resource.ID
-	stdout    io.Writer
-	collector Collector
-	instance  string
-	pageSize  int64
+	repo         Repository
+	drive        resource.ID
+	stdout       io.Writer
+	collector    Collector
+	instance     string
+	pageSize     int64
+	retention    time.Duration
+	fullInterval time.Duration
+	pollInterval time.Duration
+	rootFolder   resource.ID
+	subscribers  subscriberSet
 }
 
 // New returns a new drive stream for the given service and team drive ID.
 func New(repo Repository, driveID resource.ID, options ...Option) *Stream {
 	s := &Stream{
-		repo:     repo,
-		drive:    driveID,
-		pageSize: defaultPageSize,
+		repo:         repo,
+		drive:        driveID,
+		pageSize:     defaultPageSize,
+		pollInterval: defaultPollInterval,
 	}
 	for _, opt := range options {
 		opt(s)
@@ -60,7 +69,15 @@ func (s *Stream) Update(ctx context.Context, c Collector) (err error) {
 		return err
 	}
 
-	return s.buildCommits(ctx, update)
+	if err = s.buildCommits(ctx, update); err != nil {
+		return err
+	}
+
+	if s.retention > 0 {
+		return s.prune(ctx, update)
+	}
+
+	return nil
 }
 
 func (s *Stream) collect(ctx context.Context, c Collector, update taskLogger) (err error) {
@@ -104,6 +121,10 @@ func (s *Stream) collect(ctx context.Context, c Collector, update taskLogger) (e
 		}
 	}
 
+	// At most one full collection is performed by each update, no matter
+	// how short the full collection interval is.
+	var fullCollected bool
+
 	for {
 		col := drv.Collection(seqNum)
 
@@ -144,6 +165,10 @@ func (s *Stream) collect(ctx context.Context, c Collector, update taskLogger) (e
 			return err
 		}
 
+		if data.Type == collection.Full && state.Phase != collection.PhaseFinalized {
+			fullCollected = true
+		}
+
 		if state.Page != 0 {
 			eval.Log("%s | %s | PAGE %d\n", strings.ToUpper(data.Type.String()), strings.ToUpper(state.Phase.String()), state.Page)
 		} else {
@@ -365,33 +390,48 @@ func (s *Stream) collect(ctx context.Context, c Collector, update taskLogger) (e
 				return fmt.Errorf("failed to determine starting token of next collection")
 			}
 
-			var (
-				n         int
-				nextToken string
-				buf       [1]resource.Change
-			)
-			n, nextToken, _, err = c.Changes(ctx, startToken, buf[:])
-			if err != nil {
-				eval.Log("Checking for new changes\n")
-				return err
-			}
-			if n == 0 && nextToken != "" {
-				eval.Log("Checking for new changes\n")
-				return fmt.Errorf("the collector returned an empty change data page")
+			var full bool
+			if !fullCollected {
+				full, err = s.fullCollectionDue(nextSeqNum)
+				if err != nil {
+					eval.Log("Examining previous full collections\n")
+					return err
+				}
 			}
 
-			if n == 0 {
-				eval.Log("No changes found\n")
-				return nil
-			}
+			colType := collection.Incremental
+			if full {
+				eval.Log("A full collection is due\n")
+				colType = collection.Full
+			} else {
+				var (
+					n         int
+					nextToken string
+					buf       [1]resource.Change
+				)
+				n, nextToken, _, err = c.Changes(ctx, startToken, buf[:])
+				if err != nil {
+					eval.Log("Checking for new changes\n")
+					return err
+				}
+				if n == 0 && nextToken != "" {
+					eval.Log("Checking for new changes\n")
+					return fmt.Errorf("the collector returned an empty change data page")
+				}
 
-			eval.Log("Changes found\n")
+				if n == 0 {
+					eval.Log("No changes found\n")
+					return nil
+				}
+
+				eval.Log("Changes found\n")
+			}
 
 			init := colTask.Task("INIT")
 
 			init.Log("Adding collection to the repository\n")
 			data := collection.Data{
-				Type:       collection.Incremental,
+				Type:       colType,
 				StartToken: startToken,
 			}
 			if err = drv.Collection(nextSeqNum).Create(data); err != nil {
@@ -405,6 +445,33 @@ func (s *Stream) collect(ctx context.Context, c Collector, update taskLogger) (e
 	}
 }
 
+// fullCollectionDue returns true if the stream performs periodic full
+// collections and the most recent full collection before seqNum started
+// longer ago than the full collection interval.
+func (s *Stream) fullCollectionDue(seqNum collection.SeqNum) (bool, error) {
+	if s.fullInterval <= 0 {
+		return false, nil
+	}
+	drv := s.repo.Drive(s.drive)
+	for seqNum > 0 {
+		seqNum--
+		col := drv.Collection(seqNum)
+		data, err := col.Data()
+		if err != nil {
+			return false, err
+		}
+		if data.Type != collection.Full {
+			continue
+		}
+		state, err := col.State(0).Data()
+		if err != nil {
+			return false, err
+		}
+		return time.Since(state.Time) >= s.fullInterval, nil
+	}
+	return true, nil
+}
+
 func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error) {
 	drv := s.repo.Drive(s.drive)
 	seqNum, err := drv.Commits().Next()
@@ -438,8 +505,16 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 
 		comTask := update.Task(fmt.Sprintf("COMMIT %d", seqNum))
 		init := comTask.Task("INIT")
+
+		var data commit.Data
+		data.Time, err = s.sourceTime(data.Source)
+		if err != nil {
+			init.Log("Determining commit time\n")
+			return err
+		}
+
 		init.Log("Adding commit to the repository\n")
-		if err = drv.Commit(seqNum).Create(commit.Data{}); err != nil {
+		if err = drv.Commit(seqNum).Create(data); err != nil {
 			return err
 		}
 	}
@@ -494,6 +569,14 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 			phase := comTask.Task(strings.ToUpper(commit.PhaseSourceProcessing.String()))
 			phase.Log("Starting phase\n")
 
+			// The changes of incremental collections and the drift of
+			// full collections are recorded together with the state that
+			// completes the phase.
+			var (
+				changes []resource.Change
+				drift   *commit.DriftData
+			)
+
 			switch colData.Type {
 			case collection.Full:
 				col, err := collection.NewReader(drv.Collection(data.Source.Collection))
@@ -502,6 +585,18 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 					return err
 				}
 
+				// Full collections that follow an earlier commit are
+				// reconciled against it, and only the drift they reveal
+				// is committed.
+				var rec *reconciliation
+				if seqNum > 0 {
+					phase.Log("Reconciling the collection with commit %d\n", seqNum-1)
+					rec, err = s.reconcile(col, seqNum, data.Time)
+					if err != nil {
+						return err
+					}
+				}
+
 				for {
 					pg, err := col.Page(state.Page)
 					if err != nil {
@@ -511,19 +606,53 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 
 					phase.Log("COL %d PAGE %d\n", data.Source.Collection, state.Page)
 
-					if err := s.processSourceChanges(phase, com, pg.Changes); err != nil {
+					changes := pg.Changes
+					if rec != nil && pg.Type == page.FileList {
+						changes = rec.Corrections(changes)
+					}
+
+					// The changes of each page are recorded together with
+					// the state that marks the page as processed.
+					last := state.Page+1 >= col.NextPage()
+					err = s.batchCommit(seqNum, func(tx RepositoryTx, com commit.Reference, w *commit.Writer) error {
+						if err := s.processSourceChanges(phase, tx, com, changes); err != nil {
+							return err
+						}
+						if last {
+							return nil
+						}
+						if err := w.SetState(commit.PhaseSourceProcessing, state.Page+1); err != nil {
+							phase.Log("Updating commit state\n")
+							return err
+						}
+						return nil
+					})
+					if err != nil {
 						return err
 					}
 
-					if state.Page+1 >= col.NextPage() {
+					if last {
 						break
 					}
 
 					state.Page++
-					if err := w.SetState(commit.PhaseSourceProcessing, state.Page); err != nil {
-						phase.Log("Updating commit state\n")
-						return err
+				}
+
+				if rec != nil {
+					for start := 0; start < len(rec.removals); start += int(s.pageSize) {
+						end := start + int(s.pageSize)
+						if end > len(rec.removals) {
+							end = len(rec.removals)
+						}
+						err := s.batchCommit(seqNum, func(tx RepositoryTx, com commit.Reference, w *commit.Writer) error {
+							return s.processSourceChanges(phase, tx, com, rec.removals[start:end])
+						})
+						if err != nil {
+							return err
+						}
 					}
+					drift = &rec.drift
+					drift.Time = time.Now().UTC()
 				}
 			case collection.Incremental:
 				pg, err := drv.Collection(data.Source.Collection).Page(data.Source.Page).Data()
@@ -541,18 +670,35 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 
 				start := data.Source.Index
 				end := start + 1
-				if err := s.processSourceChanges(phase, com, pg.Changes[start:end]); err != nil {
-					return err
-				}
+				changes = pg.Changes[start:end]
 			default:
 				return fmt.Errorf("the source collection is of unrecognized type %d", colData.Type)
 			}
 
-			if err := w.SetState(commit.PhaseTreeProcessing, 0); err != nil {
-				phase.Log("Updating commit state\n")
+			err := s.batchCommit(seqNum, func(tx RepositoryTx, com commit.Reference, w *commit.Writer) error {
+				if err := s.processSourceChanges(phase, tx, com, changes); err != nil {
+					return err
+				}
+				if drift != nil {
+					if err := com.SetDrift(*drift); err != nil {
+						phase.Log("Recording drift\n")
+						return err
+					}
+				}
+				if err := w.SetState(commit.PhaseTreeProcessing, 0); err != nil {
+					phase.Log("Updating commit state\n")
+					return err
+				}
+				return nil
+			})
+			if err != nil {
 				return err
 			}
 
+			if drift != nil {
+				phase.Log("Drift: %d added, %d changed, %d removed\n", drift.Added, drift.Changed, drift.Removed)
+			}
+
 			phase.Log("Finished phase in %s\n", phase.Duration())
 
 			fallthrough
@@ -560,11 +706,16 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 			phase := comTask.Task(strings.ToUpper(commit.PhaseTreeProcessing.String()))
 			phase.Log("Starting phase\n")
 
-			if err := w.SetState(commit.PhaseFinalized, 0); err != nil {
+			err := s.batchCommit(seqNum, func(tx RepositoryTx, com commit.Reference, w *commit.Writer) error {
+				return w.SetState(commit.PhaseFinalized, 0)
+			})
+			if err != nil {
 				phase.Log("Updating commit state\n")
 				return err
 			}
 
+			s.subscribers.notify()
+
 			parents, err := com.Tree().Parents()
 			if err != nil {
 				phase.Log("Retrieving tree changes\n")
@@ -650,10 +801,16 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 
 			init := comTask.Task("INIT")
 
-			init.Log("Adding commit to the repository\n")
 			data := commit.Data{
 				Source: nextSource,
 			}
+			data.Time, err = s.sourceTime(nextSource)
+			if err != nil {
+				init.Log("Determining commit time\n")
+				return err
+			}
+
+			init.Log("Adding commit to the repository\n")
 			if err = drv.Commit(nextSeqNum).Create(data); err != nil {
 				return err
 			}
@@ -665,16 +822,69 @@ func (s *Stream) buildCommits(ctx context.Context, update taskLogger) (err error
 	}
 }
 
-func (s *Stream) processSourceChanges(phase taskLogger, com commit.Reference, changes []resource.Change) error {
+// batchCommit calls fn with a reference to the commit seqNum and a writer
+// for it, both of which belong to a single repository transaction.
+func (s *Stream) batchCommit(seqNum commit.SeqNum, fn func(tx RepositoryTx, com commit.Reference, w *commit.Writer) error) error {
+	return s.repo.Batch(func(tx RepositoryTx) error {
+		com := tx.Drive(s.drive).Commit(seqNum)
+		w, err := commit.NewWriter(com, s.instance)
+		if err != nil {
+			return err
+		}
+		return fn(tx, com, w)
+	})
+}
+
+// processSourceChanges records the file versions, views, commit files,
+// tree changes and actors of changes within tx.
+//
+// Removals of files that aren't in the drive's view are dropped, because
+// they don't change the drive. Collectors can report them for files that
+// were never part of the drive, such as files that have only been shared
+// with the user of a My Drive.
+func (s *Stream) processSourceChanges(phase taskLogger, tx RepositoryTx, com commit.Reference, changes []resource.Change) error {
+	// present records whether each file changed earlier within changes is
+	// part of the drive, because the view of the commit doesn't include
+	// those changes yet.
+	present := make(map[resource.ID]bool)
+
 	files := make([]resource.File, 0, len(changes))
 	fileViewData := make([]fileview.Data, 0, len(changes))
 	fileChanges := make([]commit.FileChange, 0, len(changes))
 	treeChanges := make([]commit.TreeChange, 0, len(changes)*2)
+	actorEntries := make([]driveactor.Entry, 0, len(changes))
+	var drive *resource.DriveData
 	for _, change := range changes {
 		switch change.Type {
 		case resource.TypeDrive:
-			// FIXME: TODO: Record drive changes
+			// FIXME: TODO: Record drive removals
+			if !change.Removed {
+				drive = &change.Drive.DriveData
+			}
 		case resource.TypeFile:
+			if change.Removed {
+				inView, ok := present[change.File.ID]
+				if !ok {
+					version, err := s.viewedVersion(tx, change.File.ID, com.SeqNum())
+					if err != nil {
+						phase.Log("Examining file views\n")
+						return err
+					}
+					inView = version >= 0
+				}
+				if !inView {
+					continue
+				}
+			}
+			present[change.File.ID] = !change.Removed
+			if change.Actor != nil && change.Actor.EmailAddress != "" {
+				actorEntries = append(actorEntries, driveactor.Entry{
+					Actor:  *change.Actor,
+					Time:   change.Time,
+					Commit: com.SeqNum(),
+					File:   change.File.ID,
+				})
+			}
 			if !change.Removed {
 				files = append(files, change.File)
 				fileChanges = append(fileChanges, commit.FileChange{
@@ -689,7 +899,7 @@ func (s *Stream) processSourceChanges(phase taskLogger, com commit.Reference, ch
 				})
 				for _, parent := range change.File.Parents {
 					treeChanges = append(treeChanges, commit.TreeChange{
-						Parent: resource.ID(parent),
+						Parent: s.treeParent(parent),
 						Child:  change.File.ID,
 					})
 				}
@@ -706,7 +916,7 @@ func (s *Stream) processSourceChanges(phase taskLogger, com commit.Reference, ch
 				})
 				for _, parent := range change.File.Parents {
 					treeChanges = append(treeChanges, commit.TreeChange{
-						Parent:  resource.ID(parent),
+						Parent:  s.treeParent(parent),
 						Child:   change.File.ID,
 						Removed: true,
 					})
@@ -715,15 +925,28 @@ func (s *Stream) processSourceChanges(phase taskLogger, com commit.Reference, ch
 		}
 	}
 
+	if drive != nil {
+		if err := s.recordDrive(tx, com.SeqNum(), *drive); err != nil {
+			phase.Log("Recording drive version\n")
+			return err
+		}
+	}
+
+	files, err := s.unseenVersions(tx, com.SeqNum(), files)
+	if err != nil {
+		phase.Log("Examining file views\n")
+		return err
+	}
+
 	if len(files) > 0 {
-		if err := s.repo.Files().AddVersions(files...); err != nil {
+		if err := tx.Files().AddVersions(files...); err != nil {
 			phase.Log("Recording file versions\n")
 			return err
 		}
 	}
 
 	if len(fileViewData) > 0 {
-		if err := s.repo.Files().AddViewData(fileViewData...); err != nil {
+		if err := tx.Files().AddViewData(fileViewData...); err != nil {
 			phase.Log("Recording file view data\n")
 			return err
 		}
@@ -743,9 +966,111 @@ func (s *Stream) processSourceChanges(phase taskLogger, com commit.Reference, ch
 		}
 	}
 
+	if len(actorEntries) > 0 {
+		if err := tx.Drive(s.drive).Actors().Add(actorEntries...); err != nil {
+			phase.Log("Recording actors\n")
+			return err
+		}
+	}
+
 	return nil
 }
 
+// treeParent returns the parent recorded in tree changes for a file with
+// the given parent. Files within the stream's root folder are recorded as
+// children of the drive.
+func (s *Stream) treeParent(parent string) resource.ID {
+	if s.rootFolder != "" && resource.ID(parent) == s.rootFolder {
+		return s.drive
+	}
+	return resource.ID(parent)
+}
+
+// unseenVersions returns the members of files that the drive's view of
+// each file doesn't already refer to as of the commit before seqNum.
+// Versions that are already in view were stored by an earlier commit and
+// are reused instead of being stored again, which avoids rewriting every
+// file version during a full collection.
+func (s *Stream) unseenVersions(tx RepositoryTx, seqNum commit.SeqNum, files []resource.File) ([]resource.File, error) {
+	if seqNum == 0 {
+		return files, nil
+	}
+	unseen := files[:0]
+	for _, file := range files {
+		version, err := s.viewedVersion(tx, file.ID, seqNum-1)
+		if err != nil {
+			return nil, err
+		}
+		if version == file.Version {
+			continue
+		}
+		unseen = append(unseen, file)
+	}
+	return unseen, nil
+}
+
+// recordDrive makes data the version of the drive in view as of seqNum. A
+// new drive version is only created when data differs from the most
+// recent version.
+func (s *Stream) recordDrive(tx RepositoryTx, seqNum commit.SeqNum, data resource.DriveData) error {
+	drv := tx.Drive(s.drive)
+	version, err := drv.Versions().Next()
+	if err != nil {
+		return err
+	}
+	same := false
+	if version > 0 {
+		latest, err := drv.Version(version - 1).Data()
+		if err != nil {
+			return err
+		}
+		if same, err = sameDriveData(latest, data); err != nil {
+			return err
+		}
+	}
+	if same {
+		version--
+	} else if err := drv.Version(version).Create(data); err != nil {
+		return err
+	}
+	return drv.View().Add(seqNum, version)
+}
+
+// sameDriveData returns true if a and b have identical JSON encodings.
+func sameDriveData(a, b resource.DriveData) (bool, error) {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aj, bj), nil
+}
+
+// sourceTime returns the time of the source data for a commit. For full
+// collections this is the time the first page was collected. For
+// incremental collections it is the time of the change itself.
+func (s *Stream) sourceTime(source commit.Source) (time.Time, error) {
+	col := s.repo.Drive(s.drive).Collection(source.Collection)
+	data, err := col.Data()
+	if err != nil {
+		return time.Time{}, err
+	}
+	pg, err := col.Page(source.Page).Data()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if data.Type == collection.Full {
+		return pg.Collected, nil
+	}
+	if source.Index >= len(pg.Changes) {
+		return pg.Collected, nil
+	}
+	return pg.Changes[source.Index].Time, nil
+}
+
 func (s *Stream) readyToCommit(ref collection.Reference) (bool, error) {
 	exists, err := ref.Exists()
 	if err != nil {
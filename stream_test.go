@@ -0,0 +1,201 @@
+package drivestream_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/boltrepo"
+	"github.com/scjalliance/drivestream/fileversion"
+	"github.com/scjalliance/drivestream/memrepo"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// collector is a drivestream collector that returns a fixed set of files
+// and batches of changes. The change token is the index of the next batch.
+type collector struct {
+	drive   resource.Drive
+	files   []resource.Change
+	changes [][]resource.Change
+}
+
+func (c *collector) ChangeToken(ctx context.Context) (string, error) {
+	return "0", nil
+}
+
+func (c *collector) Drive(ctx context.Context) (resource.Change, error) {
+	return resource.Change{Type: resource.TypeDrive, Drive: c.drive}, nil
+}
+
+func (c *collector) Files(ctx context.Context, token string, p []resource.Change) (int, string, error) {
+	return copy(p, c.files), "", nil
+}
+
+func (c *collector) Changes(ctx context.Context, token string, p []resource.Change) (int, string, string, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if i >= len(c.changes) {
+		return 0, "", token, nil
+	}
+	return copy(p, c.changes[i]), "", strconv.Itoa(i + 1), nil
+}
+
+// newCollector returns a collector for a drive that holds files. Its
+// first batch of changes is empty, so the files are committed by
+// themselves.
+func newCollector(driveID resource.ID, files ...resource.Change) *collector {
+	return &collector{
+		drive:   resource.Drive{ID: driveID, DriveData: resource.DriveData{Name: string(driveID)}},
+		files:   files,
+		changes: [][]resource.Change{{}},
+	}
+}
+
+// at returns a time a number of minutes after a fixed epoch.
+func at(minutes int) time.Time {
+	return time.Date(2020, 1, 1, 0, minutes, 0, 0, time.UTC)
+}
+
+// fileChange returns a change for a version of a file within parent, as
+// seen by the user with the given email address.
+func fileChange(id resource.ID, version resource.Version, parent resource.ID, email string, minutes int) resource.Change {
+	user := &resource.UserData{EmailAddress: email}
+	return resource.Change{
+		Type:  resource.TypeFile,
+		Time:  at(minutes),
+		Actor: user,
+		File: resource.File{
+			ID:      id,
+			Version: version,
+			FileData: resource.FileData{
+				Name:         string(id),
+				MD5Checksum:  "d41d8cd98f00b204e9800998ecf8427e",
+				Created:      at(0),
+				Modified:     at(minutes),
+				Parents:      []string{string(parent)},
+				LastModifier: user,
+			},
+		},
+	}
+}
+
+// newBoltRepo returns a bolt repository within a temporary directory, and
+// a function that closes and removes it.
+func newBoltRepo(t *testing.T) (boltrepo.Repository, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "drivestream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(dir, "drivestream.db"), 0600, nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	repo, err := boltrepo.Open(db)
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return repo, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// forEachRepo calls fn with an empty memory repository and an empty bolt
+// repository.
+func forEachRepo(t *testing.T, fn func(t *testing.T, repo drivestream.Repository)) {
+	t.Run("memrepo", func(t *testing.T) {
+		fn(t, memrepo.New())
+	})
+	t.Run("boltrepo", func(t *testing.T) {
+		repo, cleanup := newBoltRepo(t)
+		defer cleanup()
+		fn(t, repo)
+	})
+}
+
+// update runs n updates of the stream for a drive.
+func update(t *testing.T, repo drivestream.Repository, c *collector, n int) {
+	t.Helper()
+	s := drivestream.New(repo, c.drive.ID)
+	for i := 0; i < n; i++ {
+		if err := s.Update(context.Background(), c); err != nil {
+			t.Fatalf("drive %s: update %d: %v", c.drive.ID, i, err)
+		}
+	}
+}
+
+func TestSameVersionThroughTwoDrives(t *testing.T) {
+	forEachRepo(t, func(t *testing.T, repo drivestream.Repository) {
+		// The same version of a file is seen by two users through two
+		// drives, each of which reports itself as the parent and its own
+		// user as the last modifier.
+		update(t, repo, newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1)), 2)
+		update(t, repo, newCollector("b", fileChange("file", 1, "b", "bob@example.com", 1)), 2)
+
+		for _, driveID := range []resource.ID{"a", "b"} {
+			next, err := repo.Drive(driveID).Commits().Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref, err := repo.File("file").View(driveID).At(next - 1)
+			if err != nil {
+				t.Fatalf("drive %s: %v", driveID, err)
+			}
+			if ref.Version() != 1 {
+				t.Fatalf("drive %s: expected version 1 in view, got %d", driveID, ref.Version())
+			}
+		}
+
+		// The first copy of the version is kept.
+		data, err := repo.File("file").Version(1).Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.LastModifier == nil || data.LastModifier.EmailAddress != "alice@example.com" {
+			t.Fatalf("expected the first copy of the version to be kept, got %+v", data)
+		}
+	})
+}
+
+func TestCheckVersionConflict(t *testing.T) {
+	repo, cleanup := newBoltRepo(t)
+	defer cleanup()
+
+	update(t, repo, newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1)), 2)
+	update(t, repo, newCollector("b", fileChange("file", 1, "b", "bob@example.com", 1)), 2)
+
+	problems, err := repo.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("copies that differ in their parents and last modifier were reported: %v", problems)
+	}
+
+	// A copy that disagrees on the checksum of the version is still
+	// committed, but is reported by Check.
+	change := fileChange("file", 1, "c", "carol@example.com", 1)
+	change.File.MD5Checksum = "0cc175b9c0f1b6a831c399e269772661"
+	update(t, repo, newCollector("c", change), 2)
+
+	problems, err = repo.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fileversion.Conflict{File: "file", Version: 1, Drive: "c"}
+	if len(problems) != 1 || problems[0].Err != want {
+		t.Fatalf("expected %v, got %v", want, problems)
+	}
+}
@@ -0,0 +1,225 @@
+package drivestream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+const defaultPollInterval = time.Minute
+
+// CommitEvent describes a finalized commit delivered to a subscription.
+type CommitEvent struct {
+	SeqNum commit.SeqNum
+	commit.Data
+	Drift commit.DriftData
+	Files []commit.FileChange
+	Tree  []commit.TreeChange
+}
+
+// Subscription delivers the finalized commits of a drive in order. It
+// should be created by calling Stream.Subscribe.
+//
+// Commits are read from the repository as they are requested, so a
+// subscriber that falls behind never holds up the stream that writes
+// them. The subscription simply catches up on its next call to Next.
+type Subscription struct {
+	ctx     context.Context
+	stream  *Stream
+	next    commit.SeqNum
+	wake    chan struct{}
+	current CommitEvent
+	err     error
+	closed  bool
+}
+
+// Subscribe returns a subscription to the finalized commits of the
+// stream's drive, starting with the commit from.
+//
+// Commits that have already been finalized are delivered first. After
+// that the subscription waits for new commits. Commits finalized by
+// Update on s are delivered as soon as they are finalized. Commits written
+// by other streams or processes are found by polling the repository at the
+// stream's poll interval.
+//
+// The subscription ends when ctx is cancelled or when it is closed.
+func (s *Stream) Subscribe(ctx context.Context, from commit.SeqNum) *Subscription {
+	sub := &Subscription{
+		ctx:    ctx,
+		stream: s,
+		next:   from,
+		wake:   s.subscribers.add(),
+	}
+	if from < 0 {
+		sub.err = fmt.Errorf("invalid commit sequence number %d", from)
+	}
+	return sub
+}
+
+// Next waits for the next finalized commit and makes it available through
+// Commit. It returns false when the subscription ends, after which Err
+// returns the reason it ended.
+func (sub *Subscription) Next() bool {
+	if sub.err != nil || sub.closed {
+		return false
+	}
+
+	var poll <-chan time.Time
+	if interval := sub.stream.pollInterval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		ok, err := sub.read()
+		if err != nil {
+			sub.err = err
+			return false
+		}
+		if ok {
+			sub.next++
+			return true
+		}
+
+		select {
+		case <-sub.ctx.Done():
+			sub.err = sub.ctx.Err()
+			return false
+		case <-sub.wake:
+		case <-poll:
+		}
+	}
+}
+
+// Commit returns the commit found by the most recent call to Next.
+func (sub *Subscription) Commit() CommitEvent {
+	return sub.current
+}
+
+// Err returns the error that ended the subscription. It returns nil if the
+// subscription was closed.
+func (sub *Subscription) Err() error {
+	return sub.err
+}
+
+// Close ends the subscription. It must be called once the subscription is
+// no longer needed.
+func (sub *Subscription) Close() {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	sub.stream.subscribers.remove(sub.wake)
+}
+
+// read attempts to read the next commit. It returns false if the commit
+// hasn't been finalized yet.
+func (sub *Subscription) read() (bool, error) {
+	event, ok, err := ReadCommitEvent(sub.stream.repo, sub.stream.drive, sub.next)
+	if err != nil || !ok {
+		return false, err
+	}
+	sub.current = event
+	return true, nil
+}
+
+// ReadCommitEvent reads the commit seqNum of a drive within repo. It
+// returns false if the commit doesn't exist or hasn't been finalized yet.
+func ReadCommitEvent(repo Repository, driveID resource.ID, seqNum commit.SeqNum) (event CommitEvent, ok bool, err error) {
+	drv := repo.Drive(driveID)
+	next, err := drv.Commits().Next()
+	if err != nil {
+		return CommitEvent{}, false, err
+	}
+	if seqNum >= next {
+		return CommitEvent{}, false, nil
+	}
+
+	ref := drv.Commit(seqNum)
+	r, err := commit.NewReader(ref)
+	if err != nil {
+		return CommitEvent{}, false, err
+	}
+	if r.NextState() == 0 {
+		return CommitEvent{}, false, nil
+	}
+	state, err := r.LastState()
+	if err != nil {
+		return CommitEvent{}, false, err
+	}
+	if state.Phase != commit.PhaseFinalized {
+		return CommitEvent{}, false, nil
+	}
+
+	event.SeqNum = seqNum
+	if event.Data, err = r.Data(); err != nil {
+		return CommitEvent{}, false, err
+	}
+	if event.Drift, err = ref.Drift(); err != nil {
+		return CommitEvent{}, false, err
+	}
+	if event.Files, err = ref.Files().Read(); err != nil {
+		return CommitEvent{}, false, err
+	}
+	parents, err := ref.Tree().Parents()
+	if err != nil {
+		return CommitEvent{}, false, err
+	}
+	for _, parent := range parents {
+		changes, err := ref.Tree().Group(parent).Changes()
+		if err != nil {
+			return CommitEvent{}, false, err
+		}
+		event.Tree = append(event.Tree, changes...)
+	}
+
+	return event, true, nil
+}
+
+// subscriberSet notifies the subscriptions of a stream when commits are
+// finalized.
+//
+// Each subscription has a wake channel with room for a single
+// notification. Notifications are dropped when the channel is already
+// full, which never blocks the notifier and is harmless because a
+// subscription reads every commit it hasn't yet delivered when it wakes.
+type subscriberSet struct {
+	mutex sync.Mutex
+	wake  map[chan struct{}]struct{}
+}
+
+// add returns a new wake channel.
+func (set *subscriberSet) add() chan struct{} {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	if set.wake == nil {
+		set.wake = make(map[chan struct{}]struct{})
+	}
+	wake := make(chan struct{}, 1)
+	set.wake[wake] = struct{}{}
+	return wake
+}
+
+// remove removes a wake channel.
+func (set *subscriberSet) remove(wake chan struct{}) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	delete(set.wake, wake)
+}
+
+// notify wakes every subscription.
+func (set *subscriberSet) notify() {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	for wake := range set.wake {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
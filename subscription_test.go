@@ -0,0 +1,197 @@
+package drivestream_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scjalliance/drivestream"
+	"github.com/scjalliance/drivestream/commit"
+	"github.com/scjalliance/drivestream/resource"
+)
+
+// nextCommit returns the sequence number of the next commit of a drive.
+func nextCommit(t *testing.T, repo drivestream.Repository, driveID resource.ID) commit.SeqNum {
+	t.Helper()
+	next, err := repo.Drive(driveID).Commits().Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return next
+}
+
+// receive returns the next commit received from events, or fails the test
+// if none is received within a few seconds.
+func receive(t *testing.T, events <-chan drivestream.CommitEvent) drivestream.CommitEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("the subscription ended early")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a commit")
+	}
+	return drivestream.CommitEvent{}
+}
+
+// deliver sends the commits of sub to a channel until the subscription
+// ends, and then sends its error to done.
+func deliver(sub *drivestream.Subscription) (events <-chan drivestream.CommitEvent, done <-chan error) {
+	eventc := make(chan drivestream.CommitEvent)
+	donec := make(chan error, 1)
+	go func() {
+		defer close(eventc)
+		for sub.Next() {
+			eventc <- sub.Commit()
+		}
+		donec <- sub.Err()
+	}()
+	return eventc, donec
+}
+
+func TestReadCommitEvent(t *testing.T) {
+	forEachRepo(t, func(t *testing.T, repo drivestream.Repository) {
+		update(t, repo, newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1)), 2)
+
+		next := nextCommit(t, repo, "a")
+		var found bool
+		for seqNum := commit.SeqNum(0); seqNum < next; seqNum++ {
+			event, ok, err := drivestream.ReadCommitEvent(repo, "a", seqNum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatalf("commit %d: expected a finalized commit", seqNum)
+			}
+			if event.SeqNum != seqNum {
+				t.Fatalf("commit %d: unexpected sequence number %d", seqNum, event.SeqNum)
+			}
+			for _, change := range event.Files {
+				if change.File != "file" || change.Version != 1 {
+					continue
+				}
+				found = true
+				if len(event.Tree) != 1 || event.Tree[0].Parent != "a" || event.Tree[0].Child != "file" || event.Tree[0].Removed {
+					t.Fatalf("commit %d: unexpected tree changes %+v", seqNum, event.Tree)
+				}
+			}
+		}
+		if !found {
+			t.Fatal("the file was not added by any commit")
+		}
+
+		// Commits that don't exist or haven't been finalized aren't
+		// returned.
+		if _, ok, err := drivestream.ReadCommitEvent(repo, "a", next); ok || err != nil {
+			t.Fatalf("commit %d doesn't exist: expected false and no error, got %t and %v", next, ok, err)
+		}
+		if err := repo.Drive("a").Commit(next).Create(commit.Data{Time: at(2)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok, err := drivestream.ReadCommitEvent(repo, "a", next); ok || err != nil {
+			t.Fatalf("commit %d isn't finalized: expected false and no error, got %t and %v", next, ok, err)
+		}
+	})
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	forEachRepo(t, func(t *testing.T, repo drivestream.Repository) {
+		c := newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1))
+		c.changes = append(c.changes, []resource.Change{fileChange("file", 2, "a", "alice@example.com", 2)})
+		update(t, repo, c, 2)
+
+		next := nextCommit(t, repo, "a")
+		if next < 2 {
+			t.Fatalf("expected at least 2 commits, got %d", next)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s := drivestream.New(repo, "a", drivestream.WithPollInterval(0))
+		sub := s.Subscribe(ctx, 1)
+		defer sub.Close()
+		events, done := deliver(sub)
+
+		// Commits that were finalized before the subscription started are
+		// delivered in order, starting with from.
+		for seqNum := commit.SeqNum(1); seqNum < next; seqNum++ {
+			if event := receive(t, events); event.SeqNum != seqNum {
+				t.Fatalf("expected commit %d, got %d", seqNum, event.SeqNum)
+			}
+		}
+
+		// The subscription then waits until its context is cancelled.
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestSubscribeNotify(t *testing.T) {
+	forEachRepo(t, func(t *testing.T, repo drivestream.Repository) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Polling is disabled, so new commits can only be delivered when
+		// the stream notifies its subscribers.
+		c := newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1))
+		s := drivestream.New(repo, "a", drivestream.WithPollInterval(0))
+		for i := 0; i < 2; i++ {
+			if err := s.Update(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		from := nextCommit(t, repo, "a")
+		sub := s.Subscribe(ctx, from)
+		defer sub.Close()
+		events, done := deliver(sub)
+
+		// Give the subscription time to find that the commit doesn't exist
+		// yet and wait for a notification.
+		time.Sleep(50 * time.Millisecond)
+
+		c.changes = append(c.changes, []resource.Change{fileChange("file", 2, "a", "alice@example.com", 2)})
+		if err := s.Update(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+
+		event := receive(t, events)
+		if event.SeqNum != from {
+			t.Fatalf("expected commit %d, got %d", from, event.SeqNum)
+		}
+		if len(event.Files) != 1 || event.Files[0].File != "file" || event.Files[0].Version != 2 {
+			t.Fatalf("unexpected file changes %+v", event.Files)
+		}
+
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestSubscribeClose(t *testing.T) {
+	forEachRepo(t, func(t *testing.T, repo drivestream.Repository) {
+		update(t, repo, newCollector("a", fileChange("file", 1, "a", "alice@example.com", 1)), 2)
+
+		s := drivestream.New(repo, "a", drivestream.WithPollInterval(0))
+		sub := s.Subscribe(context.Background(), 0)
+		if !sub.Next() {
+			t.Fatalf("expected commit 0, got error %v", sub.Err())
+		}
+
+		// A closed subscription ends without an error.
+		sub.Close()
+		if sub.Next() {
+			t.Fatal("a closed subscription delivered a commit")
+		}
+		if err := sub.Err(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
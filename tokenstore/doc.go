@@ -0,0 +1,10 @@
+// Package tokenstore persists OAuth2 tokens.
+//
+// A Store loads and saves a single token. File stores a token in a file,
+// optionally encoded with a codec such as the encrypted codec of the
+// codec package. Other stores, such as an operating system keychain, can
+// be provided by implementing the Store interface.
+//
+// TokenSource returns a token source that writes refreshed tokens back to
+// a store, so the most recent token survives a restart.
+package tokenstore
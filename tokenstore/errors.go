@@ -0,0 +1,13 @@
+package tokenstore
+
+import "fmt"
+
+// NotFound reports that a token has not been saved to a store.
+type NotFound struct {
+	Location string
+}
+
+// Error returns a string representation of the error.
+func (e NotFound) Error() string {
+	return fmt.Sprintf("drivestream: no token has been saved to %s", e.Location)
+}
@@ -0,0 +1,88 @@
+package tokenstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/scjalliance/drivestream/codec"
+	"golang.org/x/oauth2"
+)
+
+// File stores a token in a file. It should be created by calling NewFile.
+type File struct {
+	path  string
+	codec codec.Codec
+}
+
+var _ Store = (*File)(nil)
+
+// NewFile returns a store that saves a token to the file at path.
+func NewFile(path string, options ...Option) *File {
+	f := &File{
+		path:  path,
+		codec: codec.JSON,
+	}
+	for _, opt := range options {
+		opt(f)
+	}
+	return f
+}
+
+// Load returns the token saved in the file. Tokens encoded with any
+// registered codec can be loaded, including plain JSON tokens.
+func (f *File) Load() (*oauth2.Token, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NotFound{Location: f.path}
+		}
+		return nil, err
+	}
+	token := new(oauth2.Token)
+	if err := codec.Decode(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Save writes token to the file. The token is written to a temporary file
+// that replaces the file once it is complete, so an interrupted save never
+// leaves a partial token behind. The file is only readable by its owner,
+// and its directory is created if it doesn't exist.
+func (f *File) Save(token *oauth2.Token) error {
+	data, err := codec.Encode(f.codec, token)
+	if err != nil {
+		return err
+	}
+
+	dir, name := filepath.Split(f.path)
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
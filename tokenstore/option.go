@@ -0,0 +1,15 @@
+package tokenstore
+
+import "github.com/scjalliance/drivestream/codec"
+
+// Option is a configuration option for a file store.
+type Option func(*File)
+
+// WithCodec causes the file store to encode tokens with c. Use an
+// encrypted codec to protect the token at rest. The codec must be
+// registered for the token to be loaded again.
+func WithCodec(c codec.Codec) Option {
+	return func(f *File) {
+		f.codec = c
+	}
+}
@@ -0,0 +1,49 @@
+package tokenstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenSource returns a token source that starts with token and refreshes
+// it with config when it expires. Each refreshed token is saved to store
+// before it is used.
+func TokenSource(ctx context.Context, config *oauth2.Config, store Store, token *oauth2.Token) oauth2.TokenSource {
+	return &savingSource{
+		source: config.TokenSource(ctx, token),
+		store:  store,
+		last:   token,
+	}
+}
+
+// savingSource saves the tokens returned by source whenever they change.
+type savingSource struct {
+	source oauth2.TokenSource
+	store  Store
+
+	mutex sync.Mutex
+	last  *oauth2.Token
+}
+
+// Token returns a valid token, saving it to the store if it was refreshed.
+func (s *savingSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.last != nil && token.AccessToken == s.last.AccessToken && token.RefreshToken == s.last.RefreshToken {
+		return token, nil
+	}
+	if err := s.store.Save(token); err != nil {
+		return nil, fmt.Errorf("failed to save refreshed token: %v", err)
+	}
+	s.last = token
+	return token, nil
+}
@@ -0,0 +1,13 @@
+package tokenstore
+
+import "golang.org/x/oauth2"
+
+// Store loads and saves an OAuth2 token.
+type Store interface {
+	// Load returns the saved token. It returns NotFound if no token has
+	// been saved.
+	Load() (*oauth2.Token, error)
+
+	// Save replaces the saved token with token.
+	Save(token *oauth2.Token) error
+}